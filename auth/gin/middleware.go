@@ -0,0 +1,57 @@
+package gin
+
+import (
+    "net/http"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/golobby/container"
+    "github.com/pkg/errors"
+    log "github.com/sirupsen/logrus"
+
+    "github.com/chudoyoudo/remember-cards/auth"
+)
+
+const (
+    userIdKey    = "auth.userId"
+    bearerPrefix = "Bearer "
+)
+
+// Middleware проверяет bearer токен из заголовка Authorization
+// и сохраняет в контексте запроса id пользователя
+func Middleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        header := c.GetHeader("Authorization")
+        if !strings.HasPrefix(header, bearerPrefix) {
+            c.AbortWithStatus(http.StatusUnauthorized)
+            return
+        }
+
+        token := strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix))
+        userId, err := getTokens().Parse(token)
+        if err != nil {
+            log.Debug(errors.Wrap(err, "Can't authenticate request"))
+            c.AbortWithStatus(http.StatusUnauthorized)
+            return
+        }
+
+        c.Set(userIdKey, userId)
+        c.Next()
+    }
+}
+
+// Метод возвращает id пользователя, сохраненный Middleware
+func UserId(c *gin.Context) (uint64, bool) {
+    value, found := c.Get(userIdKey)
+    if !found {
+        return 0, false
+    }
+    userId, ok := value.(uint64)
+    return userId, ok && userId != 0
+}
+
+func getTokens() auth.Tokens {
+    var t auth.Tokens
+    container.Make(&t)
+    return t
+}
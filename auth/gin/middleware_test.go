@@ -0,0 +1,67 @@
+package gin
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/golobby/container"
+    "github.com/stretchr/testify/assert"
+
+    "github.com/chudoyoudo/remember-cards/auth"
+)
+
+func init() {
+    gin.SetMode(gin.TestMode)
+    container.Singleton(func() auth.Tokens {
+        return auth.NewTokens([]byte("key"), time.Hour)
+    })
+}
+
+func serve(header string) (*httptest.ResponseRecorder, uint64) {
+    var userId uint64
+    r := gin.New()
+    r.GET("/", Middleware(), func(c *gin.Context) {
+        userId, _ = UserId(c)
+        c.Status(http.StatusOK)
+    })
+
+    req := httptest.NewRequest(http.MethodGet, "/", nil)
+    if header != "" {
+        req.Header.Set("Authorization", header)
+    }
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+    return w, userId
+}
+
+func Test_middleware_when_token_is_valid_user_id_is_set(t *testing.T) {
+    token, _ := getTokens().Issue(42)
+
+    w, userId := serve("Bearer " + token)
+
+    assert.Equal(t, http.StatusOK, w.Code, "Запрос с валидным токеном должен быть пропущен")
+    assert.Equal(t, uint64(42), userId, "В контексте должен быть id пользователя из токена")
+}
+
+func Test_middleware_when_header_is_empty_result_status_is_unauthorized(t *testing.T) {
+    w, _ := serve("")
+
+    assert.Equal(t, http.StatusUnauthorized, w.Code, "Запрос без токена должен быть отклонен")
+}
+
+func Test_middleware_when_token_is_invalid_result_status_is_unauthorized(t *testing.T) {
+    w, _ := serve("Bearer garbage")
+
+    assert.Equal(t, http.StatusUnauthorized, w.Code, "Запрос с невалидным токеном должен быть отклонен")
+}
+
+func Test_user_id_when_middleware_not_used_result_not_found(t *testing.T) {
+    c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+    _, found := UserId(c)
+
+    assert.False(t, found, "Без middleware пользователь не должен определяться")
+}
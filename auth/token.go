@@ -0,0 +1,76 @@
+package auth
+
+import (
+    "strconv"
+    "time"
+
+    "github.com/golang-jwt/jwt/v4"
+    "github.com/pkg/errors"
+)
+
+var ErrInvalidToken = errors.New("Invalid token")
+
+// Выпуск и проверка токенов доступа
+type Tokens interface {
+    Issue(userId uint64) (string, error)
+    Parse(token string) (userId uint64, err error)
+}
+
+// Метод создает JWT токены, подписанные ключом key по алгоритму HS256
+func NewTokens(key []byte, ttl time.Duration) Tokens {
+    return &jwtTokens{
+        key: key,
+        ttl: ttl,
+    }
+}
+
+type jwtTokens struct {
+    key []byte
+    ttl time.Duration
+    now time.Time
+}
+
+func (t *jwtTokens) Issue(userId uint64) (string, error) {
+    now := t.getNow()
+    claims := jwt.RegisteredClaims{
+        Subject:   strconv.FormatUint(userId, 10),
+        IssuedAt:  jwt.NewNumericDate(now),
+        ExpiresAt: jwt.NewNumericDate(now.Add(t.ttl)),
+    }
+
+    token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.key)
+    if err != nil {
+        return "", errors.Wrapf(err, "Can't sign token for user %d", userId)
+    }
+    return token, nil
+}
+
+func (t *jwtTokens) Parse(token string) (uint64, error) {
+    claims := &jwt.RegisteredClaims{}
+    parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+        if token.Method != jwt.SigningMethodHS256 {
+            return nil, errors.Errorf("Unexpected signing method %v", token.Header["alg"])
+        }
+        return t.key, nil
+    })
+    if err != nil {
+        return 0, errors.Wrapf(ErrInvalidToken, "Can't parse token: %s", err)
+    }
+    if !parsed.Valid {
+        return 0, ErrInvalidToken
+    }
+
+    userId, err := strconv.ParseUint(claims.Subject, 10, 64)
+    if err != nil || userId == 0 {
+        return 0, errors.Wrapf(ErrInvalidToken, "Wrong token subject %q", claims.Subject)
+    }
+    return userId, nil
+}
+
+func (t *jwtTokens) getNow() time.Time {
+    var emptyTime time.Time
+    if t.now == emptyTime {
+        return time.Now()
+    }
+    return t.now
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func Test_tokens_parse_return_user_id_from_issued_token(t *testing.T) {
+    tokens := NewTokens([]byte("key"), time.Hour)
+
+    token, errIssue := tokens.Issue(42)
+    userId, errParse := tokens.Parse(token)
+
+    require.Nil(t, errIssue, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errParse, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, uint64(42), userId, "Id пользователя должен совпадать с тем, для которого выпущен токен")
+}
+
+func Test_tokens_parse_when_token_signed_with_other_key_result_error_is_invalid_token(t *testing.T) {
+    token, _ := NewTokens([]byte("other"), time.Hour).Issue(42)
+
+    _, errResult := NewTokens([]byte("key"), time.Hour).Parse(token)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    assert.ErrorIs(t, errResult, ErrInvalidToken, "Возвращаемая ошибка должна быть ErrInvalidToken")
+}
+
+func Test_tokens_parse_when_token_expired_result_error_is_invalid_token(t *testing.T) {
+    tokens := &jwtTokens{
+        key: []byte("key"),
+        ttl: time.Hour,
+        now: time.Now().Add(-time.Hour * 2),
+    }
+    token, _ := tokens.Issue(42)
+
+    _, errResult := tokens.Parse(token)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    assert.ErrorIs(t, errResult, ErrInvalidToken, "Возвращаемая ошибка должна быть ErrInvalidToken")
+}
+
+func Test_tokens_parse_when_token_is_garbage_result_error_is_invalid_token(t *testing.T) {
+    _, errResult := NewTokens([]byte("key"), time.Hour).Parse("garbage")
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    assert.ErrorIs(t, errResult, ErrInvalidToken, "Возвращаемая ошибка должна быть ErrInvalidToken")
+}
@@ -0,0 +1,96 @@
+package connection
+
+import (
+	"database/sql"
+
+	gorm "github.com/chudoyoudo/gorm-interface"
+	gorm_db "gorm.io/gorm"
+)
+
+// Соединение поверх заданного *gorm.DB, например открытой транзакции или db с контекстом запроса.
+// В отличие от gorm.NewConnection каждый вызов возвращает новое соединение,
+// поэтому условия разных запросов одного dao не смешиваются
+type dbConnection struct {
+	db *gorm_db.DB
+}
+
+// Dao передают db с контекстом запроса, поэтому отмена запроса клиента прерывает запросы соединения
+func New(db *gorm_db.DB) gorm.Connection {
+	return &dbConnection{db: db}
+}
+
+func (c *dbConnection) Create(value interface{}) gorm.Connection {
+	return &dbConnection{db: c.db.Create(value)}
+}
+
+func (c *dbConnection) Save(value interface{}) gorm.Connection {
+	return &dbConnection{db: c.db.Save(value)}
+}
+
+func (c *dbConnection) Model(value interface{}) gorm.Connection {
+	return &dbConnection{db: c.db.Model(value)}
+}
+
+func (c *dbConnection) Updates(values interface{}) gorm.Connection {
+	return &dbConnection{db: c.db.Updates(values)}
+}
+
+func (c *dbConnection) First(dest interface{}, conds ...interface{}) gorm.Connection {
+	return &dbConnection{db: c.db.First(dest, conds...)}
+}
+
+func (c *dbConnection) Last(dest interface{}, conds ...interface{}) gorm.Connection {
+	return &dbConnection{db: c.db.Last(dest, conds...)}
+}
+
+func (c *dbConnection) Find(dest interface{}, conds ...interface{}) gorm.Connection {
+	return &dbConnection{db: c.db.Find(dest, conds...)}
+}
+
+func (c *dbConnection) Order(value interface{}) gorm.Connection {
+	return &dbConnection{db: c.db.Order(value)}
+}
+
+func (c *dbConnection) Limit(limit int) gorm.Connection {
+	return &dbConnection{db: c.db.Limit(limit)}
+}
+
+func (c *dbConnection) Offset(offset int) gorm.Connection {
+	return &dbConnection{db: c.db.Offset(offset)}
+}
+
+func (c *dbConnection) Delete(value interface{}, conds ...interface{}) gorm.Connection {
+	return &dbConnection{db: c.db.Delete(value, conds...)}
+}
+
+func (c *dbConnection) Count(count *int64) gorm.Connection {
+	return &dbConnection{db: c.db.Count(count)}
+}
+
+func (c *dbConnection) Transaction(fc func(tx *gorm_db.DB) error, opts ...*sql.TxOptions) error {
+	return c.db.Transaction(fc, opts...)
+}
+
+func (c *dbConnection) Begin(opts ...*sql.TxOptions) gorm.Connection {
+	return &dbConnection{db: c.db.Begin(opts...)}
+}
+
+func (c *dbConnection) Commit() gorm.Connection {
+	return &dbConnection{db: c.db.Commit()}
+}
+
+func (c *dbConnection) Rollback() gorm.Connection {
+	return &dbConnection{db: c.db.Rollback()}
+}
+
+func (c *dbConnection) Exec(sql string, values ...interface{}) gorm.Connection {
+	return &dbConnection{db: c.db.Exec(sql, values...)}
+}
+
+func (c *dbConnection) Error() error {
+	return c.db.Error
+}
+
+func (c *dbConnection) RowsAffected() int64 {
+	return c.db.RowsAffected
+}
@@ -0,0 +1,70 @@
+package connection
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "gorm.io/driver/sqlite"
+    gorm_db "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+)
+
+type record struct {
+    ID     uint64
+    UserId uint64
+}
+
+func getTestDb(t *testing.T) *gorm_db.DB {
+    db, err := gorm_db.Open(sqlite.Open("file::memory:"), &gorm_db.Config{Logger: logger.Discard})
+    require.Nil(t, err, "Не удалось открыть тестовую базу")
+    // У каждого соединения с памятью своя база, поэтому пул ограничен одним соединением
+    sqlDb, err := db.DB()
+    require.Nil(t, err, "Не удалось получить соединение тестовой базы")
+    sqlDb.SetMaxOpenConns(1)
+    require.Nil(t, db.AutoMigrate(&record{}), "Не удалось создать тестовую таблицу")
+    return db
+}
+
+func Test_db_connection_conditions_of_previous_query_are_not_applied_to_next_query(t *testing.T) {
+    db := getTestDb(t)
+    db.Create(&[]record{{UserId: 1}, {UserId: 2}})
+    c := New(db)
+
+    first := []record{}
+    errFirst := c.Limit(1).Find(&first, map[string]interface{}{"user_id": 1}).Error()
+    all := []record{}
+    errAll := c.Find(&all).Error()
+
+    require.Nil(t, errFirst, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errAll, "Возвращаемая ошибка должна быть пустой")
+    assert.Len(t, first, 1, "Первый запрос должен вернуть записи по своим условиям")
+    assert.Len(t, all, 2, "Второй запрос не должен наследовать условия первого")
+}
+
+func Test_db_connection_return_error_and_rows_affected_of_query(t *testing.T) {
+    db := getTestDb(t)
+    r := &record{UserId: 1}
+    db.Create(r)
+    c := New(db)
+
+    result := c.Model(r).Updates(map[string]interface{}{"user_id": 3})
+
+    assert.Nil(t, result.Error(), "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, int64(1), result.RowsAffected(), "Количество измененных строк неверное")
+}
+
+func Test_db_connection_when_context_is_canceled_query_is_not_executed(t *testing.T) {
+    db := getTestDb(t)
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    c := New(db.WithContext(ctx))
+
+    errResult := c.Create(&record{UserId: 1}).Error()
+
+    var count int64
+    db.Model(&record{}).Count(&count)
+    assert.ErrorIs(t, errResult, context.Canceled, "Возвращаемая ошибка должна быть отменой контекста")
+    assert.Equal(t, int64(0), count, "Запрос с отмененным контекстом не должен выполниться")
+}
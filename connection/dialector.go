@@ -0,0 +1,23 @@
+package connection
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	gorm_db "gorm.io/gorm"
+)
+
+var ErrUnknownDialect = errors.New("Unknown database dialect")
+
+// Метод возвращает диалект gorm по имени: postgres или sqlite.
+// Возможности, которых нет в SQLite, например полнотекстовый поиск, dao заменяют более простыми.
+// Время в запросах диалекта всегда передается в UTC
+func NewDialector(name, dsn string) (gorm_db.Dialector, error) {
+	switch name {
+	case "postgres":
+		return utcDialector{postgres.Open(dsn)}, nil
+	case "sqlite":
+		return utcDialector{sqlite.Open(dsn)}, nil
+	}
+	return nil, errors.Wrapf(ErrUnknownDialect, "Dialect %s", name)
+}
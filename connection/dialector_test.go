@@ -0,0 +1,24 @@
+package connection
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func Test_new_dialector_return_dialector_by_name(t *testing.T) {
+    postgres, errPostgres := NewDialector("postgres", "host=localhost")
+    sqlite, errSqlite := NewDialector("sqlite", "file::memory:")
+
+    require.Nil(t, errPostgres, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errSqlite, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, "postgres", postgres.Name(), "Диалект postgres неверный")
+    assert.Equal(t, "sqlite", sqlite.Name(), "Диалект sqlite неверный")
+}
+
+func Test_new_dialector_when_name_is_unknown_result_error_is_unknown_dialect(t *testing.T) {
+    _, errResult := NewDialector("mysql", "")
+
+    assert.ErrorIs(t, errResult, ErrUnknownDialect, "Возвращаемая ошибка должна быть ErrUnknownDialect")
+}
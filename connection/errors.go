@@ -0,0 +1,25 @@
+package connection
+
+import (
+	"github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+)
+
+// Код SQLSTATE нарушения уникального индекса в Postgres
+const pgUniqueViolation = "23505"
+
+// Метод проверяет, что запись нарушила уникальный индекс или первичный ключ.
+// Ошибка Postgres проверяется по коду SQLSTATE, ошибка sqlite по расширенному коду
+func IsUniqueViolation(err error) bool {
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		return pgErr.SQLState() == pgUniqueViolation
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+			sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+	return false
+}
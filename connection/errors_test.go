@@ -0,0 +1,23 @@
+package connection
+
+import (
+    "testing"
+
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func Test_is_unique_violation_when_record_is_duplicated_result_is_true(t *testing.T) {
+    db := getTestDb(t)
+    require.Nil(t, db.Create(&record{ID: 1}).Error)
+
+    err := db.Create(&record{ID: 1}).Error
+
+    require.NotNil(t, err, "Повторная запись должна нарушать первичный ключ")
+    assert.True(t, IsUniqueViolation(errors.Wrap(err, "Wrapped")), "Нарушение первичного ключа должно распознаваться и в обернутой ошибке")
+}
+
+func Test_is_unique_violation_when_error_is_other_result_is_false(t *testing.T) {
+    assert.False(t, IsUniqueViolation(errors.New("Other error")), "Другая ошибка не должна считаться нарушением уникальности")
+}
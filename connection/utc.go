@@ -0,0 +1,106 @@
+package connection
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/pkg/errors"
+	gorm_db "gorm.io/gorm"
+)
+
+var errNoTransactions = errors.New("Connection pool doesn't support transactions")
+
+// Диалект, который передает в базу время только в UTC.
+// SQLite хранит время текстом со смещением пояса и сравнивает его как строки, поэтому
+// время с разными смещениями, например время сервера и время из импорта, нельзя сравнивать
+// в условиях вида repeatTime <= ?. Все запросы dao идут через пул диалекта, и время
+// приводится к UTC здесь, а не в каждом месте, где оно появляется
+type utcDialector struct {
+	gorm_db.Dialector
+}
+
+func (d utcDialector) Initialize(db *gorm_db.DB) error {
+	err := d.Dialector.Initialize(db)
+	if err != nil {
+		return err
+	}
+	db.ConnPool = &utcPool{pool: db.ConnPool}
+	return nil
+}
+
+type utcPool struct {
+	pool gorm_db.ConnPool
+}
+
+func (p *utcPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.pool.PrepareContext(ctx, query)
+}
+
+func (p *utcPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.pool.ExecContext(ctx, query, utcArgs(args)...)
+}
+
+func (p *utcPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.pool.QueryContext(ctx, query, utcArgs(args)...)
+}
+
+func (p *utcPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.pool.QueryRowContext(ctx, query, utcArgs(args)...)
+}
+
+// Транзакция тоже оборачивается, чтобы время в ее запросах приводилось к UTC
+func (p *utcPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm_db.ConnPool, error) {
+	beginner, ok := p.pool.(gorm_db.TxBeginner)
+	if !ok {
+		return nil, errNoTransactions
+	}
+
+	tx, err := beginner.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &utcTx{utcPool: utcPool{pool: tx}, tx: tx}, nil
+}
+
+// gorm проверяет соединение при открытии, только если пул умеет Ping
+func (p *utcPool) Ping() error {
+	if pinger, ok := p.pool.(interface{ Ping() error }); ok {
+		return pinger.Ping()
+	}
+	return nil
+}
+
+type utcTx struct {
+	utcPool
+	tx *sql.Tx
+}
+
+func (t *utcTx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *utcTx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// Время может прийти и значением, например gorm.DeletedAt, поэтому проверяется
+// и результат driver.Valuer
+func utcArgs(args []interface{}) []interface{} {
+	result := make([]interface{}, len(args))
+	for i, arg := range args {
+		result[i] = arg
+		if valuer, ok := arg.(driver.Valuer); ok {
+			value, err := valuer.Value()
+			if err != nil {
+				continue
+			}
+			arg = value
+		}
+		if t, ok := arg.(time.Time); ok {
+			result[i] = t.UTC()
+		}
+	}
+	return result
+}
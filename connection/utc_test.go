@@ -0,0 +1,64 @@
+package connection
+
+import (
+    "database/sql"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "gorm.io/driver/sqlite"
+    gorm_db "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+)
+
+type timedRecord struct {
+    ID uint64
+    At time.Time
+}
+
+func getTestUtcDb(t *testing.T) *gorm_db.DB {
+    sqlDb, err := sql.Open(sqlite.DriverName, "file::memory:")
+    require.Nil(t, err, "Не удалось открыть тестовую базу")
+    // У каждого соединения с памятью своя база, поэтому пул ограничен одним соединением
+    sqlDb.SetMaxOpenConns(1)
+
+    db, err := gorm_db.Open(utcDialector{&sqlite.Dialector{Conn: sqlDb}}, &gorm_db.Config{Logger: logger.Discard})
+    require.Nil(t, err, "Не удалось открыть тестовую базу")
+    require.Nil(t, db.AutoMigrate(&timedRecord{}), "Не удалось создать тестовую таблицу")
+    return db
+}
+
+func Test_utc_dialector_store_time_in_utc(t *testing.T) {
+    db := getTestUtcDb(t)
+    at := time.Date(2021, 3, 1, 13, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+    require.Nil(t, db.Create(&timedRecord{At: at}).Error, "Возвращаемая ошибка должна быть пустой")
+
+    var stored string
+    require.Nil(t, db.Raw("SELECT CAST(at AS TEXT) FROM timed_records").Row().Scan(&stored))
+    assert.Equal(t, "2021-03-01 10:00:00+00:00", stored, "Время должно сохраняться в UTC")
+}
+
+func Test_utc_dialector_compare_time_with_offset_in_transaction(t *testing.T) {
+    db := getTestUtcDb(t)
+    require.Nil(t, db.Create(&timedRecord{At: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)}).Error)
+    // 12:00 по Москве раньше 10:00 UTC, хотя строкой со смещением выглядит позже
+    before := time.Date(2021, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+    found := []timedRecord{}
+    err := db.Transaction(func(tx *gorm_db.DB) error {
+        return tx.Where("at <= ?", before).Find(&found).Error
+    })
+
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    assert.Empty(t, found, "Время должно сравниваться с учетом смещения пояса")
+}
+
+func Test_utc_args_convert_time_and_time_valuer_to_utc(t *testing.T) {
+    at := time.Date(2021, 3, 1, 13, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+    result := utcArgs([]interface{}{at, gorm_db.DeletedAt{Time: at, Valid: true}, gorm_db.DeletedAt{}, "text", 1})
+
+    assert.Equal(t, []interface{}{at.UTC(), at.UTC(), gorm_db.DeletedAt{}, "text", 1}, result, "Время должно приводиться к UTC, остальные значения не меняться")
+}
@@ -0,0 +1,23 @@
+package groups
+
+import (
+    "context"
+    "time"
+)
+
+type Dao interface {
+    Create(ctx context.Context, g *Group) error
+    Update(ctx context.Context, g *Group, fields []string) error
+    // Метод переносит в корзину группы, подходящие под условия
+    Delete(ctx context.Context, conds ...interface{}) error
+    Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]Group, more bool, err error)
+    // Метод ищет группы, подходящие под условия, и среди действующих, и в корзине
+    FindWithDeleted(ctx context.Context, conds *map[string]interface{}) (*[]Group, error)
+    // Метод возвращает из корзины группы, подходящие под условия
+    Restore(ctx context.Context, conds map[string]interface{}) error
+    // Метод окончательно удаляет группы, перенесенные в корзину раньше deletedBefore,
+    // и возвращает количество удаленных групп
+    Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+    // Метод окончательно удаляет группы, подходящие под условия, минуя корзину
+    Remove(ctx context.Context, conds map[string]interface{}) error
+}
@@ -0,0 +1,320 @@
+package gin
+
+import (
+    "context"
+    "net/http"
+    "strconv"
+
+    rest_api_response_formatter "github.com/chudoyoudo/rest-api-response-formatter"
+    "github.com/gin-gonic/gin"
+    "github.com/golobby/container"
+    "github.com/pkg/errors"
+
+    auth_gin "github.com/chudoyoudo/remember-cards/auth/gin"
+    "github.com/chudoyoudo/remember-cards/groups"
+
+    errors_formatter "github.com/chudoyoudo/errors-formatter"
+    log "github.com/sirupsen/logrus"
+)
+
+func RegisterHandlers(r *gin.Engine, middleware ...gin.HandlerFunc) {
+    v1 := r.Group("/v1").Use(middleware...)
+    v1.POST("/group", addHandler)
+    v1.GET("/group", listHandler)
+    v1.PUT("/group/:id", correctHandler)
+    v1.GET("/group/:id", viewHandler)
+    v1.DELETE("/group/:id", deleteHandler)
+}
+
+// Группа без parentId становится корневой, а группа без scheduler повторяется алгоритмом по умолчанию
+type groupData struct {
+    Name      string  `json:"name" binding:"required,max=255"`
+    ParentId  *uint64 `json:"parentId"`
+    Scheduler string  `json:"scheduler" binding:"omitempty,oneof=ladder sm2 fsrs"`
+}
+
+func (d *groupData) Bind(g *groups.Group) {
+    g.Name = d.Name
+    g.ParentId = d.ParentId
+    g.Scheduler = d.Scheduler
+}
+
+// С parentId возвращаются только непосредственно вложенные в нее группы
+type filter struct {
+    ParentId uint64 `form:"parentId"`
+    Limit    int    `form:"limit"`
+    Offset   int    `form:"offset"`
+}
+
+func (f *filter) ToConds(userId uint64) *map[string]interface{} {
+    result := map[string]interface{}{
+        groups.GroupUserId: userId,
+    }
+
+    if f.ParentId != 0 {
+        result[groups.GroupParentId] = f.ParentId
+    }
+
+    return &result
+}
+
+func listHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    f := &filter{}
+    if err := c.ShouldBindQuery(f); err != nil {
+        errData := errors_formatter.FormatErrors(err)
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    conds := f.ToConds(userId)
+    order := &[]interface{}{"id desc"}
+    uc := getUsecase()
+    gl, more, err := getGroupList(c.Request.Context(), uc, conds, order, f.Limit, f.Offset)
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't get group list"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    response := rest_api_response_formatter.GetResponseData(gin.H{
+        "list": *gl,
+        "more": more,
+    }, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+func viewHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    id := getIdFomRequest(c)
+    uc := getUsecase()
+
+    g, err := getGroup(c.Request.Context(), uc, id, userId)
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't get group"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    if nil == g {
+        c.AbortWithStatus(http.StatusNotFound)
+        return
+    }
+
+    response := rest_api_response_formatter.GetResponseData(*g, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+func addHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    d := &groupData{}
+    if err := c.Bind(d); err != nil {
+        errData := errors_formatter.FormatErrors(err)
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    g := &groups.Group{UserId: userId}
+    d.Bind(g)
+    uc := getUsecase()
+    err := addGroup(c.Request.Context(), uc, g)
+    if isParentError(err) {
+        parentError(c, err)
+        return
+    }
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't add group"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    response := rest_api_response_formatter.GetResponseData(*g, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+func correctHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    id := getIdFomRequest(c)
+    uc := getUsecase()
+
+    g, err := getGroup(c.Request.Context(), uc, id, userId)
+    if err != nil {
+        log.Error(errors.Wrapf(err, "Can't get group by id %d", id))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    if nil == g {
+        c.AbortWithStatus(http.StatusNotFound)
+        return
+    }
+
+    d := &groupData{}
+    if err := c.Bind(d); err != nil {
+        errData := errors_formatter.FormatErrors(err)
+        response := rest_api_response_formatter.GetResponseData(struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    d.Bind(g)
+    err = correctGroup(c.Request.Context(), uc, g)
+    if isParentError(err) {
+        parentError(c, err)
+        return
+    }
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't correct group"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    response := rest_api_response_formatter.GetResponseData(*g, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+func deleteHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    id := getIdFomRequest(c)
+    uc := getUsecase()
+
+    g, err := getGroup(c.Request.Context(), uc, id, userId)
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't get group"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    if nil == g {
+        c.AbortWithStatus(http.StatusNotFound)
+        return
+    }
+
+    if err := deleteGroup(c.Request.Context(), uc, g); err != nil {
+        log.Error(errors.Wrap(err, "Can't delete group"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    response := rest_api_response_formatter.GetResponseData(&struct{}{}, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+func isParentError(err error) bool {
+    return errors.Is(err, groups.ErrParentNotFound) || errors.Is(err, groups.ErrParentCycle)
+}
+
+func parentError(c *gin.Context, err error) {
+    message := groups.ErrParentNotFound.Error()
+    if errors.Is(err, groups.ErrParentCycle) {
+        message = groups.ErrParentCycle.Error()
+    }
+
+    response := rest_api_response_formatter.GetResponseData(&struct{}{}, &map[string][]string{
+        "parentId": {message},
+    })
+    c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+}
+
+// Метод возвращает id пользователя, определенный по токену,
+// и прерывает запрос со статусом 401, если пользователь не определен
+func getUserIdFromRequest(c *gin.Context) (uint64, bool) {
+    userId, found := auth_gin.UserId(c)
+    if !found {
+        c.AbortWithStatus(http.StatusUnauthorized)
+        return 0, false
+    }
+    return userId, true
+}
+
+func getIdFomRequest(c *gin.Context) uint64 {
+    idFromUrl := c.Param("id")
+    result, err := strconv.ParseUint(idFromUrl, 10, 64)
+    if err != nil {
+        log.Error(errors.Wrapf(err, "Can't get group id from request [%v]", idFromUrl))
+        return 0
+    }
+    return result
+}
+
+func addGroup(ctx context.Context, uc groups.Usecase, g *groups.Group) error {
+    err := uc.Add(ctx, g)
+    if err != nil {
+        return errors.Wrapf(err, "Can't add group via usecase")
+    }
+    return nil
+}
+
+func correctGroup(ctx context.Context, uc groups.Usecase, g *groups.Group) error {
+    err := uc.Correct(ctx, g)
+    if err != nil {
+        return errors.Wrapf(err, "Can't correct group via usecase")
+    }
+    return nil
+}
+
+func deleteGroup(ctx context.Context, uc groups.Usecase, g *groups.Group) error {
+    err := uc.Delete(ctx, g)
+    if err != nil {
+        return errors.Wrapf(err, "Can't delete group by id %d via usecase", g.ID)
+    }
+    return nil
+}
+
+func getGroup(ctx context.Context, uc groups.Usecase, id, userId uint64) (*groups.Group, error) {
+    conds := &map[string]interface{}{"id": id, groups.GroupUserId: userId}
+    gl, _, err := uc.Find(ctx, conds, &[]interface{}{}, 1, 0)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't get group by id %d via usecase", id)
+    }
+
+    if len(*gl) == 0 {
+        return nil, nil
+    }
+
+    return &(*gl)[0], nil
+}
+
+func getGroupList(ctx context.Context, uc groups.Usecase, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]groups.Group, more bool, err error) {
+    gl, more, err := uc.Find(ctx, conds, order, limit, offset)
+    if err != nil {
+        return nil, false, errors.Wrapf(err, "Can't get group list by conds: %v order: %v limit: %d offset: %d via usecase", conds, order, limit, offset)
+    }
+
+    return gl, more, nil
+}
+
+func getNegotiate(data *gin.H) *gin.Negotiate {
+    return &gin.Negotiate{
+        Offered: []string{gin.MIMEJSON, gin.MIMEXML},
+        Data:    data,
+    }
+}
+
+func getUsecase() groups.Usecase {
+    var uc groups.Usecase
+    container.Make(&uc)
+    return uc
+}
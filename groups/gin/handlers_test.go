@@ -0,0 +1,261 @@
+package gin
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/groups"
+)
+
+type usecaseMock struct {
+    mock.Mock
+}
+
+func (m *usecaseMock) Add(ctx context.Context, g *groups.Group) error {
+    args := m.Called(ctx, g)
+    return args.Error(0)
+}
+
+func (m *usecaseMock) Correct(ctx context.Context, g *groups.Group) error {
+    args := m.Called(ctx, g)
+    return args.Error(0)
+}
+
+func (m *usecaseMock) Delete(ctx context.Context, g *groups.Group) error {
+    args := m.Called(ctx, g)
+    return args.Error(0)
+}
+
+func (m *usecaseMock) Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]groups.Group, more bool, err error) {
+    args := m.Called(ctx, conds, order, limit, offset)
+    return args.Get(0).(*[]groups.Group), args.Bool(1), args.Error(2)
+}
+
+func (m *usecaseMock) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+    args := m.Called(ctx, retention)
+    return args.Get(0).(int64), args.Error(1)
+}
+
+//-----------
+//--- Add ---
+//-----------
+
+func Test_handler_add_when_usecase_work_success_result_group_contains_data_from_usecase(t *testing.T) {
+    gIn := &groups.Group{}
+
+    uc := &usecaseMock{}
+    uc.On("Add", mock.Anything, gIn).Return(nil).Run(func(args mock.Arguments) {
+        gOut := args.Get(1).(*groups.Group)
+        gOut.ID = 1
+    })
+
+    errResult := addGroup(context.Background(), uc, gIn)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, uint64(1), gIn.ID, "Результирующий объект group должен иметь изменения, внесенные в него в usecase")
+}
+
+func Test_handler_add_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    gIn := &groups.Group{}
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Add", mock.Anything, gIn).Return(usecaseErr)
+
+    errResult := addGroup(context.Background(), uc, gIn)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+//---------------
+//--- Correct ---
+//---------------
+
+func Test_handler_correct_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    gIn := &groups.Group{}
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Correct", mock.Anything, gIn).Return(usecaseErr)
+
+    errResult := correctGroup(context.Background(), uc, gIn)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+//--------------
+//--- Delete ---
+//--------------
+
+func Test_handler_delete_usecase_calls_is_correct(t *testing.T) {
+    gIn := &groups.Group{ID: 1}
+
+    uc := &usecaseMock{}
+    uc.On("Delete", mock.Anything, gIn).Return(nil)
+
+    errResult := deleteGroup(context.Background(), uc, gIn)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    uc.AssertExpectations(t)
+}
+
+func Test_handler_delete_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    gIn := &groups.Group{ID: 1}
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Delete", mock.Anything, gIn).Return(usecaseErr)
+
+    errResult := deleteGroup(context.Background(), uc, gIn)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+//------------
+//--- View ---
+//------------
+
+func Test_handler_view_usecase_calls_is_correct(t *testing.T) {
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, &map[string]interface{}{"id": uint64(1), groups.GroupUserId: uint64(2)}, &[]interface{}{}, 1, 0).Return(&[]groups.Group{}, false, nil)
+
+    _, _ = getGroup(context.Background(), uc, 1, 2)
+
+    uc.AssertExpectations(t)
+}
+
+func Test_handler_view_when_group_not_found_result_group_is_empty(t *testing.T) {
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, mock.Anything, mock.Anything, 1, 0).Return(&[]groups.Group{}, false, nil)
+
+    gResult, errResult := getGroup(context.Background(), uc, 1, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Nil(t, gResult, "Результирующий объект group должен быть пустым")
+}
+
+func Test_handler_view_when_usecase_work_success_result_group_contains_data_from_usecase(t *testing.T) {
+    gExpected := groups.Group{ID: 1, UserId: 2, Name: "Name", CardCount: 3, DueCount: 1}
+
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, mock.Anything, mock.Anything, 1, 0).Return(&[]groups.Group{gExpected}, false, nil)
+
+    gResult, _ := getGroup(context.Background(), uc, 1, 2)
+
+    assert.Equal(t, gExpected, *gResult, "Результирующий объект group должен быть идентичен тому, что вернул usecase")
+}
+
+func Test_handler_view_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, mock.Anything, mock.Anything, 1, 0).Return(&[]groups.Group{}, false, usecaseErr)
+
+    _, errResult := getGroup(context.Background(), uc, 1, 2)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+//------------
+//--- List ---
+//------------
+
+func Test_handler_list_when_usecase_work_success_result_list_contains_data_from_usecase(t *testing.T) {
+    glExpected := &[]groups.Group{{ID: 1}, {ID: 2}}
+    conds := &map[string]interface{}{groups.GroupUserId: uint64(1)}
+    order := &[]interface{}{"id desc"}
+
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, conds, order, 10, 0).Return(glExpected, true, nil)
+
+    glResult, moreResult, errResult := getGroupList(context.Background(), uc, conds, order, 10, 0)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.True(t, moreResult, "Признак наличия следующей страницы должен быть взят из usecase")
+    assert.Equal(t, glExpected, glResult, "Результирующий список групп должен быть идентичен тому, что вернул usecase")
+}
+
+func Test_handler_list_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, mock.Anything, mock.Anything, 10, 0).Return(&[]groups.Group{}, false, usecaseErr)
+
+    _, _, errResult := getGroupList(context.Background(), uc, &map[string]interface{}{}, &[]interface{}{}, 10, 0)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+//------------
+//--- User ---
+//------------
+
+func Test_handler_get_user_id_when_user_not_authenticated_request_is_aborted_with_unauthorized(t *testing.T) {
+    w := httptest.NewRecorder()
+    c, _ := gin.CreateTestContext(w)
+
+    _, ok := getUserIdFromRequest(c)
+
+    assert.False(t, ok, "Пользователь не должен быть определен")
+    assert.True(t, c.IsAborted(), "Запрос должен быть прерван")
+    assert.Equal(t, http.StatusUnauthorized, w.Code, "Статус ответа должен быть 401")
+}
+
+//--------------
+//--- Filter ---
+//--------------
+
+func Test_filter_to_conds_retern_only_user_condition(t *testing.T) {
+    f := &filter{Limit: 10}
+
+    condsResult := f.ToConds(4)
+
+    assert.Equal(t, map[string]interface{}{groups.GroupUserId: uint64(4)}, *condsResult, "Результирующий список кондишенов неверный")
+}
+
+func Test_filter_to_conds_with_parent_retern_user_and_parent_conditions(t *testing.T) {
+    f := &filter{ParentId: 3}
+
+    condsResult := f.ToConds(4)
+
+    condsExpected := map[string]interface{}{groups.GroupUserId: uint64(4), groups.GroupParentId: uint64(3)}
+    assert.Equal(t, condsExpected, *condsResult, "Результирующий список кондишенов неверный")
+}
+
+//------------------
+//--- Group data ---
+//------------------
+
+func Test_group_data_bind_retern_correct_group_object(t *testing.T) {
+    parentId := uint64(3)
+    gIn := &groups.Group{ID: 1, UserId: 2}
+
+    d := &groupData{Name: "Name", ParentId: &parentId, Scheduler: "sm2"}
+    d.Bind(gIn)
+
+    assert.Equal(t, groups.Group{ID: 1, UserId: 2, ParentId: &parentId, Name: "Name", Scheduler: "sm2"}, *gIn, "Результирующий объект group неверный")
+}
+
+func Test_group_data_bind_without_parent_make_group_root(t *testing.T) {
+    parentId := uint64(3)
+    gIn := &groups.Group{ID: 1, ParentId: &parentId}
+
+    d := &groupData{Name: "Name"}
+    d.Bind(gIn)
+
+    assert.Nil(t, gIn.ParentId, "Группа без parentId должна стать корневой")
+}
@@ -0,0 +1,133 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	gorm "github.com/chudoyoudo/gorm-interface"
+	"github.com/golobby/container"
+	"github.com/pkg/errors"
+	gorm_db "gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/chudoyoudo/remember-cards/connection"
+	"github.com/chudoyoudo/remember-cards/groups"
+)
+
+type dao struct {
+	c  gorm.Connection
+	db *gorm_db.DB
+}
+
+func (dao *dao) Create(ctx context.Context, g *groups.Group) error {
+	result := dao.getConnection(ctx).Create(g)
+	err := result.Error()
+	if err != nil {
+		return errors.Wrapf(err, "Can't create group via connection %v", *g)
+	}
+	return nil
+}
+
+func (dao *dao) Update(ctx context.Context, g *groups.Group, fields []string) error {
+	data := g.ToMap(fields)
+	result := dao.getConnection(ctx).Model(g).Updates(*data)
+	err := result.Error()
+	if err != nil {
+		return errors.Wrapf(err, "Can't update group with id %d via connection %v", g.ID, data)
+	}
+	return nil
+}
+
+func (dao *dao) Delete(ctx context.Context, conds ...interface{}) error {
+	result := dao.getConnection(ctx).Delete(&groups.Group{}, conds...)
+	err := result.Error()
+	if err != nil {
+		return errors.Wrapf(err, "Can't delete group via connection by conds %v", conds)
+	}
+	return nil
+}
+
+func (dao *dao) Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]groups.Group, more bool, err error) {
+	gl := []groups.Group{}
+	c := dao.getConnection(ctx)
+
+	if limit > 0 {
+		c = c.Limit(limit + 1)
+	}
+
+	if offset > 0 {
+		c = c.Offset(offset)
+	}
+
+	if len(*order) > 0 {
+		for _, o := range *order {
+			c = c.Order(o)
+		}
+	}
+
+	result := c.Find(&gl, *conds)
+
+	err = result.Error()
+	if err != nil {
+		return &gl, false, errors.Wrapf(err, "Can't find group via connection by conds %v", conds)
+	}
+
+	more = false
+	if limit > 0 && len(gl) >= limit+1 {
+		gl = gl[:limit]
+		more = true
+	}
+
+	return &gl, more, nil
+}
+
+// Группы в корзине не видны через gorm.Connection, поэтому запросы к корзине
+// идут через db без мягкого удаления
+func (dao *dao) FindWithDeleted(ctx context.Context, conds *map[string]interface{}) (*[]groups.Group, error) {
+	gl := []groups.Group{}
+	result := dao.getDb(ctx).Unscoped().Where(*conds).Find(&gl)
+	if result.Error != nil {
+		return &gl, errors.Wrapf(result.Error, "Can't find groups with deleted via db by conds %v", *conds)
+	}
+	return &gl, nil
+}
+
+func (dao *dao) Restore(ctx context.Context, conds map[string]interface{}) error {
+	result := dao.getDb(ctx).Unscoped().Model(&groups.Group{}).Where(conds).Update("deletedAt", nil)
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "Can't restore groups via db by conds %v", conds)
+	}
+	return nil
+}
+
+func (dao *dao) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result := dao.getDb(ctx).Unscoped().
+		Where(clause.Lt{Column: clause.Column{Name: "deletedAt"}, Value: deletedBefore}).
+		Delete(&groups.Group{})
+	if result.Error != nil {
+		return 0, errors.Wrapf(result.Error, "Can't purge groups deleted before %v via db", deletedBefore)
+	}
+	return result.RowsAffected, nil
+}
+
+func (dao *dao) Remove(ctx context.Context, conds map[string]interface{}) error {
+	result := dao.getDb(ctx).Unscoped().Where(conds).Delete(&groups.Group{})
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "Can't remove groups via db by conds %v", conds)
+	}
+	return nil
+}
+
+func (dao *dao) getDb(ctx context.Context) *gorm_db.DB {
+	if dao.db == nil {
+		container.Make(&dao.db)
+	}
+	return dao.db.WithContext(ctx)
+}
+
+func (dao *dao) getConnection(ctx context.Context) gorm.Connection {
+	if dao.c == nil {
+		return connection.New(dao.getDb(ctx))
+	}
+	return dao.c
+}
@@ -0,0 +1,242 @@
+package gorm
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    gorm "github.com/chudoyoudo/gorm-interface"
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+    "github.com/stretchr/testify/require"
+    "gorm.io/driver/sqlite"
+    gorm_db "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+
+    "github.com/chudoyoudo/remember-cards/groups"
+)
+
+// ----------------
+// ---- Create ----
+// ----------------
+
+func Test_dao_create_when_connection_work_success_we_have_correct_result_group(t *testing.T) {
+    gIn := &groups.Group{}
+
+    c := &gorm.ConnectionMock{}
+    c.On("Create", gIn).Return(&gorm.ConnectionMock{}).Run(func(args mock.Arguments) {
+        gIn := args.Get(0).(*groups.Group)
+        gIn.ID = 1
+    })
+    dao := &dao{c: c}
+
+    errResult := dao.Create(context.Background(), gIn)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, uint64(1), gIn.ID, "Результируещий объект group должен содержать данные, пришедшие из connection")
+}
+
+func Test_dao_create_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
+    gIn := &groups.Group{}
+    connectionErr := errors.New("Connection mock error")
+
+    c := &gorm.ConnectionMock{}
+    c.On("Create", gIn).Return(&gorm.ConnectionMock{Err: connectionErr})
+    dao := &dao{c: c}
+
+    errResult := dao.Create(context.Background(), gIn)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
+}
+
+// ----------------
+// ---- Update ----
+// ----------------
+
+func Test_dao_update_connection_calls_is_correct(t *testing.T) {
+    gIn := &groups.Group{}
+    fields := []string{}
+
+    c := &gorm.ConnectionMock{}
+    c.On("Model", gIn).Return(c)
+    c.On("Updates", *gIn.ToMap(fields)).Return(c)
+    dao := &dao{c: c}
+
+    errResult := dao.Update(context.Background(), gIn, fields)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    c.AssertExpectations(t)
+}
+
+func Test_dao_update_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
+    gIn := &groups.Group{}
+    fields := []string{}
+    connectionErr := errors.New("Connection mock error")
+
+    c := &gorm.ConnectionMock{}
+    c.On("Model", gIn).Return(c)
+    c.On("Updates", *gIn.ToMap(fields)).Return(&gorm.ConnectionMock{Err: connectionErr})
+    dao := &dao{c: c}
+
+    errResult := dao.Update(context.Background(), gIn, fields)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
+}
+
+// ----------------
+// ---- Delete ----
+// ----------------
+
+func Test_dao_delete_connection_calls_is_correct(t *testing.T) {
+    conds := []interface{}{uint64(1)}
+
+    c := &gorm.ConnectionMock{}
+    c.On("Delete", &groups.Group{}, conds).Return(c)
+    dao := &dao{c: c}
+
+    errResult := dao.Delete(context.Background(), conds...)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    c.AssertExpectations(t)
+}
+
+func Test_dao_delete_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
+    conds := []interface{}{uint64(1)}
+    connectionErr := errors.New("Connection mock error")
+
+    c := &gorm.ConnectionMock{}
+    c.On("Delete", &groups.Group{}, conds).Return(&gorm.ConnectionMock{Err: connectionErr})
+    dao := &dao{c: c}
+
+    errResult := dao.Delete(context.Background(), conds...)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
+}
+
+// --------------
+// ---- Find ----
+// --------------
+
+func Test_dao_find_connection_calls_is_correct(t *testing.T) {
+    conds := &map[string]interface{}{groups.GroupUserId: uint64(1)}
+    order := &[]interface{}{"id desc"}
+    limit := 1
+    offset := 1
+
+    c := &gorm.ConnectionMock{}
+    c.On("Limit", limit+1).Return(c)
+    c.On("Offset", offset).Return(c)
+    c.On("Order", "id desc").Return(c)
+    c.On("Find", &[]groups.Group{}, []interface{}{*conds}).Return(c)
+    dao := &dao{c: c}
+
+    _, _, _ = dao.Find(context.Background(), conds, order, limit, offset)
+
+    c.AssertExpectations(t)
+}
+
+func Test_dao_find_when_we_have_more_then_limit_records_in_connection_result_more_is_true(t *testing.T) {
+    conds := &map[string]interface{}{}
+    order := &[]interface{}{}
+    limit := 1
+
+    c := &gorm.ConnectionMock{}
+    c.On("Limit", limit+1).Return(c)
+    c.On("Find", &[]groups.Group{}, []interface{}{*conds}).Return(&gorm.ConnectionMock{}).Run(func(args mock.Arguments) {
+        glOut := args.Get(0).(*[]groups.Group)
+        *glOut = append(*glOut, groups.Group{ID: 1}, groups.Group{ID: 2})
+    })
+    dao := &dao{c: c}
+
+    glResult, resultMore, _ := dao.Find(context.Background(), conds, order, limit, 0)
+
+    assert.Equal(t, true, resultMore, "Возвращаемый more флаг должно быть true")
+    assert.Equal(t, limit, len(*glResult), "Лишние объекты group, использовавшиеся для вычисления флага more, должны быть убраны из возвращаемого списка объектов")
+}
+
+func Test_dao_find_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
+    conds := &map[string]interface{}{}
+    order := &[]interface{}{}
+    connectionErr := errors.New("Connection mock error")
+
+    c := &gorm.ConnectionMock{}
+    c.On("Find", &[]groups.Group{}, []interface{}{*conds}).Return(&gorm.ConnectionMock{Err: connectionErr})
+    dao := &dao{c: c}
+
+    _, _, errResult := dao.Find(context.Background(), conds, order, 0, 0)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
+}
+
+// ---------------
+// ---- Trash ----
+// ---------------
+
+func getTestDb(t *testing.T) *gorm_db.DB {
+    db, err := gorm_db.Open(sqlite.Open("file::memory:"), &gorm_db.Config{Logger: logger.Discard})
+    require.Nil(t, err, "Не удалось открыть тестовую базу")
+    // У каждого соединения с памятью своя база, поэтому пул ограничен одним соединением
+    sqlDb, err := db.DB()
+    require.Nil(t, err, "Не удалось получить соединение тестовой базы")
+    sqlDb.SetMaxOpenConns(1)
+    require.Nil(t, db.AutoMigrate(&groups.Group{}), "Не удалось создать таблицу групп")
+    return db
+}
+
+func Test_dao_delete_move_groups_to_trash_and_restore_return_them(t *testing.T) {
+    db := getTestDb(t)
+    require.Nil(t, db.Create(&[]groups.Group{{UserId: 1}, {UserId: 1}}).Error)
+    dao := &dao{db: db}
+    conds := map[string]interface{}{"id": uint64(1)}
+
+    errDelete := dao.Delete(context.Background(), conds)
+    found, _, _ := dao.Find(context.Background(), &map[string]interface{}{}, &[]interface{}{}, 0, 0)
+    withDeleted, errWithDeleted := dao.FindWithDeleted(context.Background(), &conds)
+
+    require.Nil(t, errDelete, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errWithDeleted, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *found, 1, "Группа в корзине не должна находиться")
+    require.Len(t, *withDeleted, 1, "Группа в корзине должна находиться вместе с удаленными")
+    assert.True(t, (*withDeleted)[0].DeletedAt.Valid, "У группы в корзине должно быть время удаления")
+
+    errRestore := dao.Restore(context.Background(), conds)
+    found, _, _ = dao.Find(context.Background(), &map[string]interface{}{}, &[]interface{}{}, 0, 0)
+
+    require.Nil(t, errRestore, "Возвращаемая ошибка должна быть пустой")
+    assert.Len(t, *found, 2, "Восстановленная группа должна находиться")
+}
+
+func Test_dao_remove_delete_groups_without_trash(t *testing.T) {
+    db := getTestDb(t)
+    require.Nil(t, db.Create(&[]groups.Group{{UserId: 1}, {UserId: 1}}).Error)
+    dao := &dao{db: db}
+
+    errResult := dao.Remove(context.Background(), map[string]interface{}{"id": []uint64{1}})
+
+    withDeleted, _ := dao.FindWithDeleted(context.Background(), &map[string]interface{}{})
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *withDeleted, 1, "Удаленная группа не должна оставаться и в корзине")
+    assert.Equal(t, uint64(2), (*withDeleted)[0].ID, "Остальные группы должны остаться")
+}
+
+func Test_dao_purge_remove_only_groups_deleted_before_time(t *testing.T) {
+    db := getTestDb(t)
+    require.Nil(t, db.Create(&[]groups.Group{{UserId: 1}, {UserId: 1}}).Error)
+    dao := &dao{db: db}
+    require.Nil(t, dao.Delete(context.Background(), map[string]interface{}{"id": uint64(1)}))
+
+    countBefore, errBefore := dao.Purge(context.Background(), time.Now().Add(-time.Hour))
+    countAfter, errAfter := dao.Purge(context.Background(), time.Now().Add(time.Hour))
+
+    withDeleted, _ := dao.FindWithDeleted(context.Background(), &map[string]interface{}{})
+    require.Nil(t, errBefore, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errAfter, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, int64(0), countBefore, "Недавно удаленная группа не должна удаляться окончательно")
+    assert.Equal(t, int64(1), countAfter, "Должна удалиться только группа из корзины")
+    assert.Len(t, *withDeleted, 1, "Действующая группа должна остаться")
+}
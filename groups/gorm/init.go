@@ -0,0 +1,13 @@
+package gorm
+
+import (
+    "github.com/golobby/container"
+
+    "github.com/chudoyoudo/remember-cards/groups"
+)
+
+func init() {
+    container.Transient(func() groups.Dao {
+        return &dao{}
+    })
+}
@@ -0,0 +1,61 @@
+package groups
+
+import (
+    "github.com/pkg/errors"
+    "gorm.io/gorm"
+)
+
+const (
+    GroupUserId    = "userId"
+    GroupParentId  = "parentId"
+    groupName      = "name"
+    groupScheduler = "scheduler"
+)
+
+var (
+    ErrParentNotFound = errors.New("Parent group not found")
+    ErrParentCycle    = errors.New("Group can't be nested in itself or its descendants")
+)
+
+// Группы образуют дерево: у корневых групп ParentId пустой
+type Group struct {
+    ID       uint64  `json:"id" gorm:"primaryKey"`
+    UserId   uint64  `json:"userId" gorm:"column:userId;index"`
+    ParentId *uint64 `json:"parentId" gorm:"column:parentId;index"`
+    Name     string  `json:"name"`
+    // Алгоритм повторений вопросов группы. Пустое значение означает алгоритм по умолчанию,
+    // заданный переменной SCHEDULER
+    Scheduler string `json:"scheduler" gorm:"column:scheduler"`
+    // Удаленная группа лежит в корзине вместе со своими вопросами,
+    // и восстановление вопроса возвращает ее обратно
+    DeletedAt gorm.DeletedAt `json:"-" gorm:"column:deletedAt;index"`
+    // Количество вопросов в группе и вопросов к повторению, не хранятся в таблице групп
+    CardCount int64 `json:"cardCount" gorm:"-"`
+    DueCount  int64 `json:"dueCount" gorm:"-"`
+}
+
+func (g *Group) ToMap(fields []string) *map[string]interface{} {
+    if len(fields) > 0 {
+        result := map[string]interface{}{}
+        for _, field := range fields {
+            switch field {
+            case GroupUserId:
+                result[field] = g.UserId
+            case GroupParentId:
+                result[field] = g.ParentId
+            case groupName:
+                result[field] = g.Name
+            case groupScheduler:
+                result[field] = g.Scheduler
+            }
+        }
+        return &result
+    }
+
+    return &map[string]interface{}{
+        GroupUserId:    g.UserId,
+        GroupParentId:  g.ParentId,
+        groupName:      g.Name,
+        groupScheduler: g.Scheduler,
+    }
+}
@@ -0,0 +1,31 @@
+package groups
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_group_to_map_return_all_fields_if_fields_list_in_params_is_empty(t *testing.T) {
+    parentId := uint64(5)
+    g := &Group{ID: 1, UserId: 2, ParentId: &parentId, Name: "Name", Scheduler: "sm2", CardCount: 3, DueCount: 4}
+
+    expectedMap := map[string]interface{}{
+        GroupUserId:    uint64(2),
+        GroupParentId:  &parentId,
+        groupName:      "Name",
+        groupScheduler: "sm2",
+    }
+
+    assert.Equal(t, expectedMap, *g.ToMap([]string{}), "Результирующая мапа должна содержать все сохраняемые поля группы")
+}
+
+func Test_group_to_map_return_only_fields_from_params(t *testing.T) {
+    g := &Group{ID: 1, UserId: 2, Name: "Name"}
+
+    expectedMap := map[string]interface{}{
+        groupName: "Name",
+    }
+
+    assert.Equal(t, expectedMap, *g.ToMap([]string{groupName}), "Результирующая мапа должна содержать только переданные поля")
+}
@@ -0,0 +1,16 @@
+package groups
+
+import (
+    "github.com/golobby/container"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+func init() {
+    container.Transient(func() Usecase {
+        return &usecase{}
+    })
+    container.Transient(func() questions.Groups {
+        return &questionGroups{}
+    })
+}
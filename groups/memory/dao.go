@@ -0,0 +1,209 @@
+package memory
+
+import (
+    "context"
+    "sort"
+    "sync"
+    "time"
+
+    "github.com/pkg/errors"
+    "gorm.io/gorm"
+
+    "github.com/chudoyoudo/remember-cards/groups"
+    "github.com/chudoyoudo/remember-cards/memory"
+)
+
+// Группы хранятся копиями, поэтому изменения объекта после вызова dao не меняют сохраненные данные
+type dao struct {
+    mu     sync.Mutex
+    groups map[uint64]groups.Group
+    lastId uint64
+}
+
+func newDao() *dao {
+    return &dao{groups: map[uint64]groups.Group{}}
+}
+
+func (dao *dao) Create(ctx context.Context, g *groups.Group) error {
+    dao.mu.Lock()
+    defer dao.mu.Unlock()
+
+    dao.lastId++
+    g.ID = dao.lastId
+    dao.groups[g.ID] = copyGroup(*g)
+    return nil
+}
+
+func (dao *dao) Update(ctx context.Context, g *groups.Group, fields []string) error {
+    dao.mu.Lock()
+    defer dao.mu.Unlock()
+
+    stored, found := dao.groups[g.ID]
+    if !found || stored.DeletedAt.Valid {
+        return nil
+    }
+
+    err := memory.Assign(&stored, *g.ToMap(fields))
+    if err != nil {
+        return errors.Wrapf(err, "Can't update group with id %d", g.ID)
+    }
+    dao.groups[g.ID] = copyGroup(stored)
+    return nil
+}
+
+// Условия удаления передаются map, как в Find
+func (dao *dao) Delete(ctx context.Context, conds ...interface{}) error {
+    dao.mu.Lock()
+    defer dao.mu.Unlock()
+
+    matched := []uint64{}
+    for id, g := range dao.groups {
+        if g.DeletedAt.Valid {
+            continue
+        }
+
+        found := true
+        for _, cond := range conds {
+            m, ok := cond.(map[string]interface{})
+            if !ok {
+                return errors.Errorf("Can't delete group by conds %v: only map conds are supported", conds)
+            }
+
+            var err error
+            found, err = memory.Match(&g, m)
+            if err != nil {
+                return errors.Wrapf(err, "Can't match group by conds %v", conds)
+            }
+            if !found {
+                break
+            }
+        }
+        if found {
+            matched = append(matched, id)
+        }
+    }
+
+    deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+    for _, id := range matched {
+        g := dao.groups[id]
+        g.DeletedAt = deletedAt
+        dao.groups[id] = g
+    }
+    return nil
+}
+
+func (dao *dao) Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]groups.Group, more bool, err error) {
+    dao.mu.Lock()
+    defer dao.mu.Unlock()
+
+    active := []groups.Group{}
+    for _, g := range dao.sorted() {
+        if !g.DeletedAt.Valid {
+            active = append(active, g)
+        }
+    }
+
+    gl, err := match(active, *conds)
+    if err != nil {
+        return &[]groups.Group{}, false, err
+    }
+
+    err = memory.Sort(gl, *order)
+    if err != nil {
+        return &[]groups.Group{}, false, errors.Wrapf(err, "Can't sort groups by order %v", order)
+    }
+
+    from, to, more := memory.Page(len(gl), limit, offset)
+    gl = gl[from:to]
+    return &gl, more, nil
+}
+
+func (dao *dao) FindWithDeleted(ctx context.Context, conds *map[string]interface{}) (*[]groups.Group, error) {
+    dao.mu.Lock()
+    defer dao.mu.Unlock()
+
+    gl, err := match(dao.sorted(), *conds)
+    if err != nil {
+        return &[]groups.Group{}, err
+    }
+    return &gl, nil
+}
+
+func (dao *dao) Restore(ctx context.Context, conds map[string]interface{}) error {
+    dao.mu.Lock()
+    defer dao.mu.Unlock()
+
+    gl, err := match(dao.sorted(), conds)
+    if err != nil {
+        return err
+    }
+    for _, g := range gl {
+        g.DeletedAt = gorm.DeletedAt{}
+        dao.groups[g.ID] = g
+    }
+    return nil
+}
+
+func (dao *dao) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+    dao.mu.Lock()
+    defer dao.mu.Unlock()
+
+    count := int64(0)
+    for id, g := range dao.groups {
+        if g.DeletedAt.Valid && g.DeletedAt.Time.Before(deletedBefore) {
+            delete(dao.groups, id)
+            count++
+        }
+    }
+    return count, nil
+}
+
+func (dao *dao) Remove(ctx context.Context, conds map[string]interface{}) error {
+    dao.mu.Lock()
+    defer dao.mu.Unlock()
+
+    gl, err := match(dao.sorted(), conds)
+    if err != nil {
+        return err
+    }
+    for _, g := range gl {
+        delete(dao.groups, g.ID)
+    }
+    return nil
+}
+
+// Метод возвращает копии групп, подходящих под условия
+func match(gl []groups.Group, conds map[string]interface{}) ([]groups.Group, error) {
+    result := []groups.Group{}
+    for _, g := range gl {
+        found, err := memory.Match(&g, conds)
+        if err != nil {
+            return nil, errors.Wrapf(err, "Can't match group by conds %v", conds)
+        }
+        if found {
+            result = append(result, copyGroup(g))
+        }
+    }
+    return result, nil
+}
+
+// Метод возвращает группы по возрастанию id, как их без сортировки вернула бы база
+func (dao *dao) sorted() []groups.Group {
+    gl := make([]groups.Group, 0, len(dao.groups))
+    for _, g := range dao.groups {
+        gl = append(gl, g)
+    }
+    sort.Slice(gl, func(i, j int) bool {
+        return gl[i].ID < gl[j].ID
+    })
+    return gl
+}
+
+// Копия группы без общего с ней указателя на родительскую группу
+func copyGroup(g groups.Group) groups.Group {
+    if g.ParentId != nil {
+        parentId := *g.ParentId
+        g.ParentId = &parentId
+    }
+    return g
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/groups"
+)
+
+func getTestDao(t *testing.T, gl ...*groups.Group) *dao {
+    dao := newDao()
+    for _, g := range gl {
+        require.Nil(t, dao.Create(context.Background(), g), "Не удалось создать тестовую группу")
+    }
+    return dao
+}
+
+func Test_dao_create_set_id_and_save_copy_of_group(t *testing.T) {
+    parentId := uint64(1)
+    g := &groups.Group{UserId: 1, ParentId: &parentId, Name: "Old"}
+    dao := getTestDao(t, g)
+
+    g.Name = "New"
+    parentId = 5
+    list, _, _ := dao.Find(context.Background(), &map[string]interface{}{}, &[]interface{}{}, 0, 0)
+
+    require.Len(t, *list, 1, "Группа должна сохраниться")
+    assert.Equal(t, uint64(1), g.ID, "Группа должна получить id")
+    assert.Equal(t, "Old", (*list)[0].Name, "Изменение объекта после создания не должно менять сохраненную группу")
+    assert.Equal(t, uint64(1), *(*list)[0].ParentId, "Сохраненная группа не должна зависеть от указателя объекта")
+}
+
+func Test_dao_update_change_only_passed_fields(t *testing.T) {
+    g := &groups.Group{UserId: 1, Name: "Old"}
+    dao := getTestDao(t, g)
+
+    parentId := uint64(3)
+    errResult := dao.Update(context.Background(), &groups.Group{ID: g.ID, UserId: 2, ParentId: &parentId, Name: "New"}, []string{"name", groups.GroupParentId})
+
+    list, _, _ := dao.Find(context.Background(), &map[string]interface{}{}, &[]interface{}{}, 0, 0)
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, "New", (*list)[0].Name, "Переданное поле должно измениться")
+    assert.Equal(t, uint64(3), *(*list)[0].ParentId, "Переданное поле должно измениться")
+    assert.Equal(t, uint64(1), (*list)[0].UserId, "Не переданное поле не должно измениться")
+}
+
+func Test_dao_delete_remove_groups_by_conds(t *testing.T) {
+    dao := getTestDao(t, &groups.Group{UserId: 1}, &groups.Group{UserId: 1}, &groups.Group{UserId: 2})
+
+    errResult := dao.Delete(context.Background(), map[string]interface{}{"id": []uint64{1, 3}, groups.GroupUserId: uint64(1)})
+
+    list, _, _ := dao.Find(context.Background(), &map[string]interface{}{}, &[]interface{}{}, 0, 0)
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *list, 2, "Должна удалиться только группа, подходящая под все условия")
+    assert.Equal(t, []uint64{2, 3}, []uint64{(*list)[0].ID, (*list)[1].ID}, "Удалена не та группа")
+}
+
+func Test_dao_delete_move_groups_to_trash_and_restore_return_them(t *testing.T) {
+    dao := getTestDao(t, &groups.Group{UserId: 1}, &groups.Group{UserId: 1})
+    conds := map[string]interface{}{"id": uint64(1)}
+
+    errDelete := dao.Delete(context.Background(), conds)
+    found, _, _ := dao.Find(context.Background(), &map[string]interface{}{}, &[]interface{}{}, 0, 0)
+    withDeleted, errWithDeleted := dao.FindWithDeleted(context.Background(), &conds)
+
+    require.Nil(t, errDelete, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errWithDeleted, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *found, 1, "Группа в корзине не должна находиться")
+    require.Len(t, *withDeleted, 1, "Группа в корзине должна находиться вместе с удаленными")
+    assert.True(t, (*withDeleted)[0].DeletedAt.Valid, "У группы в корзине должно быть время удаления")
+
+    errRestore := dao.Restore(context.Background(), conds)
+    found, _, _ = dao.Find(context.Background(), &map[string]interface{}{}, &[]interface{}{}, 0, 0)
+
+    require.Nil(t, errRestore, "Возвращаемая ошибка должна быть пустой")
+    assert.Len(t, *found, 2, "Восстановленная группа должна находиться")
+}
+
+func Test_dao_remove_delete_groups_without_trash(t *testing.T) {
+    dao := getTestDao(t, &groups.Group{UserId: 1}, &groups.Group{UserId: 1})
+
+    errResult := dao.Remove(context.Background(), map[string]interface{}{"id": []uint64{1}})
+
+    withDeleted, _ := dao.FindWithDeleted(context.Background(), &map[string]interface{}{})
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *withDeleted, 1, "Удаленная группа не должна оставаться и в корзине")
+    assert.Equal(t, uint64(2), (*withDeleted)[0].ID, "Остальные группы должны остаться")
+}
+
+func Test_dao_purge_remove_only_groups_deleted_before_time(t *testing.T) {
+    dao := getTestDao(t, &groups.Group{UserId: 1}, &groups.Group{UserId: 1})
+    require.Nil(t, dao.Delete(context.Background(), map[string]interface{}{"id": uint64(1)}))
+
+    countBefore, errBefore := dao.Purge(context.Background(), time.Now().Add(-time.Hour))
+    countAfter, errAfter := dao.Purge(context.Background(), time.Now().Add(time.Hour))
+
+    withDeleted, _ := dao.FindWithDeleted(context.Background(), &map[string]interface{}{})
+    require.Nil(t, errBefore, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errAfter, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, int64(0), countBefore, "Недавно удаленная группа не должна удаляться окончательно")
+    assert.Equal(t, int64(1), countAfter, "Должна удалиться только группа из корзины")
+    assert.Len(t, *withDeleted, 1, "Действующая группа должна остаться")
+}
+
+func Test_dao_delete_when_conds_not_map_result_error_not_empty(t *testing.T) {
+    dao := getTestDao(t, &groups.Group{UserId: 1})
+
+    errResult := dao.Delete(context.Background(), 1)
+
+    assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
+
+func Test_dao_find_filter_by_parent_sort_and_paginate(t *testing.T) {
+    parentId := uint64(1)
+    dao := getTestDao(t,
+        &groups.Group{UserId: 1, Name: "Root"},
+        &groups.Group{UserId: 1, ParentId: &parentId, Name: "A"},
+        &groups.Group{UserId: 1, ParentId: &parentId, Name: "B"},
+        &groups.Group{UserId: 1, ParentId: &parentId, Name: "C"},
+    )
+
+    conds := &map[string]interface{}{groups.GroupUserId: uint64(1), groups.GroupParentId: []uint64{parentId}}
+    list, more, errResult := dao.Find(context.Background(), conds, &[]interface{}{"id desc"}, 2, 1)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *list, 2, "Количество групп на странице неверное")
+    assert.Equal(t, []string{"B", "A"}, []string{(*list)[0].Name, (*list)[1].Name}, "Группы отобраны или отсортированы неверно")
+    assert.False(t, more, "После страницы нет групп")
+}
+
+func Test_dao_find_when_column_not_found_result_error_not_empty(t *testing.T) {
+    dao := getTestDao(t, &groups.Group{UserId: 1})
+
+    _, _, errResult := dao.Find(context.Background(), &map[string]interface{}{"cardCount": 0}, &[]interface{}{}, 0, 0)
+
+    assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
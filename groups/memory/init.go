@@ -0,0 +1,15 @@
+package memory
+
+import (
+    "github.com/golobby/container"
+
+    "github.com/chudoyoudo/remember-cards/groups"
+)
+
+// Метод регистрирует в контейнере dao групп, которое хранит данные в памяти процесса
+func Register() {
+    d := newDao()
+    container.Singleton(func() groups.Dao {
+        return d
+    })
+}
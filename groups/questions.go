@@ -0,0 +1,267 @@
+package groups
+
+import (
+    "context"
+    "strings"
+
+    "github.com/golobby/container"
+    "github.com/pkg/errors"
+)
+
+// Реализация questions.Groups для проверки групп при добавлении и изменении вопросов
+type questionGroups struct {
+    dao Dao
+}
+
+func (qg *questionGroups) IsOwned(ctx context.Context, groupId, userId uint64) (bool, error) {
+    owned, err := isOwned(ctx, qg.getDao(), groupId, userId)
+    if err != nil {
+        return false, errors.Wrapf(err, "Can't check owner of group %d", groupId)
+    }
+    return owned, nil
+}
+
+func (qg *questionGroups) Descendants(ctx context.Context, groupIds []uint64, userId uint64) ([]uint64, error) {
+    ids, err := descendants(ctx, qg.getDao(), groupIds, userId)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't get descendants of groups %v", groupIds)
+    }
+    return ids, nil
+}
+
+func (qg *questionGroups) Names(ctx context.Context, groupIds []uint64, userId uint64) (map[uint64]string, error) {
+    byId, err := ancestors(ctx, qg.getDao(), groupIds, userId)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't get ancestors of groups %v", groupIds)
+    }
+
+    names := map[uint64]string{}
+    for _, id := range groupIds {
+        if _, found := byId[id]; found {
+            names[id] = path(byId, id)
+        }
+    }
+    return names, nil
+}
+
+// Метод загружает все группы пользователя и по одному разу создает
+// недостающие части каждого пути "Родитель::Группа".
+// Если группу создать не удалось, уже созданные группы удаляются
+func (qg *questionGroups) Ensure(ctx context.Context, names []string, userId uint64) (map[string]uint64, []uint64, error) {
+    dao := qg.getDao()
+    gl, _, err := dao.Find(ctx, &map[string]interface{}{GroupUserId: userId}, &[]interface{}{}, 0, 0)
+    if err != nil {
+        return nil, nil, errors.Wrapf(err, "Can't find groups of user %d via dao", userId)
+    }
+
+    byId := map[uint64]Group{}
+    for _, g := range *gl {
+        byId[g.ID] = g
+    }
+    byPath := map[string]uint64{}
+    for id := range byId {
+        byPath[path(byId, id)] = id
+    }
+
+    result := map[string]uint64{}
+    created := []uint64{}
+    for _, name := range names {
+        var parentId *uint64
+        prefix := []string{}
+        for _, part := range strings.Split(name, "::") {
+            prefix = append(prefix, strings.TrimSpace(part))
+            key := strings.Join(prefix, "::")
+
+            id, found := byPath[key]
+            if !found {
+                g := &Group{UserId: userId, ParentId: parentId, Name: prefix[len(prefix)-1]}
+                err := dao.Create(ctx, g)
+                if err != nil {
+                    err = errors.Wrapf(err, "Can't create group %s via dao", key)
+                    if removeErr := qg.Remove(ctx, created, userId); removeErr != nil {
+                        return nil, nil, errors.Wrapf(err, "Can't remove created groups: %v", removeErr)
+                    }
+                    return nil, nil, err
+                }
+                id = g.ID
+                created = append(created, id)
+                byPath[key] = id
+            }
+            parentId = &id
+        }
+        result[name] = *parentId
+    }
+    return result, created, nil
+}
+
+func (qg *questionGroups) Remove(ctx context.Context, groupIds []uint64, userId uint64) error {
+    if len(groupIds) == 0 {
+        return nil
+    }
+    err := qg.getDao().Remove(ctx, map[string]interface{}{"id": groupIds, GroupUserId: userId})
+    if err != nil {
+        return errors.Wrapf(err, "Can't remove groups %v via dao", groupIds)
+    }
+    return nil
+}
+
+// Для ненайденной группы возвращается алгоритм по умолчанию: вопрос мог остаться без группы
+func (qg *questionGroups) Scheduler(ctx context.Context, groupId, userId uint64) (string, error) {
+    conds := &map[string]interface{}{"id": groupId, GroupUserId: userId}
+    gl, _, err := qg.getDao().Find(ctx, conds, &[]interface{}{}, 1, 0)
+    if err != nil {
+        return "", errors.Wrapf(err, "Can't find group by id %d via dao", groupId)
+    }
+    if len(*gl) == 0 {
+        return "", nil
+    }
+    return (*gl)[0].Scheduler, nil
+}
+
+func (qg *questionGroups) Exists(ctx context.Context, groupId, userId uint64) (bool, error) {
+    gl, err := qg.getDao().FindWithDeleted(ctx, &map[string]interface{}{"id": groupId, GroupUserId: userId})
+    if err != nil {
+        return false, errors.Wrapf(err, "Can't find group by id %d via dao", groupId)
+    }
+    return len(*gl) > 0, nil
+}
+
+func (qg *questionGroups) Restore(ctx context.Context, groupId, userId uint64) (bool, error) {
+    found, err := restore(ctx, qg.getDao(), groupId, userId)
+    if err != nil {
+        return false, errors.Wrapf(err, "Can't restore group %d", groupId)
+    }
+    return found, nil
+}
+
+func (qg *questionGroups) getDao() Dao {
+    if qg.dao == nil {
+        container.Make(&qg.dao)
+    }
+    return qg.dao
+}
+
+func isOwned(ctx context.Context, dao Dao, groupId, userId uint64) (bool, error) {
+    conds := &map[string]interface{}{"id": groupId, GroupUserId: userId}
+    gl, _, err := dao.Find(ctx, conds, &[]interface{}{}, 1, 0)
+    if err != nil {
+        return false, errors.Wrapf(err, "Can't find group by id %d via dao", groupId)
+    }
+    return len(*gl) > 0, nil
+}
+
+// Метод обходит дерево групп пользователя по уровням и возвращает
+// переданные группы вместе со всеми вложенными в них
+func descendants(ctx context.Context, dao Dao, groupIds []uint64, userId uint64) ([]uint64, error) {
+    result := append([]uint64{}, groupIds...)
+    visited := map[uint64]bool{}
+    for _, id := range groupIds {
+        visited[id] = true
+    }
+
+    parents := groupIds
+    for len(parents) > 0 {
+        conds := &map[string]interface{}{GroupParentId: parents, GroupUserId: userId}
+        gl, _, err := dao.Find(ctx, conds, &[]interface{}{}, 0, 0)
+        if err != nil {
+            return nil, errors.Wrapf(err, "Can't find child groups via dao by parents %v", parents)
+        }
+
+        parents = []uint64{}
+        for _, g := range *gl {
+            if visited[g.ID] {
+                continue
+            }
+            visited[g.ID] = true
+            result = append(result, g.ID)
+            parents = append(parents, g.ID)
+        }
+    }
+
+    return result, nil
+}
+
+// Метод загружает группы пользователя вместе со всеми их родительскими группами
+func ancestors(ctx context.Context, dao Dao, groupIds []uint64, userId uint64) (map[uint64]Group, error) {
+    byId := map[uint64]Group{}
+    ids := groupIds
+    for len(ids) > 0 {
+        conds := &map[string]interface{}{"id": ids, GroupUserId: userId}
+        gl, _, err := dao.Find(ctx, conds, &[]interface{}{}, 0, 0)
+        if err != nil {
+            return nil, errors.Wrapf(err, "Can't find groups via dao by ids %v", ids)
+        }
+
+        ids = []uint64{}
+        for _, g := range *gl {
+            byId[g.ID] = g
+        }
+        for _, g := range *gl {
+            if g.ParentId == nil {
+                continue
+            }
+            if _, found := byId[*g.ParentId]; !found {
+                ids = append(ids, *g.ParentId)
+            }
+        }
+    }
+    return byId, nil
+}
+
+// Метод возвращает из корзины группу вместе с удаленными родительскими группами,
+// чтобы она снова была видна в дереве. Возвращает false, если группы пользователя нет и в корзине
+func restore(ctx context.Context, dao Dao, groupId, userId uint64) (bool, error) {
+    deleted := []uint64{}
+    visited := map[uint64]bool{}
+    for id := groupId; !visited[id]; {
+        visited[id] = true
+        gl, err := dao.FindWithDeleted(ctx, &map[string]interface{}{"id": id, GroupUserId: userId})
+        if err != nil {
+            return false, errors.Wrapf(err, "Can't find group by id %d via dao", id)
+        }
+        if len(*gl) == 0 {
+            if id == groupId {
+                return false, nil
+            }
+            break
+        }
+
+        g := (*gl)[0]
+        if !g.DeletedAt.Valid {
+            break
+        }
+        deleted = append(deleted, g.ID)
+        if g.ParentId == nil {
+            break
+        }
+        id = *g.ParentId
+    }
+
+    if len(deleted) == 0 {
+        return true, nil
+    }
+    err := dao.Restore(ctx, map[string]interface{}{"id": deleted, GroupUserId: userId})
+    if err != nil {
+        return false, errors.Wrapf(err, "Can't restore groups %v via dao", deleted)
+    }
+    return true, nil
+}
+
+// Метод собирает имя группы из имен ее родительских групп, начиная с корневой
+func path(byId map[uint64]Group, id uint64) string {
+    names := []string{}
+    visited := map[uint64]bool{}
+    for {
+        g, found := byId[id]
+        if !found || visited[id] {
+            break
+        }
+        visited[id] = true
+        names = append([]string{g.Name}, names...)
+        if g.ParentId == nil {
+            break
+        }
+        id = *g.ParentId
+    }
+    return strings.Join(names, "::")
+}
@@ -0,0 +1,309 @@
+package groups
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+    "github.com/stretchr/testify/require"
+    "gorm.io/gorm"
+)
+
+func Test_question_groups_is_owned_when_group_of_user_found_result_is_true(t *testing.T) {
+    conds := &map[string]interface{}{"id": uint64(1), GroupUserId: uint64(2)}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, conds, &[]interface{}{}, 1, 0).Return(&[]Group{{ID: 1, UserId: 2}}, false, nil)
+    qg := &questionGroups{dao: dao}
+
+    owned, errResult := qg.IsOwned(context.Background(), 1, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.True(t, owned, "Группа пользователя должна считаться принадлежащей ему")
+}
+
+func Test_question_groups_is_owned_when_group_not_found_result_is_false(t *testing.T) {
+    conds := &map[string]interface{}{"id": uint64(1), GroupUserId: uint64(2)}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, conds, &[]interface{}{}, 1, 0).Return(&[]Group{}, false, nil)
+    qg := &questionGroups{dao: dao}
+
+    owned, errResult := qg.IsOwned(context.Background(), 1, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.False(t, owned, "Ненайденная группа не должна считаться принадлежащей пользователю")
+}
+
+func Test_question_groups_is_owned_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, &map[string]interface{}{"id": uint64(1), GroupUserId: uint64(2)}, &[]interface{}{}, 1, 0).Return(&[]Group{}, false, daoErr)
+    qg := &questionGroups{dao: dao}
+
+    _, errResult := qg.IsOwned(context.Background(), 1, 2)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_question_groups_descendants_return_groups_of_all_levels(t *testing.T) {
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, childConds(2, 1, 5), &[]interface{}{}, 0, 0).Return(&[]Group{{ID: 3}, {ID: 4}}, false, nil)
+    dao.On("Find", mock.Anything, childConds(2, 3, 4), &[]interface{}{}, 0, 0).Return(&[]Group{{ID: 6}}, false, nil)
+    dao.On("Find", mock.Anything, childConds(2, 6), &[]interface{}{}, 0, 0).Return(&[]Group{}, false, nil)
+    qg := &questionGroups{dao: dao}
+
+    idsResult, errResult := qg.Descendants(context.Background(), []uint64{1, 5}, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []uint64{1, 5, 3, 4, 6}, idsResult, "Результат должен содержать переданные группы и все вложенные в них")
+}
+
+func Test_question_groups_descendants_when_tree_has_cycle_each_group_returned_once(t *testing.T) {
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, childConds(2, 1), &[]interface{}{}, 0, 0).Return(&[]Group{{ID: 3}}, false, nil)
+    dao.On("Find", mock.Anything, childConds(2, 3), &[]interface{}{}, 0, 0).Return(&[]Group{{ID: 1}}, false, nil)
+    qg := &questionGroups{dao: dao}
+
+    idsResult, errResult := qg.Descendants(context.Background(), []uint64{1}, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []uint64{1, 3}, idsResult, "Каждая группа должна встречаться в результате один раз")
+}
+
+func Test_question_groups_descendants_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, childConds(2, 1), &[]interface{}{}, 0, 0).Return(&[]Group{}, false, daoErr)
+    qg := &questionGroups{dao: dao}
+
+    _, errResult := qg.Descendants(context.Background(), []uint64{1}, 2)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_question_groups_names_return_names_with_parent_groups(t *testing.T) {
+    subjectId := uint64(1)
+    topicId := uint64(2)
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, &map[string]interface{}{"id": []uint64{3, 1}, GroupUserId: uint64(5)}, &[]interface{}{}, 0, 0).Return(&[]Group{
+        {ID: 3, ParentId: &topicId, Name: "Subtopic"},
+        {ID: 1, Name: "Subject"},
+    }, false, nil)
+    dao.On("Find", mock.Anything, &map[string]interface{}{"id": []uint64{2}, GroupUserId: uint64(5)}, &[]interface{}{}, 0, 0).Return(&[]Group{
+        {ID: 2, ParentId: &subjectId, Name: "Topic"},
+    }, false, nil)
+    qg := &questionGroups{dao: dao}
+
+    namesResult, errResult := qg.Names(context.Background(), []uint64{3, 1}, 5)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, map[uint64]string{3: "Subject::Topic::Subtopic", 1: "Subject"}, namesResult, "Имена групп должны включать имена родительских групп")
+}
+
+func Test_question_groups_names_when_group_not_found_result_has_no_name(t *testing.T) {
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, &map[string]interface{}{"id": []uint64{3}, GroupUserId: uint64(5)}, &[]interface{}{}, 0, 0).Return(&[]Group{}, false, nil)
+    qg := &questionGroups{dao: dao}
+
+    namesResult, errResult := qg.Names(context.Background(), []uint64{3}, 5)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Empty(t, namesResult, "Для ненайденной группы имя не должно возвращаться")
+}
+
+func Test_question_groups_names_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, &map[string]interface{}{"id": []uint64{3}, GroupUserId: uint64(5)}, &[]interface{}{}, 0, 0).Return(&[]Group{}, false, daoErr)
+    qg := &questionGroups{dao: dao}
+
+    _, errResult := qg.Names(context.Background(), []uint64{3}, 5)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_question_groups_ensure_return_existing_groups_and_create_missing_with_parents(t *testing.T) {
+    subjectId := uint64(1)
+    nextId := uint64(10)
+    created := []Group{}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, &map[string]interface{}{GroupUserId: uint64(5)}, &[]interface{}{}, 0, 0).Return(&[]Group{
+        {ID: 1, UserId: 5, Name: "Subject"},
+        {ID: 2, UserId: 5, ParentId: &subjectId, Name: "Topic"},
+    }, false, nil)
+    dao.On("Create", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+        g := args.Get(1).(*Group)
+        g.ID = nextId
+        nextId++
+        created = append(created, *g)
+    })
+    qg := &questionGroups{dao: dao}
+
+    idsResult, createdResult, errResult := qg.Ensure(context.Background(), []string{"Subject::Topic", "Subject::Topic::New", "Other", "Subject::Topic::New"}, 5)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, map[string]uint64{"Subject::Topic": 2, "Subject::Topic::New": 10, "Other": 11}, idsResult, "Id групп по именам неверные")
+    assert.Equal(t, []uint64{10, 11}, createdResult, "Должны вернуться id только созданных групп")
+    require.Len(t, created, 2, "Каждая недостающая группа должна создаваться один раз")
+    assert.Equal(t, uint64(2), *created[0].ParentId, "Новая группа должна создаваться в родительской группе")
+    assert.Nil(t, created[1].ParentId, "Группа без родителя должна быть корневой")
+    assert.Equal(t, uint64(5), created[1].UserId, "Группа должна создаваться для пользователя")
+}
+
+func Test_question_groups_ensure_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, &map[string]interface{}{GroupUserId: uint64(5)}, &[]interface{}{}, 0, 0).Return(&[]Group{}, false, nil)
+    dao.On("Create", mock.Anything, mock.Anything).Return(daoErr)
+    qg := &questionGroups{dao: dao}
+
+    _, _, errResult := qg.Ensure(context.Background(), []string{"Other"}, 5)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_question_groups_ensure_when_create_fails_created_groups_are_removed(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, &map[string]interface{}{GroupUserId: uint64(5)}, &[]interface{}{}, 0, 0).Return(&[]Group{}, false, nil)
+    dao.On("Create", mock.Anything, mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+        args.Get(1).(*Group).ID = 10
+    })
+    dao.On("Create", mock.Anything, mock.Anything).Return(daoErr).Once()
+    dao.On("Remove", mock.Anything, map[string]interface{}{"id": []uint64{10}, GroupUserId: uint64(5)}).Return(nil)
+    qg := &questionGroups{dao: dao}
+
+    _, _, errResult := qg.Ensure(context.Background(), []string{"Subject::Topic"}, 5)
+
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+    dao.AssertCalled(t, "Remove", mock.Anything, map[string]interface{}{"id": []uint64{10}, GroupUserId: uint64(5)})
+}
+
+func Test_question_groups_scheduler_return_scheduler_of_group(t *testing.T) {
+    conds := &map[string]interface{}{"id": uint64(1), GroupUserId: uint64(2)}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, conds, &[]interface{}{}, 1, 0).Return(&[]Group{{ID: 1, UserId: 2, Scheduler: "sm2"}}, false, nil)
+    qg := &questionGroups{dao: dao}
+
+    name, errResult := qg.Scheduler(context.Background(), 1, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, "sm2", name, "Должен вернуться алгоритм группы")
+}
+
+func Test_question_groups_scheduler_when_group_not_found_result_is_default(t *testing.T) {
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, mock.Anything, mock.Anything, 1, 0).Return(&[]Group{}, false, nil)
+    qg := &questionGroups{dao: dao}
+
+    name, errResult := qg.Scheduler(context.Background(), 1, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, "", name, "Для ненайденной группы должен вернуться алгоритм по умолчанию")
+}
+
+func Test_question_groups_scheduler_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, mock.Anything, mock.Anything, 1, 0).Return(&[]Group{}, false, daoErr)
+    qg := &questionGroups{dao: dao}
+
+    _, errResult := qg.Scheduler(context.Background(), 1, 2)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_question_groups_exists_find_group_among_deleted(t *testing.T) {
+    deleted := gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+    dao := &daoMock{}
+    dao.On("FindWithDeleted", mock.Anything, &map[string]interface{}{"id": uint64(3), GroupUserId: uint64(7)}).
+        Return(&[]Group{{ID: 3, DeletedAt: deleted}}, nil)
+    dao.On("FindWithDeleted", mock.Anything, &map[string]interface{}{"id": uint64(4), GroupUserId: uint64(7)}).
+        Return(&[]Group{}, nil)
+    qg := &questionGroups{dao: dao}
+
+    deletedFound, errDeleted := qg.Exists(context.Background(), 3, 7)
+    missingFound, errMissing := qg.Exists(context.Background(), 4, 7)
+
+    require.Nil(t, errDeleted, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errMissing, "Возвращаемая ошибка должна быть пустой")
+    assert.True(t, deletedFound, "Группа в корзине должна найтись")
+    assert.False(t, missingFound, "Группа другого пользователя не должна найтись")
+}
+
+func Test_question_groups_restore_return_deleted_group_with_deleted_parents(t *testing.T) {
+    deleted := gorm.DeletedAt{Time: time.Now(), Valid: true}
+    rootId, parentId := uint64(1), uint64(2)
+
+    dao := &daoMock{}
+    dao.On("FindWithDeleted", mock.Anything, &map[string]interface{}{"id": uint64(3), GroupUserId: uint64(7)}).
+        Return(&[]Group{{ID: 3, ParentId: &parentId, DeletedAt: deleted}}, nil)
+    dao.On("FindWithDeleted", mock.Anything, &map[string]interface{}{"id": parentId, GroupUserId: uint64(7)}).
+        Return(&[]Group{{ID: parentId, ParentId: &rootId, DeletedAt: deleted}}, nil)
+    dao.On("FindWithDeleted", mock.Anything, &map[string]interface{}{"id": rootId, GroupUserId: uint64(7)}).
+        Return(&[]Group{{ID: rootId}}, nil)
+    dao.On("Restore", mock.Anything, map[string]interface{}{"id": []uint64{3, parentId}, GroupUserId: uint64(7)}).Return(nil)
+    qg := &questionGroups{dao: dao}
+
+    found, errResult := qg.Restore(context.Background(), 3, 7)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.True(t, found, "Удаленная группа должна найтись")
+    dao.AssertNumberOfCalls(t, "Restore", 1)
+}
+
+func Test_question_groups_restore_when_group_is_active_groups_are_not_restored(t *testing.T) {
+    dao := &daoMock{}
+    dao.On("FindWithDeleted", mock.Anything, mock.Anything).Return(&[]Group{{ID: 3}}, nil)
+    qg := &questionGroups{dao: dao}
+
+    found, errResult := qg.Restore(context.Background(), 3, 7)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.True(t, found, "Действующая группа должна найтись")
+    dao.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+}
+
+func Test_question_groups_restore_when_group_not_found_result_is_false(t *testing.T) {
+    dao := &daoMock{}
+    dao.On("FindWithDeleted", mock.Anything, mock.Anything).Return(&[]Group{}, nil)
+    qg := &questionGroups{dao: dao}
+
+    found, errResult := qg.Restore(context.Background(), 3, 7)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.False(t, found, "Группа другого пользователя не должна найтись")
+    dao.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+}
+
+func Test_question_groups_restore_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("FindWithDeleted", mock.Anything, mock.Anything).Return(&[]Group{{ID: 3, DeletedAt: gorm.DeletedAt{Valid: true}}}, nil)
+    dao.On("Restore", mock.Anything, mock.Anything).Return(daoErr)
+    qg := &questionGroups{dao: dao}
+
+    _, errResult := qg.Restore(context.Background(), 3, 7)
+
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
@@ -0,0 +1,183 @@
+package groups
+
+import (
+    "context"
+    "time"
+
+    "github.com/golobby/container"
+    "github.com/pkg/errors"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+type Usecase interface {
+    Add(ctx context.Context, g *Group) error
+    Correct(ctx context.Context, g *Group) error
+    Delete(ctx context.Context, g *Group) error
+    Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]Group, more bool, err error)
+    Purge(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type usecase struct {
+    dao       Dao
+    questions questions.Usecase
+}
+
+// Метод добавляет группу пользователя.
+// Если родительская группа не найдена или принадлежит другому пользователю, возвращается ErrParentNotFound
+func (u *usecase) Add(ctx context.Context, g *Group) error {
+    err := u.checkParent(ctx, g)
+    if err != nil {
+        return err
+    }
+
+    dao := u.getDao()
+    err = dao.Create(ctx, g)
+    if err != nil {
+        return errors.Wrap(err, "Can't create group via dao")
+    }
+    return nil
+}
+
+// Метод изменяет группу. Перенос группы внутрь нее самой
+// или ее вложенных групп возвращает ErrParentCycle
+func (u *usecase) Correct(ctx context.Context, g *Group) error {
+    err := u.checkParent(ctx, g)
+    if err != nil {
+        return err
+    }
+
+    dao := u.getDao()
+    fields := []string{groupName, GroupParentId, groupScheduler}
+    err = dao.Update(ctx, g, fields)
+    if err != nil {
+        return errors.Wrap(err, "Can't update group via dao")
+    }
+    return nil
+}
+
+// Метод переносит в корзину группу вместе с вложенными группами и вопросами всех этих групп.
+// Восстановление вопроса из корзины возвращает и его группу.
+// Вопросы и группы хранятся раздельно, поэтому группы переносятся в корзину первыми,
+// а если вопросы перенести не удалось, группы возвращаются из корзины
+func (u *usecase) Delete(ctx context.Context, g *Group) error {
+    dao := u.getDao()
+    ids, err := descendants(ctx, dao, []uint64{g.ID}, g.UserId)
+    if err != nil {
+        return errors.Wrapf(err, "Can't get descendants of group %d", g.ID)
+    }
+
+    conds := map[string]interface{}{"id": ids, GroupUserId: g.UserId}
+    err = dao.Delete(ctx, conds)
+    if err != nil {
+        return errors.Wrapf(err, "Can't delete group %d via dao", g.ID)
+    }
+
+    query := questions.NewQuery().In(questions.FieldGroupId, ids).Eq(questions.FieldUserId, g.UserId)
+    err = u.getQuestions().Delete(ctx, query)
+    if err != nil {
+        err = errors.Wrapf(err, "Can't delete questions of groups %v via usecase", ids)
+        if restoreErr := dao.Restore(ctx, conds); restoreErr != nil {
+            return errors.Wrapf(err, "Can't restore groups %v via dao: %v", ids, restoreErr)
+        }
+        return err
+    }
+    return nil
+}
+
+// Метод возвращает группы с количеством вопросов и вопросов к повторению в каждой
+func (u *usecase) Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]Group, more bool, err error) {
+    dao := u.getDao()
+    list, more, err = dao.Find(ctx, conds, order, limit, offset)
+    if err != nil {
+        return list, more, errors.Wrapf(err, "Can't find group via dao by conds %v", conds)
+    }
+
+    err = u.fillCounts(ctx, list)
+    if err != nil {
+        return list, more, errors.Wrap(err, "Can't count questions of groups")
+    }
+    return list, more, nil
+}
+
+// Метод окончательно удаляет группы, которые пролежали в корзине дольше retention.
+// Вопросы этих групп удаляются из корзины по тому же сроку
+func (u *usecase) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+    deletedBefore := time.Now().Add(-retention)
+    count, err := u.getDao().Purge(ctx, deletedBefore)
+    if err != nil {
+        return 0, errors.Wrapf(err, "Can't purge groups deleted before %v via dao", deletedBefore)
+    }
+    return count, nil
+}
+
+func (u *usecase) checkParent(ctx context.Context, g *Group) error {
+    if g.ParentId == nil {
+        return nil
+    }
+
+    dao := u.getDao()
+    owned, err := isOwned(ctx, dao, *g.ParentId, g.UserId)
+    if err != nil {
+        return errors.Wrapf(err, "Can't check parent %d of group", *g.ParentId)
+    }
+    if !owned {
+        return errors.Wrapf(ErrParentNotFound, "Group %d is not owned by user %d", *g.ParentId, g.UserId)
+    }
+
+    if g.ID == 0 {
+        return nil
+    }
+
+    ids, err := descendants(ctx, dao, []uint64{g.ID}, g.UserId)
+    if err != nil {
+        return errors.Wrapf(err, "Can't get descendants of group %d", g.ID)
+    }
+    for _, id := range ids {
+        if id == *g.ParentId {
+            return errors.Wrapf(ErrParentCycle, "Group %d can't be nested in group %d", g.ID, id)
+        }
+    }
+    return nil
+}
+
+func (u *usecase) fillCounts(ctx context.Context, list *[]Group) error {
+    if len(*list) == 0 {
+        return nil
+    }
+
+    ids := make([]uint64, 0, len(*list))
+    for _, g := range *list {
+        ids = append(ids, g.ID)
+    }
+
+    counts, err := u.getQuestions().CountByGroup(ctx, questions.NewQuery().In(questions.FieldGroupId, ids))
+    if err != nil {
+        return errors.Wrapf(err, "Can't count questions via usecase by groups %v", ids)
+    }
+
+    byGroup := map[uint64]questions.GroupCount{}
+    for _, c := range *counts {
+        byGroup[c.GroupId] = c
+    }
+    for i := range *list {
+        g := &(*list)[i]
+        g.CardCount = byGroup[g.ID].Total
+        g.DueCount = byGroup[g.ID].Due
+    }
+    return nil
+}
+
+func (u *usecase) getDao() Dao {
+    if u.dao == nil {
+        container.Make(&u.dao)
+    }
+    return u.dao
+}
+
+func (u *usecase) getQuestions() questions.Usecase {
+    if u.questions == nil {
+        container.Make(&u.questions)
+    }
+    return u.questions
+}
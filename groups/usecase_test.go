@@ -0,0 +1,449 @@
+package groups
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+type daoMock struct {
+    mock.Mock
+}
+
+func (m *daoMock) Create(ctx context.Context, g *Group) error {
+    args := m.Called(ctx, g)
+    return args.Error(0)
+}
+
+func (m *daoMock) Update(ctx context.Context, g *Group, fields []string) error {
+    args := m.Called(ctx, g, fields)
+    return args.Error(0)
+}
+
+func (m *daoMock) Delete(ctx context.Context, conds ...interface{}) error {
+    args := m.Called(append([]interface{}{ctx}, conds...)...)
+    return args.Error(0)
+}
+
+func (m *daoMock) Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]Group, more bool, err error) {
+    args := m.Called(ctx, conds, order, limit, offset)
+    return args.Get(0).(*[]Group), args.Bool(1), args.Error(2)
+}
+
+func (m *daoMock) FindWithDeleted(ctx context.Context, conds *map[string]interface{}) (*[]Group, error) {
+    args := m.Called(ctx, conds)
+    return args.Get(0).(*[]Group), args.Error(1)
+}
+
+func (m *daoMock) Restore(ctx context.Context, conds map[string]interface{}) error {
+    args := m.Called(ctx, conds)
+    return args.Error(0)
+}
+
+func (m *daoMock) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+    args := m.Called(ctx, deletedBefore)
+    return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *daoMock) Remove(ctx context.Context, conds map[string]interface{}) error {
+    args := m.Called(ctx, conds)
+    return args.Error(0)
+}
+
+type questionsMock struct {
+    mock.Mock
+}
+
+func (m *questionsMock) Add(ctx context.Context, q *questions.Question) error {
+    args := m.Called(ctx, q)
+    return args.Error(0)
+}
+
+func (m *questionsMock) Import(ctx context.Context, ql []*questions.Question) ([]error, error) {
+    args := m.Called(ctx, ql)
+    return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *questionsMock) Batch(ctx context.Context, userId uint64, ops []questions.BatchOperation) ([]questions.BatchResult, error) {
+    args := m.Called(ctx, userId, ops)
+    return args.Get(0).([]questions.BatchResult), args.Error(1)
+}
+
+func (m *questionsMock) Correct(ctx context.Context, q *questions.Question) error {
+    args := m.Called(ctx, q)
+    return args.Error(0)
+}
+
+func (m *questionsMock) Patch(ctx context.Context, q *questions.Question, fields []string) error {
+    args := m.Called(ctx, q, fields)
+    return args.Error(0)
+}
+
+func (m *questionsMock) Delete(ctx context.Context, query *questions.Query) error {
+    args := m.Called(ctx, query)
+    return args.Error(0)
+}
+
+func (m *questionsMock) Trash(ctx context.Context, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    args := m.Called(ctx, query)
+    return args.Get(0).(*[]questions.Question), args.Bool(1), args.Error(2)
+}
+
+func (m *questionsMock) Restore(ctx context.Context, q *questions.Question) error {
+    args := m.Called(ctx, q)
+    return args.Error(0)
+}
+
+func (m *questionsMock) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+    args := m.Called(ctx, retention)
+    return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *questionsMock) Answer(ctx context.Context, id, version uint64, grade questions.Grade, responseTime time.Duration) (*questions.Question, error) {
+    args := m.Called(ctx, id, version, grade, responseTime)
+    return args.Get(0).(*questions.Question), args.Error(1)
+}
+
+func (m *questionsMock) Find(ctx context.Context, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    args := m.Called(ctx, query)
+    return args.Get(0).(*[]questions.Question), args.Bool(1), args.Error(2)
+}
+
+func (m *questionsMock) Due(ctx context.Context, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    args := m.Called(ctx, query)
+    return args.Get(0).(*[]questions.Question), args.Bool(1), args.Error(2)
+}
+
+func (m *questionsMock) Search(ctx context.Context, text string, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    args := m.Called(ctx, text, query)
+    return args.Get(0).(*[]questions.Question), args.Bool(1), args.Error(2)
+}
+
+func (m *questionsMock) CountByGroup(ctx context.Context, query *questions.Query) (*[]questions.GroupCount, error) {
+    args := m.Called(ctx, query)
+    return args.Get(0).(*[]questions.GroupCount), args.Error(1)
+}
+
+func (m *questionsMock) DescendantGroups(ctx context.Context, groupIds []uint64, userId uint64) ([]uint64, error) {
+    args := m.Called(ctx, groupIds, userId)
+    return args.Get(0).([]uint64), args.Error(1)
+}
+
+func (m *questionsMock) GroupNames(ctx context.Context, groupIds []uint64, userId uint64) (map[uint64]string, error) {
+    args := m.Called(ctx, groupIds, userId)
+    return args.Get(0).(map[uint64]string), args.Error(1)
+}
+
+func (m *questionsMock) EnsureGroups(ctx context.Context, names []string, userId uint64) (map[string]uint64, []uint64, error) {
+    args := m.Called(ctx, names, userId)
+    return args.Get(0).(map[string]uint64), args.Get(1).([]uint64), args.Error(2)
+}
+
+func (m *questionsMock) RemoveGroups(ctx context.Context, groupIds []uint64, userId uint64) error {
+    args := m.Called(ctx, groupIds, userId)
+    return args.Error(0)
+}
+
+func childConds(userId uint64, parents ...uint64) *map[string]interface{} {
+    return &map[string]interface{}{GroupParentId: parents, GroupUserId: userId}
+}
+
+// Dao, в котором у групп нет вложенных групп
+func withoutChildren() *daoMock {
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, mock.Anything, &[]interface{}{}, 0, 0).Return(&[]Group{}, false, nil)
+    return dao
+}
+
+// -------------
+// ---- Add ----
+// -------------
+
+func Test_usecase_add_dao_calls_is_correct(t *testing.T) {
+    gIn := &Group{}
+
+    dao := &daoMock{}
+    dao.On("Create", mock.Anything, gIn).Return(nil)
+    uc := usecase{dao: dao}
+
+    _ = uc.Add(context.Background(), gIn)
+
+    createCalls := 1
+    if !dao.AssertNumberOfCalls(t, "Create", createCalls) {
+        t.Errorf("Метод Create у dao должен вызваться %d раз", createCalls)
+        t.Fail()
+    }
+}
+
+func Test_usecase_add_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    gIn := &Group{}
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Create", mock.Anything, gIn).Return(daoErr)
+    uc := usecase{dao: dao}
+
+    errResult := uc.Add(context.Background(), gIn)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_usecase_add_when_parent_not_owned_result_error_is_parent_not_found(t *testing.T) {
+    parentId := uint64(3)
+    gIn := &Group{UserId: 2, ParentId: &parentId}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, &map[string]interface{}{"id": uint64(3), GroupUserId: uint64(2)}, &[]interface{}{}, 1, 0).Return(&[]Group{}, false, nil)
+    uc := usecase{dao: dao}
+
+    errResult := uc.Add(context.Background(), gIn)
+
+    require.ErrorIs(t, errResult, ErrParentNotFound, "Возвращаемая ошибка должна быть ErrParentNotFound")
+    dao.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_usecase_add_when_parent_owned_group_is_created(t *testing.T) {
+    parentId := uint64(3)
+    gIn := &Group{UserId: 2, ParentId: &parentId}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, &map[string]interface{}{"id": uint64(3), GroupUserId: uint64(2)}, &[]interface{}{}, 1, 0).Return(&[]Group{{ID: 3}}, false, nil)
+    dao.On("Create", mock.Anything, gIn).Return(nil)
+    uc := usecase{dao: dao}
+
+    errResult := uc.Add(context.Background(), gIn)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    dao.AssertCalled(t, "Create", mock.Anything, gIn)
+}
+
+// -----------------
+// ---- Correct ----
+// -----------------
+
+func Test_usecase_correct_dao_calls_is_correct(t *testing.T) {
+    gIn := &Group{}
+
+    dao := &daoMock{}
+    dao.On("Update", mock.Anything, gIn, []string{groupName, GroupParentId, groupScheduler}).Return(nil)
+    uc := usecase{dao: dao}
+
+    _ = uc.Correct(context.Background(), gIn)
+
+    updateCalls := 1
+    if !dao.AssertNumberOfCalls(t, "Update", updateCalls) {
+        t.Errorf("Метод Update у dao должен вызваться %d раз", updateCalls)
+        t.Fail()
+    }
+}
+
+func Test_usecase_correct_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    gIn := &Group{}
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Update", mock.Anything, gIn, []string{groupName, GroupParentId, groupScheduler}).Return(daoErr)
+    uc := usecase{dao: dao}
+
+    errResult := uc.Correct(context.Background(), gIn)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_usecase_correct_when_parent_is_descendant_result_error_is_parent_cycle(t *testing.T) {
+    parentId := uint64(4)
+    gIn := &Group{ID: 1, UserId: 2, ParentId: &parentId}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, &map[string]interface{}{"id": uint64(4), GroupUserId: uint64(2)}, &[]interface{}{}, 1, 0).Return(&[]Group{{ID: 4}}, false, nil)
+    dao.On("Find", mock.Anything, childConds(2, 1), &[]interface{}{}, 0, 0).Return(&[]Group{{ID: 3}}, false, nil)
+    dao.On("Find", mock.Anything, childConds(2, 3), &[]interface{}{}, 0, 0).Return(&[]Group{{ID: 4}}, false, nil)
+    dao.On("Find", mock.Anything, childConds(2, 4), &[]interface{}{}, 0, 0).Return(&[]Group{}, false, nil)
+    uc := usecase{dao: dao}
+
+    errResult := uc.Correct(context.Background(), gIn)
+
+    require.ErrorIs(t, errResult, ErrParentCycle, "Возвращаемая ошибка должна быть ErrParentCycle")
+    dao.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_usecase_correct_when_parent_is_group_itself_result_error_is_parent_cycle(t *testing.T) {
+    parentId := uint64(1)
+    gIn := &Group{ID: 1, UserId: 2, ParentId: &parentId}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, &map[string]interface{}{"id": uint64(1), GroupUserId: uint64(2)}, &[]interface{}{}, 1, 0).Return(&[]Group{{ID: 1}}, false, nil)
+    dao.On("Find", mock.Anything, childConds(2, 1), &[]interface{}{}, 0, 0).Return(&[]Group{}, false, nil)
+    uc := usecase{dao: dao}
+
+    errResult := uc.Correct(context.Background(), gIn)
+
+    require.ErrorIs(t, errResult, ErrParentCycle, "Возвращаемая ошибка должна быть ErrParentCycle")
+}
+
+// ----------------
+// ---- Delete ----
+// ----------------
+
+func Test_usecase_delete_remove_descendant_groups_and_their_questions(t *testing.T) {
+    gIn := &Group{ID: 1, UserId: 2}
+    qQuery := questions.NewQuery().In(questions.FieldGroupId, []uint64{1, 3}).Eq(questions.FieldUserId, uint64(2))
+    gConds := map[string]interface{}{"id": []uint64{1, 3}, GroupUserId: uint64(2)}
+
+    qs := &questionsMock{}
+    qs.On("Delete", mock.Anything, qQuery).Return(nil)
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, childConds(2, 1), &[]interface{}{}, 0, 0).Return(&[]Group{{ID: 3}}, false, nil)
+    dao.On("Find", mock.Anything, childConds(2, 3), &[]interface{}{}, 0, 0).Return(&[]Group{}, false, nil)
+    dao.On("Delete", mock.Anything, gConds).Return(nil)
+    uc := usecase{dao: dao, questions: qs}
+
+    errResult := uc.Delete(context.Background(), gIn)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    qs.AssertCalled(t, "Delete", mock.Anything, qQuery)
+    dao.AssertCalled(t, "Delete", mock.Anything, gConds)
+}
+
+func Test_usecase_delete_when_questions_not_deleted_groups_are_restored(t *testing.T) {
+    gIn := &Group{ID: 1, UserId: 2}
+    gConds := map[string]interface{}{"id": []uint64{1}, GroupUserId: uint64(2)}
+    usecaseErr := errors.New("Usecase mock error")
+
+    qs := &questionsMock{}
+    qs.On("Delete", mock.Anything, mock.Anything).Return(usecaseErr)
+    dao := withoutChildren()
+    dao.On("Delete", mock.Anything, gConds).Return(nil)
+    dao.On("Restore", mock.Anything, gConds).Return(nil)
+    uc := usecase{dao: dao, questions: qs}
+
+    errResult := uc.Delete(context.Background(), gIn)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase вопросов")
+    dao.AssertCalled(t, "Restore", mock.Anything, gConds)
+}
+
+func Test_usecase_delete_dao_work_wrong_result_error_not_empty_and_questions_are_not_deleted(t *testing.T) {
+    gIn := &Group{ID: 1, UserId: 2}
+    daoErr := errors.New("Dao mock error")
+
+    qs := &questionsMock{}
+    dao := withoutChildren()
+    dao.On("Delete", mock.Anything, mock.Anything).Return(daoErr)
+    uc := usecase{dao: dao, questions: qs}
+
+    errResult := uc.Delete(context.Background(), gIn)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+    qs.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+// ---------------
+// ---- Purge ----
+// ---------------
+
+func Test_usecase_purge_delete_groups_older_than_retention(t *testing.T) {
+    dao := &daoMock{}
+    dao.On("Purge", mock.Anything, mock.Anything).Return(int64(2), nil)
+    uc := usecase{dao: dao}
+
+    before := time.Now()
+    count, errResult := uc.Purge(context.Background(), time.Hour)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, int64(2), count, "Количество удаленных групп неверное")
+    deletedBefore := dao.Calls[0].Arguments.Get(1).(time.Time)
+    assert.WithinDuration(t, before.Add(-time.Hour), deletedBefore, time.Second, "Удаляться должны группы старше срока хранения")
+}
+
+func Test_usecase_purge_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Purge", mock.Anything, mock.Anything).Return(int64(0), daoErr)
+    uc := usecase{dao: dao}
+
+    _, errResult := uc.Purge(context.Background(), time.Hour)
+
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+// --------------
+// ---- Find ----
+// --------------
+
+func Test_usecase_find_result_groups_contain_counts_of_questions(t *testing.T) {
+    conds := &map[string]interface{}{GroupUserId: 1}
+    order := &[]interface{}{}
+    countQuery := questions.NewQuery().In(questions.FieldGroupId, []uint64{1, 2})
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, conds, order, 10, 0).Return(&[]Group{{ID: 1}, {ID: 2}}, true, nil)
+    qs := &questionsMock{}
+    qs.On("CountByGroup", mock.Anything, countQuery).Return(&[]questions.GroupCount{{GroupId: 2, Total: 5, Due: 3}}, nil)
+    uc := usecase{dao: dao, questions: qs}
+
+    glResult, moreResult, errResult := uc.Find(context.Background(), conds, order, 10, 0)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.True(t, moreResult, "Признак наличия следующей страницы должен быть взят из dao")
+    assert.Equal(t, &[]Group{{ID: 1}, {ID: 2, CardCount: 5, DueCount: 3}}, glResult, "Группы должны содержать количество вопросов из usecase вопросов")
+}
+
+func Test_usecase_find_when_groups_not_found_questions_are_not_counted(t *testing.T) {
+    conds := &map[string]interface{}{GroupUserId: 1}
+    order := &[]interface{}{}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, conds, order, 10, 0).Return(&[]Group{}, false, nil)
+    qs := &questionsMock{}
+    uc := usecase{dao: dao, questions: qs}
+
+    _, _, errResult := uc.Find(context.Background(), conds, order, 10, 0)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    qs.AssertNotCalled(t, "CountByGroup", mock.Anything, mock.Anything)
+}
+
+func Test_usecase_find_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+    conds := &map[string]interface{}{"id": 1}
+    order := &[]interface{}{}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, conds, order, 1, 0).Return(&[]Group{}, false, daoErr)
+    uc := usecase{dao: dao}
+
+    _, _, errResult := uc.Find(context.Background(), conds, order, 1, 0)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_usecase_find_when_count_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+    conds := &map[string]interface{}{"id": 1}
+    order := &[]interface{}{}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, conds, order, 1, 0).Return(&[]Group{{ID: 1}}, false, nil)
+    qs := &questionsMock{}
+    qs.On("CountByGroup", mock.Anything, mock.Anything).Return(&[]questions.GroupCount{}, usecaseErr)
+    uc := usecase{dao: dao, questions: qs}
+
+    _, _, errResult := uc.Find(context.Background(), conds, order, 1, 0)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase вопросов")
+}
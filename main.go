@@ -1,25 +1,42 @@
 package main
 
 import (
+    "context"
     "log"
     "os"
+    "strings"
+    "time"
 
     "github.com/golobby/container"
-    "gorm.io/driver/postgres"
     "gorm.io/gorm"
 
     "github.com/gin-gonic/gin"
 
+    "github.com/chudoyoudo/remember-cards/auth"
+    auth_gin "github.com/chudoyoudo/remember-cards/auth/gin"
+    "github.com/chudoyoudo/remember-cards/connection"
+    "github.com/chudoyoudo/remember-cards/groups"
+    group_gin "github.com/chudoyoudo/remember-cards/groups/gin"
+    _ "github.com/chudoyoudo/remember-cards/groups/gorm"
+    group_memory "github.com/chudoyoudo/remember-cards/groups/memory"
     "github.com/chudoyoudo/remember-cards/questions"
     question_gin "github.com/chudoyoudo/remember-cards/questions/gin"
-    _ "github.com/chudoyoudo/remember-cards/questions/gorm"
+    question_gorm "github.com/chudoyoudo/remember-cards/questions/gorm"
+    question_memory "github.com/chudoyoudo/remember-cards/questions/memory"
+    "github.com/chudoyoudo/remember-cards/users"
+    user_gin "github.com/chudoyoudo/remember-cards/users/gin"
+    _ "github.com/chudoyoudo/remember-cards/users/gorm"
+    user_memory "github.com/chudoyoudo/remember-cards/users/memory"
 )
 
 func init() {
-    initPostgres()
+    initStorage()
+    initScheduler()
+    initAuth()
 }
 
 func main() {
+    go RunTrashPurge()
     RunHttpServer()
 }
 
@@ -28,22 +45,97 @@ func RunHttpServer() {
     r := gin.New()
     r.Use(gin.Recovery())
     r.Use(gin.Logger())
-    question_gin.RegisterHandlers(r)
+    question_gin.RegisterHandlers(r, auth_gin.Middleware())
+    user_gin.RegisterHandlers(r, auth_gin.Middleware())
+    group_gin.RegisterHandlers(r, auth_gin.Middleware())
     if err := r.Run(":8080"); err != nil {
         log.Fatalln(err)
     }
 }
 
-func initPostgres() {
+// Метод раз в TRASH_PURGE_INTERVAL (по умолчанию час) окончательно удаляет вопросы и группы,
+// которые пролежали в корзине дольше TRASH_RETENTION (по умолчанию 30 дней)
+func RunTrashPurge() {
+    retention := getDurationEnv("TRASH_RETENTION", time.Hour*24*30)
+    interval := getDurationEnv("TRASH_PURGE_INTERVAL", time.Hour)
+
+    var uc questions.Usecase
+    container.Make(&uc)
+    var groupUc groups.Usecase
+    container.Make(&groupUc)
+
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    for {
+        count, err := uc.Purge(context.Background(), retention)
+        if err != nil {
+            log.Printf("Can't purge trash. Error %s", err)
+        } else if count > 0 {
+            log.Printf("Purged %d questions from trash", count)
+        }
+
+        count, err = groupUc.Purge(context.Background(), retention)
+        if err != nil {
+            log.Printf("Can't purge groups from trash. Error %s", err)
+        } else if count > 0 {
+            log.Printf("Purged %d groups from trash", count)
+        }
+        <-ticker.C
+    }
+}
+
+// Хранилище выбирается переменной STORAGE: postgres (по умолчанию), sqlite или memory.
+// SQLite подходит для однопользовательской установки, файл базы задается переменной SQLITE_DSN.
+// В памяти данные не переживают перезапуск, зато сервер и тесты работают без базы
+func initStorage() {
+    storage, found := os.LookupEnv("STORAGE")
+    if !found {
+        storage = "postgres"
+    }
+
+    switch storage {
+    case "postgres", "sqlite":
+        initDb(storage)
+    case "memory":
+        question_memory.Register()
+        group_memory.Register()
+        user_memory.Register()
+    default:
+        log.Fatalf("Unknown storage %s", storage)
+    }
+}
+
+// Строки подключения по умолчанию, их переопределяют переменные POSTGRES_DSN и SQLITE_DSN
+var defaultDsn = map[string]string{
+    "postgres": "host=localhost port=5432 user=postgres password=123 dbname=rc",
+    "sqlite":   "remember-cards.db?_busy_timeout=5000&_journal_mode=WAL",
+}
+
+func initDb(dialect string) {
     container.Singleton(func() *gorm.DB {
-        dsn, found := os.LookupEnv("POSTGRES_DSN")
+        dsn, found := os.LookupEnv(strings.ToUpper(dialect) + "_DSN")
         if !found {
-            dsn = "host=localhost port=5432 user=postgres password=123 dbname=rc"
+            dsn = defaultDsn[dialect]
+        }
+
+        dialector, err := connection.NewDialector(dialect, dsn)
+        if err != nil {
+            log.Fatalf("Can't create database dialector. Error %s", err)
+        }
+
+        db, err := gorm.Open(dialector, &gorm.Config{})
+        if err != nil {
+            log.Fatalf("Can't connect to %s. Error %s", dialect, err)
         }
 
-        db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+        err = db.AutoMigrate(&questions.Tag{})
         if err != nil {
-            log.Fatalf("Can't connect to postgresql. Error %s", err)
+            log.Fatalf("Can't migrate tags table. Error %s", err)
+        }
+
+        err = question_gorm.RenameColumns(db)
+        if err != nil {
+            log.Fatalf("Can't rename questions columns. Error %s", err)
         }
 
         err = db.AutoMigrate(&questions.Question{})
@@ -51,6 +143,73 @@ func initPostgres() {
             log.Fatalf("Can't migrate questions table. Error %s", err)
         }
 
+        err = question_gorm.Migrate(db)
+        if err != nil {
+            log.Fatalf("Can't create questions search index. Error %s", err)
+        }
+
+        err = db.AutoMigrate(&questions.Review{})
+        if err != nil {
+            log.Fatalf("Can't migrate reviews table. Error %s", err)
+        }
+
+        err = db.AutoMigrate(&users.User{})
+        if err != nil {
+            log.Fatalf("Can't migrate users table. Error %s", err)
+        }
+
+        err = db.AutoMigrate(&groups.Group{})
+        if err != nil {
+            log.Fatalf("Can't migrate groups table. Error %s", err)
+        }
+
         return db
     })
 }
+
+// Алгоритм повторений по умолчанию выбирается переменной SCHEDULER,
+// а группа может выбрать свой алгоритм в поле scheduler
+func initScheduler() {
+    name, found := os.LookupEnv("SCHEDULER")
+    if !found {
+        name = questions.SchedulerLadder
+    }
+
+    schedulers, err := questions.NewSchedulerResolver(name)
+    if err != nil {
+        log.Fatalf("Can't create scheduler. Error %s", err)
+    }
+
+    container.Singleton(func() questions.SchedulerResolver {
+        return schedulers
+    })
+}
+
+// Токены подписываются ключом из JWT_KEY и живут JWT_TTL (по умолчанию сутки)
+func initAuth() {
+    // Без ключа любой мог бы подписать токен для любого пользователя, поэтому значения по умолчанию нет
+    key := os.Getenv("JWT_KEY")
+    if key == "" {
+        log.Fatalf("JWT_KEY is not set")
+    }
+
+    ttl := getDurationEnv("JWT_TTL", time.Hour*24)
+
+    container.Singleton(func() auth.Tokens {
+        return auth.NewTokens([]byte(key), ttl)
+    })
+}
+
+// Метод читает длительность из переменной окружения name в формате time.ParseDuration, например 720h
+func getDurationEnv(name string, def time.Duration) time.Duration {
+    value, found := os.LookupEnv(name)
+    if !found {
+        return def
+    }
+
+    d, err := time.ParseDuration(value)
+    if err != nil {
+        log.Fatalf("Can't parse %s. Error %s", name, err)
+    }
+    return d
+}
@@ -0,0 +1,251 @@
+package memory
+
+import (
+    "reflect"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/pkg/errors"
+)
+
+// Метод сравнивает значения колонок и возвращает -1, 0 или 1. Числа разных типов
+// сравниваются по значению, nil меньше любого другого значения
+func Compare(a, b interface{}) (int, error) {
+    if a == nil || b == nil {
+        switch {
+        case a == nil && b == nil:
+            return 0, nil
+        case a == nil:
+            return -1, nil
+        }
+        return 1, nil
+    }
+
+    if x, ok := a.(time.Time); ok {
+        y, ok := b.(time.Time)
+        if !ok {
+            return 0, errors.Errorf("Can't compare %T with %T", a, b)
+        }
+        switch {
+        case x.Before(y):
+            return -1, nil
+        case x.After(y):
+            return 1, nil
+        }
+        return 0, nil
+    }
+
+    x, y := reflect.ValueOf(a), reflect.ValueOf(b)
+    switch {
+    case isNumber(x) && isNumber(y):
+        return compareNumbers(x, y), nil
+    case x.Kind() == reflect.String && y.Kind() == reflect.String:
+        return strings.Compare(x.String(), y.String()), nil
+    case x.Kind() == reflect.Bool && y.Kind() == reflect.Bool:
+        return compareInts(boolToInt(x.Bool()), boolToInt(y.Bool())), nil
+    }
+    return 0, errors.Errorf("Can't compare %T with %T", a, b)
+}
+
+// Метод проверяет строку по условиям в виде map, как Find в gorm:
+// значение колонки должно совпасть со значением условия, а для среза — с любым его элементом
+func Match(row interface{}, conds map[string]interface{}) (bool, error) {
+    for column, cond := range conds {
+        value, err := Value(row, column)
+        if err != nil {
+            return false, err
+        }
+
+        found := false
+        for _, c := range condValues(cond) {
+            result, err := Compare(value, c)
+            if err != nil {
+                return false, errors.Wrapf(err, "Can't compare column %s", column)
+            }
+            if result == 0 {
+                found = true
+                break
+            }
+        }
+        if !found {
+            return false, nil
+        }
+    }
+    return true, nil
+}
+
+// Метод сортирует срез строк rows по порядку в формате gorm Order, например "id desc".
+// Строки с равными значениями сохраняют исходный порядок
+func Sort(rows interface{}, order []interface{}) error {
+    type orderColumn struct {
+        name string
+        desc bool
+    }
+
+    columns := []orderColumn{}
+    for _, o := range order {
+        s, ok := o.(string)
+        if !ok {
+            return errors.Errorf("Order %v is not supported", o)
+        }
+        parts := strings.Fields(s)
+        if len(parts) == 0 || len(parts) > 2 {
+            return errors.Errorf("Order %q is not supported", s)
+        }
+        desc := len(parts) == 2 && strings.EqualFold(parts[1], "desc")
+        if len(parts) == 2 && !desc && !strings.EqualFold(parts[1], "asc") {
+            return errors.Errorf("Order %q is not supported", s)
+        }
+        columns = append(columns, orderColumn{name: parts[0], desc: desc})
+    }
+
+    v := reflect.ValueOf(rows)
+    var err error
+    sort.SliceStable(rows, func(i, j int) bool {
+        for _, c := range columns {
+            a, errA := Value(v.Index(i).Interface(), c.name)
+            b, errB := Value(v.Index(j).Interface(), c.name)
+            if errA != nil || errB != nil {
+                err = errors.Errorf("Can't sort by column %s", c.name)
+                return false
+            }
+
+            result, errCompare := Compare(a, b)
+            if errCompare != nil {
+                err = errors.Wrapf(errCompare, "Can't sort by column %s", c.name)
+                return false
+            }
+            if result != 0 {
+                return (result < 0) != c.desc
+            }
+        }
+        return false
+    })
+    return err
+}
+
+// Метод возвращает границы страницы среди n строк и признак того,
+// что после страницы есть еще строки. Нулевой limit означает все строки после offset
+func Page(n, limit, offset int) (from, to int, more bool) {
+    from = offset
+    if from > n {
+        from = n
+    }
+
+    to = n
+    if limit > 0 && from+limit < n {
+        to = from + limit
+        more = true
+    }
+    return from, to, more
+}
+
+// Значения условия: элементы среза или само значение
+func condValues(cond interface{}) []interface{} {
+    v := reflect.ValueOf(cond)
+    if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+        return []interface{}{cond}
+    }
+
+    values := make([]interface{}, v.Len())
+    for i := range values {
+        values[i] = v.Index(i).Interface()
+    }
+    return values
+}
+
+func isNumber(v reflect.Value) bool {
+    switch v.Kind() {
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+        reflect.Float32, reflect.Float64:
+        return true
+    }
+    return false
+}
+
+func compareNumbers(x, y reflect.Value) int {
+    if isFloat(x) || isFloat(y) {
+        return compareFloats(toFloat(x), toFloat(y))
+    }
+
+    // Отрицательное число меньше любого беззнакового, остальные целые сравниваются как uint64
+    xNeg, yNeg := isNegative(x), isNegative(y)
+    switch {
+    case xNeg && yNeg:
+        return compareInts(x.Int(), y.Int())
+    case xNeg:
+        return -1
+    case yNeg:
+        return 1
+    }
+    return compareUints(toUint(x), toUint(y))
+}
+
+func isFloat(v reflect.Value) bool {
+    return v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+}
+
+func isNegative(v reflect.Value) bool {
+    switch v.Kind() {
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        return v.Int() < 0
+    }
+    return false
+}
+
+func toFloat(v reflect.Value) float64 {
+    switch {
+    case isFloat(v):
+        return v.Float()
+    case isNegative(v):
+        return float64(v.Int())
+    }
+    return float64(toUint(v))
+}
+
+func toUint(v reflect.Value) uint64 {
+    switch v.Kind() {
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        return uint64(v.Int())
+    }
+    return v.Uint()
+}
+
+func compareInts(x, y int64) int {
+    switch {
+    case x < y:
+        return -1
+    case x > y:
+        return 1
+    }
+    return 0
+}
+
+func compareUints(x, y uint64) int {
+    switch {
+    case x < y:
+        return -1
+    case x > y:
+        return 1
+    }
+    return 0
+}
+
+func compareFloats(x, y float64) int {
+    switch {
+    case x < y:
+        return -1
+    case x > y:
+        return 1
+    }
+    return 0
+}
+
+func boolToInt(b bool) int64 {
+    if b {
+        return 1
+    }
+    return 0
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+// -----------------
+// ---- Compare ----
+// -----------------
+
+func Test_compare_numbers_of_different_types_by_value(t *testing.T) {
+    less, errLess := Compare(uint64(1), 2)
+    equal, errEqual := Compare(uint8(3), int64(3))
+    negative, errNegative := Compare(uint64(0), -1)
+    float, errFloat := Compare(1.5, uint32(1))
+
+    require.Nil(t, errLess, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errEqual, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errNegative, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errFloat, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, -1, less, "uint64(1) должно быть меньше 2")
+    assert.Equal(t, 0, equal, "uint8(3) должно быть равно int64(3)")
+    assert.Equal(t, 1, negative, "uint64(0) должно быть больше -1")
+    assert.Equal(t, 1, float, "1.5 должно быть больше uint32(1)")
+}
+
+func Test_compare_times_strings_bools_and_nil(t *testing.T) {
+    now := time.Now()
+
+    times, _ := Compare(now, now.Add(time.Second))
+    strings, _ := Compare("b", "a")
+    bools, _ := Compare(false, true)
+    nilFirst, _ := Compare(nil, now)
+    nils, _ := Compare(nil, nil)
+
+    assert.Equal(t, -1, times, "Более раннее время должно быть меньше")
+    assert.Equal(t, 1, strings, "Строки должны сравниваться лексикографически")
+    assert.Equal(t, -1, bools, "false должно быть меньше true")
+    assert.Equal(t, -1, nilFirst, "nil должен быть меньше любого значения")
+    assert.Equal(t, 0, nils, "nil должен быть равен nil")
+}
+
+func Test_compare_when_types_differs_result_error_not_empty(t *testing.T) {
+    _, errResult := Compare("1", 1)
+
+    assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
+
+// ---------------
+// ---- Match ----
+// ---------------
+
+func Test_match_check_all_conds_and_any_value_of_slice(t *testing.T) {
+    r := &record{ID: 2, UserId: 1}
+
+    all, errAll := Match(r, map[string]interface{}{"userId": 1, "id": []uint64{1, 2}})
+    notAll, errNotAll := Match(r, map[string]interface{}{"userId": 1, "id": []uint64{3}})
+    empty, errEmpty := Match(r, map[string]interface{}{})
+    nilParent, errNilParent := Match(r, map[string]interface{}{"parentId": nil})
+
+    require.Nil(t, errAll, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errNotAll, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errEmpty, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errNilParent, "Возвращаемая ошибка должна быть пустой")
+    assert.True(t, all, "Строка подходит под все условия")
+    assert.False(t, notAll, "Строка не подходит под условие по id")
+    assert.True(t, empty, "Без условий подходит любая строка")
+    assert.True(t, nilParent, "Пустой указатель должен совпадать с nil")
+}
+
+func Test_match_when_column_not_found_result_error_is_column_not_found(t *testing.T) {
+    _, errResult := Match(&record{}, map[string]interface{}{"unknown": 1})
+
+    assert.ErrorIs(t, errResult, ErrColumnNotFound, "Возвращаемая ошибка должна быть ErrColumnNotFound")
+}
+
+// --------------
+// ---- Sort ----
+// --------------
+
+func Test_sort_order_rows_by_columns_and_keep_order_of_equal_rows(t *testing.T) {
+    rows := []record{{ID: 1, UserId: 1}, {ID: 2, UserId: 2}, {ID: 3, UserId: 1}}
+
+    errResult := Sort(rows, []interface{}{"userId desc"})
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []record{{ID: 2, UserId: 2}, {ID: 1, UserId: 1}, {ID: 3, UserId: 1}}, rows, "Строки отсортированы неверно")
+}
+
+func Test_sort_when_order_not_supported_result_error_not_empty(t *testing.T) {
+    rows := []record{{ID: 1}, {ID: 2}}
+
+    errFormat := Sort(rows, []interface{}{"id sideways"})
+    errColumn := Sort(rows, []interface{}{"unknown"})
+
+    assert.NotNil(t, errFormat, "Возвращаемая ошибка не должна быть пустой для неизвестного направления")
+    assert.NotNil(t, errColumn, "Возвращаемая ошибка не должна быть пустой для неизвестной колонки")
+}
+
+// --------------
+// ---- Page ----
+// --------------
+
+func Test_page_return_bounds_and_more_flag(t *testing.T) {
+    from, to, more := Page(5, 2, 1)
+    fromLast, toLast, moreLast := Page(5, 2, 3)
+    fromAll, toAll, moreAll := Page(5, 0, 0)
+    fromOut, toOut, moreOut := Page(5, 2, 10)
+
+    assert.Equal(t, []interface{}{1, 3, true}, []interface{}{from, to, more}, "Страница в середине неверная")
+    assert.Equal(t, []interface{}{3, 5, false}, []interface{}{fromLast, toLast, moreLast}, "Последняя страница неверная")
+    assert.Equal(t, []interface{}{0, 5, false}, []interface{}{fromAll, toAll, moreAll}, "Без limit должны вернуться все строки")
+    assert.Equal(t, []interface{}{5, 5, false}, []interface{}{fromOut, toOut, moreOut}, "Страница за концом должна быть пустой")
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+    "database/sql/driver"
+    "reflect"
+
+    "github.com/pkg/errors"
+    "gorm.io/gorm/schema"
+)
+
+var ErrColumnNotFound = errors.New("Column not found")
+
+var naming = schema.NamingStrategy{}
+
+// Метод возвращает значение колонки column строки row. Имя колонки берется из тега gorm column,
+// а без него строится из имени поля так же, как в gorm. Пустой указатель возвращается как nil,
+// а значения вроде gorm.DeletedAt — в том виде, в котором их записал бы драйвер базы
+func Value(row interface{}, column string) (interface{}, error) {
+    field, err := fieldByColumn(row, column)
+    if err != nil {
+        return nil, err
+    }
+
+    if field.Kind() == reflect.Ptr {
+        if field.IsNil() {
+            return nil, nil
+        }
+        field = field.Elem()
+    }
+
+    value := field.Interface()
+    if valuer, ok := value.(driver.Valuer); ok {
+        return valuer.Value()
+    }
+    return value, nil
+}
+
+// Метод записывает в строку row значения колонок из values, как Updates в gorm.
+// Тип значения должен совпадать с типом поля, nil обнуляет поле
+func Assign(row interface{}, values map[string]interface{}) error {
+    for column, value := range values {
+        field, err := fieldByColumn(row, column)
+        if err != nil {
+            return err
+        }
+
+        if value == nil {
+            field.Set(reflect.Zero(field.Type()))
+            continue
+        }
+
+        v := reflect.ValueOf(value)
+        if !v.Type().AssignableTo(field.Type()) {
+            return errors.Errorf("Can't assign %T to column %s of type %s", value, column, field.Type())
+        }
+        field.Set(v)
+    }
+    return nil
+}
+
+func fieldByColumn(row interface{}, column string) (reflect.Value, error) {
+    v := reflect.Indirect(reflect.ValueOf(row))
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        f := t.Field(i)
+        settings := schema.ParseTagSetting(f.Tag.Get("gorm"), ";")
+        if _, ignored := settings["-"]; ignored {
+            continue
+        }
+
+        name := settings["COLUMN"]
+        if name == "" {
+            name = naming.ColumnName("", f.Name)
+        }
+        if name == column {
+            return v.Field(i), nil
+        }
+    }
+    return reflect.Value{}, errors.Wrapf(ErrColumnNotFound, "Column %s of %s", column, t)
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "gorm.io/gorm"
+)
+
+type record struct {
+    ID        uint64
+    UserId    uint64  `gorm:"column:userId"`
+    ParentId  *uint64 `gorm:"column:parentId"`
+    FirstName string
+    Count     int64          `gorm:"-"`
+    DeletedAt gorm.DeletedAt `gorm:"column:deletedAt"`
+}
+
+// ---------------
+// ---- Value ----
+// ---------------
+
+func Test_value_find_column_by_tag_and_by_gorm_naming(t *testing.T) {
+    r := &record{ID: 1, UserId: 2, FirstName: "Name"}
+
+    id, errId := Value(r, "id")
+    userId, errUserId := Value(r, "userId")
+    name, errName := Value(r, "first_name")
+
+    require.Nil(t, errId, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errUserId, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errName, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, uint64(1), id, "Значение колонки без тега неверное")
+    assert.Equal(t, uint64(2), userId, "Значение колонки из тега column неверное")
+    assert.Equal(t, "Name", name, "Имя колонки без тега должно строиться как в gorm")
+}
+
+func Test_value_return_nil_for_empty_pointer_and_value_of_pointer_otherwise(t *testing.T) {
+    parentId := uint64(3)
+
+    empty, errEmpty := Value(&record{}, "parentId")
+    value, errValue := Value(&record{ParentId: &parentId}, "parentId")
+
+    require.Nil(t, errEmpty, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errValue, "Возвращаемая ошибка должна быть пустой")
+    assert.Nil(t, empty, "Пустой указатель должен возвращаться как nil")
+    assert.Equal(t, uint64(3), value, "Для указателя должно возвращаться его значение")
+}
+
+func Test_value_return_driver_value_of_deleted_at(t *testing.T) {
+    now := time.Now()
+
+    empty, _ := Value(&record{}, "deletedAt")
+    deleted, _ := Value(&record{DeletedAt: gorm.DeletedAt{Time: now, Valid: true}}, "deletedAt")
+
+    assert.Nil(t, empty, "Пустое время удаления должно возвращаться как nil")
+    assert.Equal(t, now, deleted, "Для времени удаления должно возвращаться само время")
+}
+
+func Test_value_when_column_not_found_or_ignored_result_error_is_column_not_found(t *testing.T) {
+    _, errUnknown := Value(&record{}, "unknown")
+    _, errIgnored := Value(&record{}, "count")
+
+    assert.ErrorIs(t, errUnknown, ErrColumnNotFound, "Возвращаемая ошибка должна быть ErrColumnNotFound")
+    assert.ErrorIs(t, errIgnored, ErrColumnNotFound, "Колонка с тегом - не должна находиться")
+}
+
+// ----------------
+// ---- Assign ----
+// ----------------
+
+func Test_assign_set_values_of_columns(t *testing.T) {
+    parentId := uint64(3)
+    r := &record{ID: 1, UserId: 2, ParentId: &parentId}
+
+    errResult := Assign(r, map[string]interface{}{"userId": uint64(5), "first_name": "New", "parentId": nil})
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, &record{ID: 1, UserId: 5, FirstName: "New"}, r, "Результирующая строка неверная")
+}
+
+func Test_assign_when_type_of_value_differs_result_error_not_empty(t *testing.T) {
+    errResult := Assign(&record{}, map[string]interface{}{"userId": "5"})
+
+    assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
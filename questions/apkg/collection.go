@@ -0,0 +1,149 @@
+package apkg
+
+import "time"
+
+const schema = `
+CREATE TABLE col (
+    id integer primary key, crt integer not null, mod integer not null, scm integer not null,
+    ver integer not null, dty integer not null, usn integer not null, ls integer not null,
+    conf text not null, models text not null, decks text not null, dconf text not null, tags text not null
+);
+CREATE TABLE notes (
+    id integer primary key, guid text not null, mid integer not null, mod integer not null,
+    usn integer not null, tags text not null, flds text not null, sfld integer not null,
+    csum integer not null, flags integer not null, data text not null
+);
+CREATE TABLE cards (
+    id integer primary key, nid integer not null, did integer not null, ord integer not null,
+    mod integer not null, usn integer not null, type integer not null, queue integer not null,
+    due integer not null, ivl integer not null, factor integer not null, reps integer not null,
+    lapses integer not null, left integer not null, odue integer not null, odid integer not null,
+    flags integer not null, data text not null
+);
+CREATE TABLE revlog (
+    id integer primary key, cid integer not null, usn integer not null, ease integer not null,
+    ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null,
+    type integer not null
+);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+var confJson = map[string]interface{}{
+    "activeDecks":   []int{defaultDeckId},
+    "curDeck":       defaultDeckId,
+    "newSpread":     0,
+    "collapseTime":  1200,
+    "timeLim":       0,
+    "estTimes":      true,
+    "dueCounts":     true,
+    "curModel":      nil,
+    "nextPos":       1,
+    "sortType":      "noteFld",
+    "sortBackwards": false,
+    "addToCur":      true,
+}
+
+var dconfJson = map[string]interface{}{
+    "1": map[string]interface{}{
+        "id":       1,
+        "name":     "Default",
+        "mod":      0,
+        "usn":      0,
+        "maxTaken": 60,
+        "timer":    0,
+        "autoplay": true,
+        "replayq":  true,
+        "new": map[string]interface{}{
+            "perDay":        20,
+            "delays":        []int{1, 10},
+            "ints":          []int{1, 4, 7},
+            "initialFactor": defaultFactor,
+            "separate":      true,
+            "order":         1,
+            "bury":          false,
+        },
+        "rev": map[string]interface{}{
+            "perDay":   200,
+            "ease4":    1.3,
+            "fuzz":     0.05,
+            "ivlFct":   1,
+            "maxIvl":   36500,
+            "minSpace": 1,
+            "bury":     false,
+        },
+        "lapse": map[string]interface{}{
+            "delays":      []int{10},
+            "mult":        0,
+            "minInt":      1,
+            "leechFails":  8,
+            "leechAction": 0,
+        },
+    },
+}
+
+func deckJson(id int64, name string, now time.Time) map[string]interface{} {
+    return map[string]interface{}{
+        "id":        id,
+        "name":      name,
+        "desc":      "",
+        "mod":       now.Unix(),
+        "usn":       -1,
+        "conf":      1,
+        "dyn":       0,
+        "collapsed": false,
+        "extendNew": 10,
+        "extendRev": 50,
+        "newToday":  []int{0, 0},
+        "revToday":  []int{0, 0},
+        "lrnToday":  []int{0, 0},
+        "timeToday": []int{0, 0},
+    }
+}
+
+// Модель Basic с полями Front и Back
+func modelJson(now time.Time) map[string]interface{} {
+    field := func(name string, ord int) map[string]interface{} {
+        return map[string]interface{}{
+            "name":   name,
+            "ord":    ord,
+            "font":   "Arial",
+            "size":   20,
+            "media":  []string{},
+            "rtl":    false,
+            "sticky": false,
+        }
+    }
+
+    return map[string]interface{}{
+        "id":    modelId,
+        "name":  "Basic",
+        "type":  0,
+        "mod":   now.Unix(),
+        "usn":   -1,
+        "sortf": 0,
+        "did":   defaultDeckId,
+        "flds":  []interface{}{field("Front", 0), field("Back", 1)},
+        "tmpls": []interface{}{map[string]interface{}{
+            "name":  "Card 1",
+            "ord":   0,
+            "qfmt":  "{{Front}}",
+            "afmt":  "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}",
+            "did":   nil,
+            "bqfmt": "",
+            "bafmt": "",
+        }},
+        "css":       ".card {\n font-family: arial;\n font-size: 20px;\n text-align: center;\n color: black;\n background-color: white;\n}\n",
+        "latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+        "latexPost": "\\end{document}",
+        "tags":      []string{},
+        "vers":      []interface{}{},
+        "req":       []interface{}{[]interface{}{0, "all", []int{0}}},
+    }
+}
@@ -0,0 +1,237 @@
+package apkg
+
+import (
+    "archive/zip"
+    "database/sql"
+    "encoding/json"
+    "io"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+
+    "github.com/pkg/errors"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+const (
+    cardNew        = 0
+    cardLearning   = 1
+    cardReview     = 2
+    cardRelearning = 3
+
+    // Очередь изучения с шагами от суток: due такой карточки хранится днем от создания коллекции,
+    // а не временем в секундах, как у остальных изучаемых карточек
+    queueDayLearning = 3
+
+    // Средняя сложность FSRS для карточек, у которых в Anki нет такой оценки
+    importDifficulty = 5
+
+    // Максимальный размер распакованной коллекции. Коллекция распаковывается на диск,
+    // поэтому маленький архив не должен разворачиваться в файл произвольного размера
+    maxCollectionSize = 128 << 20
+)
+
+var (
+    ErrCollectionNotFound = errors.New("Package has no collection supported for import. Export it from Anki with \"Support older Anki versions\" enabled")
+    ErrCollectionTooLarge = errors.Errorf("Package collection must be at most %d MB", maxCollectionSize>>20)
+)
+
+// Карточка колоды Anki, переведенная в вопрос
+type Card struct {
+    Deck     string
+    Question questions.Question
+}
+
+// Метод читает карточки из архива колоды Anki.
+// Для новых карточек расписание не заполняется, для остальных переводится
+// из интервала и срока Anki в поля расписания вопроса
+func Read(r io.ReaderAt, size int64) ([]Card, error) {
+    z, err := zip.NewReader(r, size)
+    if err != nil {
+        return nil, errors.Wrap(err, "Can't open package archive")
+    }
+
+    entry := findCollection(z)
+    if entry == nil {
+        return nil, ErrCollectionNotFound
+    }
+
+    dir, err := ioutil.TempDir("", "apkg")
+    if err != nil {
+        return nil, errors.Wrap(err, "Can't create temp dir for collection")
+    }
+    defer os.RemoveAll(dir)
+
+    path := filepath.Join(dir, "collection")
+    if err := extract(entry, path, maxCollectionSize); err != nil {
+        return nil, errors.Wrap(err, "Can't extract collection")
+    }
+
+    db, err := sql.Open("sqlite3", path)
+    if err != nil {
+        return nil, errors.Wrap(err, "Can't open collection")
+    }
+    defer db.Close()
+
+    return readCards(db)
+}
+
+// Anki 2.1 кладет в архив collection.anki21, старые версии — collection.anki2.
+// Новый формат collection.anki21b сжат zstd и не поддерживается
+func findCollection(z *zip.Reader) *zip.File {
+    files := map[string]*zip.File{}
+    for _, f := range z.File {
+        files[f.Name] = f
+    }
+
+    for _, name := range []string{"collection.anki21", "collection.anki2"} {
+        if f, found := files[name]; found {
+            return f
+        }
+    }
+    return nil
+}
+
+// Размер из заголовка архива может быть подделан, поэтому распаковка
+// обрывается и по фактическому размеру больше limit
+func extract(f *zip.File, path string, limit int64) error {
+    if f.UncompressedSize64 > uint64(limit) {
+        return ErrCollectionTooLarge
+    }
+
+    r, err := f.Open()
+    if err != nil {
+        return err
+    }
+    defer r.Close()
+
+    out, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    defer out.Close()
+
+    n, err := io.Copy(out, io.LimitReader(r, limit+1))
+    if err != nil {
+        return err
+    }
+    if n > limit {
+        return ErrCollectionTooLarge
+    }
+    return nil
+}
+
+type ankiCard struct {
+    ord      int
+    deckId   int64
+    cardType int
+    queue    int
+    due      int64
+    ivl      int64
+    factor   int64
+    reps     int64
+    lapses   int64
+    fields   string
+    tags     string
+}
+
+func readCards(db *sql.DB) ([]Card, error) {
+    var crt int64
+    var decksJson string
+    if err := db.QueryRow("SELECT crt, decks FROM col").Scan(&crt, &decksJson); err != nil {
+        return nil, errors.Wrap(err, "Can't read collection settings")
+    }
+
+    decks := map[string]struct {
+        Name string `json:"name"`
+    }{}
+    if err := json.Unmarshal([]byte(decksJson), &decks); err != nil {
+        return nil, errors.Wrap(err, "Can't parse collection decks")
+    }
+
+    rows, err := db.Query(`SELECT c.ord, c.did, c.type, c.queue, c.due, c.ivl, c.factor, c.reps, c.lapses, n.flds, n.tags
+        FROM cards c JOIN notes n ON n.id = c.nid ORDER BY c.id`)
+    if err != nil {
+        return nil, errors.Wrap(err, "Can't read cards")
+    }
+    defer rows.Close()
+
+    cards := []Card{}
+    for rows.Next() {
+        c := ankiCard{}
+        err := rows.Scan(&c.ord, &c.deckId, &c.cardType, &c.queue, &c.due, &c.ivl, &c.factor, &c.reps, &c.lapses, &c.fields, &c.tags)
+        if err != nil {
+            return nil, errors.Wrap(err, "Can't scan card")
+        }
+
+        deck := decks[jsonId(c.deckId)].Name
+        if deck == "" {
+            deck = "Default"
+        }
+        cards = append(cards, Card{Deck: deck, Question: c.toQuestion(time.Unix(crt, 0))})
+    }
+
+    return cards, errors.Wrap(rows.Err(), "Can't read cards")
+}
+
+// Для обратной карточки (ord 1) вопрос и ответ меняются местами
+func (c *ankiCard) toQuestion(crt time.Time) questions.Question {
+    fields := strings.Split(c.fields, fieldSep)
+    front := fields[0]
+    back := ""
+    if len(fields) > 1 {
+        back = fields[1]
+    }
+    if c.ord == 1 && len(fields) > 1 {
+        front, back = back, front
+    }
+
+    q := questions.Question{Title: front, Body: back}
+    if tags := strings.Fields(c.tags); len(tags) > 0 {
+        q.Tags = questions.NewTags(tags)
+    }
+    if c.factor > 0 {
+        q.Ease = float64(c.factor) / 1000
+    }
+
+    switch c.cardType {
+    case cardLearning, cardRelearning:
+        q.Step = 1
+        q.IsFailed = c.cardType == cardRelearning
+        q.RepeatTime = time.Unix(c.due, 0)
+        if c.queue == queueDayLearning {
+            q.RepeatTime = crt.Add(day * time.Duration(c.due))
+        }
+    case cardReview:
+        q.Step = reviewStep(c.reps, c.lapses)
+        q.Interval = uint32(c.ivl)
+        q.RepeatTime = crt.Add(day * time.Duration(c.due))
+        q.LastReview = q.RepeatTime.Add(-day * time.Duration(c.ivl))
+        // При целевой вероятности вспомнить 90% интервал FSRS равен стабильности
+        q.Stability = float64(c.ivl)
+        q.Difficulty = importDifficulty
+    }
+    return q
+}
+
+// Step — номер успешного повторения, как у SM-2 и FSRS. Экспорт записывает его в reps,
+// поэтому обратно он считается по повторениям без забываний. Карточка в повторении
+// уже прошла изучение, и Step у нее не меньше 2
+func reviewStep(reps, lapses int64) uint8 {
+    step := reps - lapses
+    switch {
+    case step < 2:
+        return 2
+    case step > 255:
+        return 255
+    }
+    return uint8(step)
+}
+
+func jsonId(id int64) string {
+    b, _ := json.Marshal(id)
+    return string(b)
+}
@@ -0,0 +1,134 @@
+package apkg
+
+import (
+    "archive/zip"
+    "bytes"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+func Test_read_return_cards_written_by_writer(t *testing.T) {
+    now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+    w, err := NewWriter(now)
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    defer w.Close()
+
+    require.Nil(t, w.Add(&questions.Question{ID: 1, Title: "Front 1", Body: "Back 1", Step: 1, RepeatTime: now.Add(time.Minute * 30), Tags: []questions.Tag{{Name: "weak"}, {Name: "exam-2026"}}}, "Subject::Topic"))
+    require.Nil(t, w.Add(&questions.Question{ID: 2, Title: "Front 2", Body: "Back 2", Step: 3, Interval: 6, Ease: 2.36, RepeatTime: now.Add(day * 4)}, "Subject"))
+
+    buf := &bytes.Buffer{}
+    require.Nil(t, w.Save(buf))
+
+    cards, errResult := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, cards, 2, "Должны вернуться все карточки коллекции")
+
+    assert.Equal(t, "Subject::Topic", cards[0].Deck, "Колода карточки неверная")
+    assert.Equal(t, "Front 1", cards[0].Question.Title, "Вопрос должен браться из первого поля")
+    assert.Equal(t, "Back 1", cards[0].Question.Body, "Ответ должен браться из второго поля")
+    assert.True(t, cards[0].Question.RepeatTime.IsZero(), "У новой карточки расписание не должно заполняться")
+    assert.Equal(t, []questions.Tag{{Name: "weak"}, {Name: "exam-2026"}}, cards[0].Question.Tags, "Метки заметки должны стать метками вопроса")
+    assert.Nil(t, cards[1].Question.Tags, "У заметки без меток не должно быть меток")
+
+    review := cards[1].Question
+    assert.Equal(t, "Subject", cards[1].Deck, "Колода карточки неверная")
+    assert.Equal(t, uint8(3), review.Step, "Step должен переноситься из карточки")
+    assert.Equal(t, uint32(6), review.Interval, "Interval должен переноситься из карточки")
+    assert.Equal(t, 2.36, review.Ease, "Ease должен переноситься из карточки")
+    assert.WithinDuration(t, time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC), review.RepeatTime, 0, "RepeatTime должно быть днем повторения карточки")
+}
+
+func Test_read_when_card_is_in_day_learning_queue_result_repeat_time_is_day_of_collection(t *testing.T) {
+    now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+    w, err := NewWriter(now)
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    defer w.Close()
+
+    require.Nil(t, w.Add(&questions.Question{ID: 1, Title: "Front", Body: "Back", Step: 1}, "Default"))
+    _, err = w.(*writer).db.Exec("UPDATE cards SET type = ?, queue = ?, due = ?", cardRelearning, queueDayLearning, 3)
+    require.Nil(t, err)
+
+    buf := &bytes.Buffer{}
+    require.Nil(t, w.Save(buf))
+
+    cards, errResult := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, cards, 1, "Должны вернуться все карточки коллекции")
+    assert.True(t, cards[0].Question.IsFailed, "Забытая карточка должна быть помечена как проваленная")
+    assert.WithinDuration(t, time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), cards[0].Question.RepeatTime, 0, "RepeatTime должно быть днем повторения карточки")
+}
+
+func Test_read_when_collection_not_found_result_error_is_collection_not_found(t *testing.T) {
+    buf := &bytes.Buffer{}
+    z := zip.NewWriter(buf)
+    _, err := z.Create("collection.anki21b")
+    require.Nil(t, err)
+    require.Nil(t, z.Close())
+
+    _, errResult := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+    assert.ErrorIs(t, errResult, ErrCollectionNotFound, "Для архива без поддерживаемой коллекции должна вернуться ErrCollectionNotFound")
+}
+
+func Test_extract_when_collection_is_over_limit_result_error_is_collection_too_large(t *testing.T) {
+    buf := &bytes.Buffer{}
+    z := zip.NewWriter(buf)
+    f, err := z.Create("collection.anki2")
+    require.Nil(t, err)
+    _, err = f.Write(make([]byte, 100))
+    require.Nil(t, err)
+    require.Nil(t, z.Close())
+    zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+    require.Nil(t, err)
+
+    errResult := extract(zr.File[0], filepath.Join(t.TempDir(), "collection"), 50)
+
+    assert.ErrorIs(t, errResult, ErrCollectionTooLarge, "Коллекция больше ограничения не должна распаковываться")
+}
+
+func Test_read_when_file_is_not_archive_result_error_not_empty(t *testing.T) {
+    data := []byte("not a zip")
+
+    _, errResult := Read(bytes.NewReader(data), int64(len(data)))
+
+    assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
+
+func Test_anki_card_to_question_translate_schedule(t *testing.T) {
+    crt := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+    due := time.Unix(time.Date(2021, 3, 1, 12, 30, 0, 0, time.UTC).Unix(), 0)
+
+    reversed := (&ankiCard{ord: 1, fields: "Front\x1fBack"}).toQuestion(crt)
+    learning := (&ankiCard{cardType: cardLearning, due: due.Unix(), factor: 2500}).toQuestion(crt)
+    relearning := (&ankiCard{cardType: cardRelearning, due: due.Unix()}).toQuestion(crt)
+    dayLearning := (&ankiCard{cardType: cardLearning, queue: queueDayLearning, due: 2}).toQuestion(crt)
+    review := (&ankiCard{cardType: cardReview, due: 10, ivl: 4, factor: 2100, reps: 5, lapses: 1}).toQuestion(crt)
+    firstReview := (&ankiCard{cardType: cardReview, due: 1, ivl: 1, reps: 1, lapses: 1}).toQuestion(crt)
+
+    assert.Equal(t, "Back", reversed.Title, "У обратной карточки вопросом должно быть второе поле")
+    assert.Equal(t, "Front", reversed.Body, "У обратной карточки ответом должно быть первое поле")
+
+    assert.Equal(t, questions.Question{Step: 1, Ease: 2.5, RepeatTime: due}, learning, "Изучаемая карточка должна повторяться в свой срок с первого шага")
+    assert.Equal(t, questions.Question{Step: 1, IsFailed: true, RepeatTime: due}, relearning, "Забытая карточка должна быть помечена как проваленная")
+    assert.Equal(t, questions.Question{Step: 1, RepeatTime: crt.Add(day * 2)}, dayLearning, "Карточка с шагом от суток должна повторяться в свой день")
+
+    repeatTime := crt.Add(day * 10)
+    assert.Equal(t, questions.Question{
+        Step:       4,
+        Ease:       2.1,
+        Interval:   4,
+        Stability:  4,
+        Difficulty: importDifficulty,
+        RepeatTime: repeatTime,
+        LastReview: repeatTime.Add(-day * 4),
+    }, review, "Расписание карточки в повторении должно переводиться в поля вопроса")
+    assert.Equal(t, uint8(2), firstReview.Step, "Step карточки в повторении должен быть не меньше 2")
+}
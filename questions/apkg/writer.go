@@ -0,0 +1,247 @@
+package apkg
+
+import (
+    "archive/zip"
+    "crypto/sha1"
+    "database/sql"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "io"
+    "io/ioutil"
+    "math"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "time"
+
+    _ "github.com/mattn/go-sqlite3"
+    "github.com/pkg/errors"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+const (
+    // Версия схемы коллекции Anki 2.1, которую понимают все клиенты
+    schemaVersion = 11
+    modelId       = 1342697561419
+    defaultDeckId = 1
+    // Колоды получают id из этого диапазона, чтобы не пересекаться с колодой по умолчанию
+    deckIdBase    = 1500000000000
+    defaultFactor = 2500
+    fieldSep      = "\x1f"
+    day           = time.Hour * 24
+)
+
+// Колода Anki (.apkg): zip-архив с SQLite-коллекцией collection.anki2 и списком медиафайлов
+type Writer interface {
+    // Метод добавляет вопрос заметкой с полями Front и Back в колоду с указанным именем.
+    // Вложенные колоды разделяются "::"
+    Add(q *questions.Question, deck string) error
+    // Метод записывает архив колоды в w
+    Save(w io.Writer) error
+    // Метод удаляет временные файлы коллекции
+    Close() error
+}
+
+type writer struct {
+    dir   string
+    db    *sql.DB
+    now   time.Time
+    crt   time.Time
+    decks map[string]int64
+    count int64
+}
+
+func NewWriter(now time.Time) (Writer, error) {
+    dir, err := ioutil.TempDir("", "apkg")
+    if err != nil {
+        return nil, errors.Wrap(err, "Can't create temp dir for collection")
+    }
+
+    db, err := sql.Open("sqlite3", filepath.Join(dir, "collection.anki2"))
+    if err != nil {
+        _ = os.RemoveAll(dir)
+        return nil, errors.Wrap(err, "Can't open collection")
+    }
+
+    w := &writer{
+        dir:   dir,
+        db:    db,
+        now:   now,
+        crt:   time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+        decks: map[string]int64{},
+    }
+
+    if _, err := db.Exec(schema); err != nil {
+        _ = w.Close()
+        return nil, errors.Wrap(err, "Can't create collection schema")
+    }
+    return w, nil
+}
+
+func (w *writer) Add(q *questions.Question, deck string) error {
+    deckId, found := w.decks[deck]
+    if !found {
+        deckId = deckIdBase + int64(len(w.decks)) + 1
+        w.decks[deck] = deckId
+    }
+
+    w.count++
+    id := w.now.UnixNano()/int64(time.Millisecond) + w.count
+    mod := w.now.Unix()
+
+    _, err := w.db.Exec(
+        "INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')",
+        id, guid(q), modelId, mod, noteTags(q), q.Title+fieldSep+q.Body, q.Title, checksum(q.Title),
+    )
+    if err != nil {
+        return errors.Wrapf(err, "Can't insert note for question %d", q.ID)
+    }
+
+    s := w.schedule(q)
+    _, err = w.db.Exec(
+        "INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, ?, ?, ?, ?, ?, ?, ?, 0, 0, 0, 0, '')",
+        id, id, deckId, mod, s.cardType, s.queue, s.due, s.ivl, s.factor, s.reps, s.lapses,
+    )
+    if err != nil {
+        return errors.Wrapf(err, "Can't insert card for question %d", q.ID)
+    }
+    return nil
+}
+
+func (w *writer) Save(out io.Writer) error {
+    if err := w.writeCollection(); err != nil {
+        return errors.Wrap(err, "Can't write collection settings")
+    }
+    if err := w.db.Close(); err != nil {
+        return errors.Wrap(err, "Can't close collection")
+    }
+
+    z := zip.NewWriter(out)
+    if err := addFile(z, "collection.anki2", filepath.Join(w.dir, "collection.anki2")); err != nil {
+        return errors.Wrap(err, "Can't add collection to archive")
+    }
+
+    media, err := z.Create("media")
+    if err != nil {
+        return errors.Wrap(err, "Can't add media to archive")
+    }
+    if _, err := media.Write([]byte("{}")); err != nil {
+        return errors.Wrap(err, "Can't write media to archive")
+    }
+
+    return errors.Wrap(z.Close(), "Can't close archive")
+}
+
+func (w *writer) Close() error {
+    _ = w.db.Close()
+    return errors.Wrap(os.RemoveAll(w.dir), "Can't remove collection dir")
+}
+
+func (w *writer) writeCollection() error {
+    decks := map[string]interface{}{
+        strconv.Itoa(defaultDeckId): deckJson(defaultDeckId, "Default", w.now),
+    }
+    for name, id := range w.decks {
+        decks[strconv.FormatInt(id, 10)] = deckJson(id, name, w.now)
+    }
+
+    models := map[string]interface{}{
+        strconv.Itoa(modelId): modelJson(w.now),
+    }
+
+    values := []interface{}{}
+    for _, v := range []interface{}{confJson, models, decks, dconfJson} {
+        b, err := json.Marshal(v)
+        if err != nil {
+            return errors.Wrap(err, "Can't marshal collection settings")
+        }
+        values = append(values, string(b))
+    }
+
+    ms := w.now.UnixNano() / int64(time.Millisecond)
+    _, err := w.db.Exec(
+        "INSERT INTO col VALUES (1, ?, ?, ?, ?, 0, 0, 0, ?, ?, ?, ?, '{}')",
+        append([]interface{}{w.crt.Unix(), ms, ms, schemaVersion}, values...)...,
+    )
+    return err
+}
+
+type cardSchedule struct {
+    cardType int
+    queue    int
+    due      int64
+    ivl      int64
+    factor   int64
+    reps     int64
+    lapses   int64
+}
+
+// Метод переводит расписание вопроса в расписание карточки Anki.
+// Вопросы, которые еще не повторялись, становятся новыми карточками в порядке добавления,
+// остальные — карточками на повторении со сроком в днях от создания коллекции
+func (w *writer) schedule(q *questions.Question) cardSchedule {
+    if q.LastReview.IsZero() && q.Interval == 0 && q.Step <= 1 && !q.IsFailed {
+        return cardSchedule{due: w.count, factor: defaultFactor}
+    }
+
+    s := cardSchedule{cardType: 2, queue: 2, reps: int64(q.Step), factor: defaultFactor}
+    s.due = int64(math.Max(0, math.Floor(q.RepeatTime.Sub(w.crt).Hours()/24)))
+
+    switch {
+    case q.Interval > 0:
+        s.ivl = int64(q.Interval)
+    case !q.LastReview.IsZero():
+        s.ivl = int64(math.Round(q.RepeatTime.Sub(q.LastReview).Hours() / 24))
+    default:
+        s.ivl = int64(math.Round(q.RepeatTime.Sub(w.now).Hours() / 24))
+    }
+    if s.ivl < 1 {
+        s.ivl = 1
+    }
+
+    if q.Ease > 0 {
+        s.factor = int64(math.Round(q.Ease * 1000))
+    }
+    if q.IsFailed {
+        s.lapses = 1
+    }
+    return s
+}
+
+// Anki хранит метки заметки через пробел с пробелами по краям
+func noteTags(q *questions.Question) string {
+    if len(q.Tags) == 0 {
+        return ""
+    }
+    return " " + strings.Join(questions.TagNames(q.Tags), " ") + " "
+}
+
+// Guid зависит только от id вопроса, поэтому повторный импорт в Anki обновляет заметки, а не дублирует их
+func guid(q *questions.Question) string {
+    return fmt.Sprintf("rc-%d", q.ID)
+}
+
+// Контрольная сумма первого поля по правилам Anki: первые 8 hex-символов sha1
+func checksum(field string) int64 {
+    sum := sha1.Sum([]byte(strings.TrimSpace(field)))
+    result, _ := strconv.ParseInt(hex.EncodeToString(sum[:])[:8], 16, 64)
+    return result
+}
+
+func addFile(z *zip.Writer, name, path string) error {
+    f, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    entry, err := z.Create(name)
+    if err != nil {
+        return err
+    }
+    _, err = io.Copy(entry, f)
+    return err
+}
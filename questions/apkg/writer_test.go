@@ -0,0 +1,122 @@
+package apkg
+
+import (
+    "archive/zip"
+    "bytes"
+    "database/sql"
+    "encoding/json"
+    "io"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+// Метод распаковывает архив колоды и открывает коллекцию из него
+func openCollection(t *testing.T, data []byte) *sql.DB {
+    z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+    require.Nil(t, err, "Архив колоды должен читаться")
+
+    names := []string{}
+    dir, err := ioutil.TempDir("", "apkg_test")
+    require.Nil(t, err)
+    t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+    for _, f := range z.File {
+        names = append(names, f.Name)
+        if f.Name != "collection.anki2" {
+            continue
+        }
+        r, err := f.Open()
+        require.Nil(t, err)
+        out, err := os.Create(filepath.Join(dir, f.Name))
+        require.Nil(t, err)
+        _, err = io.Copy(out, r)
+        require.Nil(t, err)
+        require.Nil(t, out.Close())
+        require.Nil(t, r.Close())
+    }
+    require.ElementsMatch(t, []string{"collection.anki2", "media"}, names, "Архив должен содержать коллекцию и список медиа")
+
+    db, err := sql.Open("sqlite3", filepath.Join(dir, "collection.anki2"))
+    require.Nil(t, err)
+    t.Cleanup(func() { _ = db.Close() })
+    return db
+}
+
+func Test_writer_save_write_notes_cards_and_decks_to_collection(t *testing.T) {
+    now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+    w, err := NewWriter(now)
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    defer w.Close()
+
+    require.Nil(t, w.Add(&questions.Question{ID: 1, Title: "Front 1", Body: "Back 1"}, "Subject::Topic"))
+    require.Nil(t, w.Add(&questions.Question{ID: 2, Title: "Front 2", Body: "Back 2"}, "Subject"))
+    require.Nil(t, w.Add(&questions.Question{ID: 3, Title: "Front 3", Body: "Back 3"}, "Subject"))
+
+    buf := &bytes.Buffer{}
+    require.Nil(t, w.Save(buf), "Возвращаемая ошибка должна быть пустой")
+    db := openCollection(t, buf.Bytes())
+
+    var flds, guid string
+    require.Nil(t, db.QueryRow("SELECT flds, guid FROM notes ORDER BY id LIMIT 1").Scan(&flds, &guid))
+    assert.Equal(t, "Front 1\x1fBack 1", flds, "Поля заметки должны содержать вопрос и ответ")
+    assert.Equal(t, "rc-1", guid, "Guid заметки должен строиться по id вопроса")
+
+    var cards, decksUsed int
+    require.Nil(t, db.QueryRow("SELECT count(*), count(DISTINCT did) FROM cards").Scan(&cards, &decksUsed))
+    assert.Equal(t, 3, cards, "Для каждого вопроса должна быть создана карточка")
+    assert.Equal(t, 2, decksUsed, "Карточки должны быть разложены по двум колодам")
+
+    var decksJson string
+    var ver int
+    require.Nil(t, db.QueryRow("SELECT decks, ver FROM col").Scan(&decksJson, &ver))
+    decks := map[string]struct {
+        Name string `json:"name"`
+    }{}
+    require.Nil(t, json.Unmarshal([]byte(decksJson), &decks))
+    deckNames := []string{}
+    for _, d := range decks {
+        deckNames = append(deckNames, d.Name)
+    }
+    assert.ElementsMatch(t, []string{"Default", "Subject", "Subject::Topic"}, deckNames, "Коллекция должна содержать колоды групп")
+    assert.Equal(t, schemaVersion, ver, "Версия схемы коллекции неверная")
+}
+
+func Test_writer_schedule_new_question_is_new_card(t *testing.T) {
+    now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+    w := &writer{now: now, crt: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), count: 3}
+
+    s := w.schedule(&questions.Question{Step: 1, RepeatTime: now.Add(time.Minute * 30)})
+
+    assert.Equal(t, cardSchedule{due: 3, factor: defaultFactor}, s, "Неповторявшийся вопрос должен стать новой карточкой")
+}
+
+func Test_writer_schedule_reviewed_question_is_review_card(t *testing.T) {
+    now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+    w := &writer{now: now, crt: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+    sm2 := w.schedule(&questions.Question{Step: 3, Interval: 6, Ease: 2.36, RepeatTime: now.Add(day * 4)})
+    ladder := w.schedule(&questions.Question{Step: 2, RepeatTime: now.Add(day * 14)})
+    failed := w.schedule(&questions.Question{Step: 1, IsFailed: true, RepeatTime: now.Add(-day)})
+
+    assert.Equal(t, cardSchedule{cardType: 2, queue: 2, due: 4, ivl: 6, factor: 2360, reps: 3}, sm2, "Расписание SM-2 должно переноситься в карточку")
+    assert.Equal(t, cardSchedule{cardType: 2, queue: 2, due: 14, ivl: 14, factor: defaultFactor, reps: 2}, ladder, "Интервал лестницы должен считаться до времени повторения")
+    assert.Equal(t, cardSchedule{cardType: 2, queue: 2, due: 0, ivl: 1, factor: defaultFactor, reps: 1, lapses: 1}, failed, "Просроченная карточка должна быть к повторению сегодня")
+}
+
+func Test_writer_close_remove_temp_files(t *testing.T) {
+    w, err := NewWriter(time.Now())
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+
+    require.Nil(t, w.Close(), "Возвращаемая ошибка должна быть пустой")
+
+    _, errStat := os.Stat(w.(*writer).dir)
+    assert.True(t, os.IsNotExist(errStat), "Временная папка коллекции должна быть удалена")
+}
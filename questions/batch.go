@@ -0,0 +1,43 @@
+package questions
+
+import (
+    "time"
+
+    "github.com/pkg/errors"
+)
+
+var ErrQuestionNotFound = errors.New("Question not found")
+
+type BatchAction string
+
+const (
+    BatchAdd     BatchAction = "add"
+    BatchCorrect BatchAction = "correct"
+    BatchDelete  BatchAction = "delete"
+    BatchAnswer  BatchAction = "answer"
+)
+
+// Операция пакета. Для add Question — новый вопрос, для correct — новые вопрос, ответ,
+// группа и метки вопроса с id ID. Version задает ожидаемую версию вопроса, 0 — любую
+type BatchOperation struct {
+    Action       BatchAction
+    ID           uint64
+    Version      uint64
+    Question     *Question
+    Grade        Grade
+    ResponseTime time.Duration
+}
+
+// Результат операции пакета: вопрос после операции или ошибка, из-за которой
+// операция пропущена. Для delete вопрос пустой
+type BatchResult struct {
+    Question *Question
+    Err      error
+}
+
+// Ошибки, при которых пропускается только сама операция, а не весь пакет
+func isBatchItemError(err error) bool {
+    return errors.Is(err, ErrQuestionNotFound) ||
+        errors.Is(err, ErrGroupNotFound) ||
+        errors.Is(err, ErrVersionConflict)
+}
@@ -1,8 +1,35 @@
 package questions
 
+import (
+    "context"
+    "time"
+)
+
 type Dao interface {
-    Create(q *Question) error
-    Update(q *Question, fields []string) error
-    Delete(conds ...interface{}) error
-    Find(conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]Question, more bool, err error)
+    Create(ctx context.Context, q *Question) error
+    Update(ctx context.Context, q *Question, fields []string) error
+    // Метод переносит в корзину вопросы, подходящие под условия запроса.
+    // Сортировка и страница не учитываются. Если запрос проверяет версию
+    // и ни один вопрос не подошел, возвращается ErrVersionConflict
+    Delete(ctx context.Context, query *Query) error
+    Find(ctx context.Context, query *Query) (list *[]Question, more bool, err error)
+    // Метод ищет вопросы только среди вопросов в корзине
+    FindDeleted(ctx context.Context, query *Query) (list *[]Question, more bool, err error)
+    // Метод возвращает вопрос из корзины, если его версия не изменилась, иначе возвращает ErrVersionConflict
+    Restore(ctx context.Context, q *Question) error
+    // Метод окончательно удаляет вопросы, перенесенные в корзину раньше deletedBefore,
+    // вместе с их метками и историей повторений, и возвращает количество удаленных вопросов
+    Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+    // Метод ищет вопросы, в вопросе или ответе которых есть все слова text,
+    // начиная с самых подходящих. Сортировка запроса не учитывается
+    Search(ctx context.Context, text string, query *Query) (list *[]Question, more bool, err error)
+    CountByGroup(ctx context.Context, query *Query, dueBefore time.Time) (*[]GroupCount, error)
+    // Метод выполняет fc в транзакции и передает в нее dao вопросов и историю повторений,
+    // работающие в этой транзакции. Если fc возвращает ошибку, транзакция откатывается
+    WithTx(ctx context.Context, fc func(dao Dao, reviewDao ReviewDao) error) error
+}
+
+type ReviewDao interface {
+    Create(ctx context.Context, r *Review) error
+    Find(ctx context.Context, query *Query) (list *[]Review, more bool, err error)
 }
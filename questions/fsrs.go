@@ -0,0 +1,147 @@
+package questions
+
+import (
+    "math"
+    "time"
+)
+
+const (
+    fsrsRequestRetention = 0.9
+    fsrsMaxInterval      = 36500
+    fsrsMinDifficulty    = 1
+    fsrsMaxDifficulty    = 10
+    fsrsMinStability     = 0.1
+
+    // Через сколько повторить вопрос, который не вспомнили
+    fsrsRelearnDelay = time.Minute * 10
+)
+
+// Параметры модели FSRS v4 по умолчанию
+var fsrsDefaultWeights = [17]float64{
+    0.4, 0.6, 2.4, 5.8,
+    4.93, 0.94, 0.86, 0.01,
+    1.49, 0.14, 0.94,
+    2.18, 0.05, 0.34, 1.26,
+    0.29, 2.61,
+}
+
+// Алгоритм Free Spaced Repetition Scheduler.
+// Для каждой карточки хранятся стабильность (через сколько дней вероятность вспомнить падает до 90%)
+// и сложность (от 1 до 10), по ним вычисляется следующий интервал.
+// Step хранит номер успешного повторения, начиная с 1 для новой карточки
+type fsrsScheduler struct {
+    w         [17]float64
+    retention float64
+}
+
+func newFsrsScheduler() *fsrsScheduler {
+    return &fsrsScheduler{
+        w:         fsrsDefaultWeights,
+        retention: fsrsRequestRetention,
+    }
+}
+
+func (s *fsrsScheduler) Init(q *Question, now time.Time) {
+    q.Step = 1
+    q.Stability = 0
+    q.Difficulty = 0
+    q.Interval = 0
+    q.LastReview = time.Time{}
+    q.RepeatTime = now
+    q.IsFailed = false
+}
+
+func (s *fsrsScheduler) Answer(q *Question, grade Grade, now time.Time) {
+    if grade < GradeAgain || grade > GradeEasy {
+        grade = GradeAgain
+    }
+
+    if q.Stability == 0 {
+        q.Stability = s.initStability(grade)
+        q.Difficulty = s.initDifficulty(grade)
+    } else {
+        elapsed := now.Sub(q.LastReview).Hours() / 24
+        if elapsed < 0 {
+            elapsed = 0
+        }
+        r := s.retrievability(elapsed, q.Stability)
+
+        lastDifficulty := q.Difficulty
+        q.Difficulty = s.nextDifficulty(lastDifficulty, grade)
+        if grade == GradeAgain {
+            q.Stability = s.forgetStability(lastDifficulty, q.Stability, r)
+        } else {
+            q.Stability = s.recallStability(lastDifficulty, q.Stability, r, grade)
+        }
+    }
+    q.LastReview = now
+
+    if grade == GradeAgain {
+        q.Step = 1
+        q.Interval = 0
+        q.IsFailed = true
+        q.RepeatTime = now.Add(fsrsRelearnDelay)
+        return
+    }
+
+    if q.Step < math.MaxUint8 {
+        q.Step++
+    }
+    q.Interval = s.nextInterval(q.Stability)
+    q.IsFailed = false
+    q.RepeatTime = now.Add(time.Hour * 24 * time.Duration(q.Interval))
+}
+
+func (s *fsrsScheduler) initStability(g Grade) float64 {
+    return math.Max(s.w[g-1], fsrsMinStability)
+}
+
+func (s *fsrsScheduler) initDifficulty(g Grade) float64 {
+    return s.clampDifficulty(s.w[4] - float64(int(g)-3)*s.w[5])
+}
+
+// Сложность смещается в зависимости от оценки и возвращается к сложности оценки good
+func (s *fsrsScheduler) nextDifficulty(d float64, g Grade) float64 {
+    next := d - s.w[6]*float64(int(g)-3)
+    return s.clampDifficulty(s.w[7]*s.initDifficulty(GradeGood) + (1-s.w[7])*next)
+}
+
+// Вероятность вспомнить вопрос спустя elapsed дней
+func (s *fsrsScheduler) retrievability(elapsed, stability float64) float64 {
+    return math.Pow(1+elapsed/(9*stability), -1)
+}
+
+func (s *fsrsScheduler) recallStability(d, stability, r float64, g Grade) float64 {
+    hardPenalty := 1.0
+    if g == GradeHard {
+        hardPenalty = s.w[15]
+    }
+    easyBonus := 1.0
+    if g == GradeEasy {
+        easyBonus = s.w[16]
+    }
+
+    return stability * (1 + math.Exp(s.w[8])*
+        (11-d)*
+        math.Pow(stability, -s.w[9])*
+        (math.Exp((1-r)*s.w[10])-1)*
+        hardPenalty*
+        easyBonus)
+}
+
+func (s *fsrsScheduler) forgetStability(d, stability, r float64) float64 {
+    return math.Max(s.w[11]*
+        math.Pow(d, -s.w[12])*
+        (math.Pow(stability+1, s.w[13])-1)*
+        math.Exp((1-r)*s.w[14]), fsrsMinStability)
+}
+
+// Интервал в днях, через который вероятность вспомнить упадет до заданной
+func (s *fsrsScheduler) nextInterval(stability float64) uint32 {
+    interval := math.Round(9 * stability * (1/s.retention - 1))
+    return uint32(math.Min(math.Max(interval, 1), fsrsMaxInterval))
+}
+
+func (s *fsrsScheduler) clampDifficulty(d float64) float64 {
+    return math.Min(math.Max(d, fsrsMinDifficulty), fsrsMaxDifficulty)
+}
@@ -0,0 +1,85 @@
+package questions
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_fsrs_init_reset_memory_state_and_due_now(t *testing.T) {
+    now := time.Now()
+    q := &Question{Step: 4, Stability: 10, Difficulty: 5, LastReview: now, IsFailed: true}
+
+    newFsrsScheduler().Init(q, now)
+
+    assert.Equal(t, uint8(1), q.Step, "Step должен быть 1")
+    assert.Equal(t, float64(0), q.Stability, "Stability должна быть пустой")
+    assert.Equal(t, float64(0), q.Difficulty, "Difficulty должна быть пустой")
+    assert.Equal(t, false, q.IsFailed, "Флаг IsFailed должен быть false")
+    assert.Equal(t, now, q.RepeatTime, "Новая карточка должна быть доступна к повторению сразу")
+}
+
+func Test_fsrs_answer_first_review_set_initial_stability_and_difficulty(t *testing.T) {
+    now := time.Now()
+    expected := map[Grade]struct {
+        stability  float64
+        difficulty float64
+        interval   uint32
+    }{
+        GradeHard: {0.6, 5.87, 1},
+        GradeGood: {2.4, 4.93, 2},
+        GradeEasy: {5.8, 3.99, 6},
+    }
+
+    for grade, e := range expected {
+        q := &Question{}
+        s := newFsrsScheduler()
+        s.Init(q, now)
+        s.Answer(q, grade, now)
+
+        assert.InDelta(t, e.stability, q.Stability, 0.0001, "Stability после оценки %d неверная", grade)
+        assert.InDelta(t, e.difficulty, q.Difficulty, 0.0001, "Difficulty после оценки %d неверная", grade)
+        assert.Equal(t, e.interval, q.Interval, "Interval после оценки %d неверный", grade)
+        assert.Equal(t, now.Add(time.Hour*24*time.Duration(e.interval)), q.RepeatTime, "RepeatTime после оценки %d неверное", grade)
+        assert.Equal(t, now, q.LastReview, "LastReview должно быть текущим временем")
+        assert.Equal(t, uint8(2), q.Step, "Step должен увеличиться")
+    }
+}
+
+func Test_fsrs_answer_when_again_question_is_relearned_soon_and_marked_failed(t *testing.T) {
+    now := time.Now()
+    q := &Question{Step: 3, Stability: 10, Difficulty: 5, LastReview: now.Add(-time.Hour * 24 * 10)}
+
+    newFsrsScheduler().Answer(q, GradeAgain, now)
+
+    assert.Equal(t, uint8(1), q.Step, "Step должен быть 1")
+    assert.Equal(t, true, q.IsFailed, "Флаг IsFailed должен быть true")
+    assert.Less(t, q.Stability, float64(10), "Stability должна уменьшиться")
+    assert.Greater(t, q.Difficulty, float64(5), "Difficulty должна увеличиться")
+    assert.Equal(t, now.Add(fsrsRelearnDelay), q.RepeatTime, "Вопрос должен повториться через короткий интервал")
+}
+
+func Test_fsrs_answer_when_recalled_stability_grows_more_for_easier_grades(t *testing.T) {
+    now := time.Now()
+    stability := map[Grade]float64{}
+
+    for _, grade := range []Grade{GradeHard, GradeGood, GradeEasy} {
+        q := &Question{Step: 2, Stability: 2.4, Difficulty: 4.93, LastReview: now.Add(-time.Hour * 24 * 2)}
+        newFsrsScheduler().Answer(q, grade, now)
+        stability[grade] = q.Stability
+
+        assert.Greater(t, q.Stability, 2.4, "Stability должна вырасти после оценки %d", grade)
+        assert.Equal(t, false, q.IsFailed, "Флаг IsFailed должен быть false после оценки %d", grade)
+    }
+
+    assert.Less(t, stability[GradeHard], stability[GradeGood], "Stability после hard должна быть меньше, чем после good")
+    assert.Less(t, stability[GradeGood], stability[GradeEasy], "Stability после good должна быть меньше, чем после easy")
+}
+
+func Test_fsrs_retrievability_is_request_retention_after_stability_days(t *testing.T) {
+    s := newFsrsScheduler()
+
+    assert.InDelta(t, 1, s.retrievability(0, 5), 0.0001, "Сразу после повторения вероятность вспомнить должна быть 1")
+    assert.InDelta(t, fsrsRequestRetention, s.retrievability(5, 5), 0.0001, "Через stability дней вероятность вспомнить должна быть 0.9")
+}
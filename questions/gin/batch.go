@@ -0,0 +1,92 @@
+package gin
+
+import (
+    "net/http"
+    "time"
+
+    errors_formatter "github.com/chudoyoudo/errors-formatter"
+    "github.com/gin-gonic/gin/binding"
+    "github.com/pkg/errors"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+// В пакете от 1 до 100 операций
+type batchData struct {
+    Operations []batchOperation `json:"operations" binding:"required,min=1,max=100"`
+}
+
+// Операция пакета. Поля вопроса title, body, groupId и tags нужны для add и correct,
+// поля ответа grade или remembered и responseTime — для answer.
+// version, как If-Match у отдельных запросов, задает ожидаемую версию вопроса
+type batchOperation struct {
+    questionData `binding:"-"`
+    answerData   `binding:"-"`
+    Action       string `json:"action" binding:"required,oneof=add correct delete answer"`
+    Id           uint64 `json:"id" binding:"required_unless=Action add"`
+    Version      uint64 `json:"version"`
+}
+
+func (o *batchOperation) Validate() map[string][]string {
+    if err := binding.Validator.ValidateStruct(o); err != nil {
+        return errors_formatter.FormatErrors(err)
+    }
+
+    var err error
+    switch questions.BatchAction(o.Action) {
+    case questions.BatchAdd, questions.BatchCorrect:
+        err = binding.Validator.ValidateStruct(&o.questionData)
+    case questions.BatchAnswer:
+        err = binding.Validator.ValidateStruct(&o.answerData)
+    }
+    if err != nil {
+        return errors_formatter.FormatErrors(err)
+    }
+    return nil
+}
+
+func (o *batchOperation) ToOperation() questions.BatchOperation {
+    op := questions.BatchOperation{
+        Action:  questions.BatchAction(o.Action),
+        ID:      o.Id,
+        Version: o.Version,
+    }
+
+    switch op.Action {
+    case questions.BatchAdd, questions.BatchCorrect:
+        op.Question = &questions.Question{}
+        o.questionData.Bind(op.Question)
+    case questions.BatchAnswer:
+        op.Grade = o.ToGrade()
+        op.ResponseTime = time.Duration(o.ResponseTime) * time.Millisecond
+    }
+    return op
+}
+
+// Результат операции пакета со статусом, который вернул бы отдельный запрос
+type batchResult struct {
+    Status   int                 `json:"status"`
+    Question *questions.Question `json:"question,omitempty"`
+    Errors   map[string][]string `json:"errors,omitempty"`
+}
+
+func newBatchResult(r questions.BatchResult) batchResult {
+    switch {
+    case errors.Is(r.Err, questions.ErrGroupNotFound):
+        return batchResult{
+            Status: http.StatusBadRequest,
+            Errors: map[string][]string{"groupId": {questions.ErrGroupNotFound.Error()}},
+        }
+    case errors.Is(r.Err, questions.ErrQuestionNotFound):
+        return batchResult{
+            Status: http.StatusNotFound,
+            Errors: map[string][]string{"id": {questions.ErrQuestionNotFound.Error()}},
+        }
+    case errors.Is(r.Err, questions.ErrVersionConflict):
+        return batchResult{
+            Status: http.StatusPreconditionFailed,
+            Errors: map[string][]string{"version": {questions.ErrVersionConflict.Error()}},
+        }
+    }
+    return batchResult{Status: http.StatusOK, Question: r.Question}
+}
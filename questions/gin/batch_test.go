@@ -0,0 +1,65 @@
+package gin
+
+import (
+    "encoding/json"
+    "net/http"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+func Test_batch_operation_decode_flat_question_and_answer_fields(t *testing.T) {
+    d := &batchData{}
+    data := `{"operations": [
+        {"action": "add", "title": "Question", "body": "Answer", "groupId": 2, "tags": ["go"]},
+        {"action": "answer", "id": 3, "version": 4, "grade": "hard", "responseTime": 1500}
+    ]}`
+
+    require.Nil(t, json.Unmarshal([]byte(data), d))
+
+    require.Len(t, d.Operations, 2)
+    assert.Equal(t, questions.BatchOperation{
+        Action:   questions.BatchAdd,
+        Question: &questions.Question{Title: "Question", Body: "Answer", GroupId: 2, Tags: questions.NewTags([]string{"go"})},
+    }, d.Operations[0].ToOperation(), "Операция добавления неверная")
+    assert.Equal(t, questions.BatchOperation{
+        Action:       questions.BatchAnswer,
+        ID:           3,
+        Version:      4,
+        Grade:        questions.GradeHard,
+        ResponseTime: 1500 * time.Millisecond,
+    }, d.Operations[1].ToOperation(), "Операция ответа неверная")
+}
+
+func Test_batch_operation_validate_check_fields_of_action(t *testing.T) {
+    add := &batchOperation{Action: "add", answerData: answerData{Grade: "wrong"}}
+    answer := &batchOperation{Action: "answer", Id: 1, questionData: questionData{GroupId: 2}}
+    unknown := &batchOperation{Action: "move", Id: 1}
+
+    assert.Contains(t, add.Validate(), "title", "Для add должны проверяться поля вопроса")
+    assert.NotContains(t, add.Validate(), "grade", "Для add не должны проверяться поля ответа")
+    assert.Contains(t, answer.Validate(), "grade", "Для answer должны проверяться поля ответа")
+    assert.NotContains(t, answer.Validate(), "title", "Для answer не должны проверяться поля вопроса")
+    assert.Contains(t, unknown.Validate(), "action", "Неизвестная операция должна быть отклонена")
+}
+
+func Test_batch_operation_validate_when_id_is_empty_return_error_for_all_actions_except_add(t *testing.T) {
+    valid := questionData{Title: "Question", Body: "Answer", GroupId: 2}
+
+    assert.Nil(t, (&batchOperation{Action: "add", questionData: valid}).Validate(), "Для add id не нужен")
+    assert.Contains(t, (&batchOperation{Action: "correct", questionData: valid}).Validate(), "id", "Для correct нужен id")
+    assert.Contains(t, (&batchOperation{Action: "delete"}).Validate(), "id", "Для delete нужен id")
+}
+
+func Test_new_batch_result_return_status_of_item_error(t *testing.T) {
+    q := &questions.Question{ID: 1}
+
+    assert.Equal(t, batchResult{Status: http.StatusOK, Question: q}, newBatchResult(questions.BatchResult{Question: q}))
+    assert.Equal(t, http.StatusBadRequest, newBatchResult(questions.BatchResult{Err: questions.ErrGroupNotFound}).Status)
+    assert.Equal(t, http.StatusNotFound, newBatchResult(questions.BatchResult{Err: questions.ErrQuestionNotFound}).Status)
+    assert.Equal(t, http.StatusPreconditionFailed, newBatchResult(questions.BatchResult{Err: questions.ErrVersionConflict}).Status)
+}
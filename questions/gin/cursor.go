@@ -0,0 +1,60 @@
+package gin
+
+import (
+    "encoding/base64"
+    "encoding/json"
+
+    "github.com/pkg/errors"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+var (
+    errCursorNotValid     = errors.New("cursor is not valid")
+    errCursorNotSupported = errors.New("cursor is supported only for sort by id")
+)
+
+// Позиция в списке вопросов. Клиент получает ее закодированной в nextCursor
+// и передает обратно без изменений, поэтому состав полей можно расширять
+type listCursor struct {
+    ID uint64 `json:"id"`
+}
+
+// Метод ограничивает запрос вопросами, которые идут после курсора при сортировке
+// по id, при desc — от новых к старым. Пустой курсор означает первую страницу
+func (cur *listCursor) Apply(query *questions.Query, desc bool) *questions.Query {
+    if cur.ID == 0 {
+        return query
+    }
+    if desc {
+        return query.Lt(questions.FieldId, cur.ID)
+    }
+    return query.Gt(questions.FieldId, cur.ID)
+}
+
+func encodeCursor(cur *listCursor) string {
+    data, _ := json.Marshal(cur)
+    return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (*listCursor, error) {
+    data, err := base64.RawURLEncoding.DecodeString(value)
+    if err != nil {
+        return nil, errCursorNotValid
+    }
+
+    cur := &listCursor{}
+    if err := json.Unmarshal(data, cur); err != nil || cur.ID == 0 {
+        return nil, errCursorNotValid
+    }
+    return cur, nil
+}
+
+// Курсор следующей страницы строится по последнему вопросу текущей
+func nextCursor(ql *[]questions.Question, more bool) string {
+    if !more || len(*ql) == 0 {
+        return ""
+    }
+    last := (*ql)[len(*ql)-1]
+    return encodeCursor(&listCursor{ID: last.ID})
+}
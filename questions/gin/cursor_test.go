@@ -0,0 +1,47 @@
+package gin
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+func Test_decode_cursor_return_encoded_cursor(t *testing.T) {
+    cur, err := decodeCursor(encodeCursor(&listCursor{ID: 42}))
+
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, &listCursor{ID: 42}, cur, "Курсор должен декодироваться в исходную позицию")
+}
+
+func Test_decode_cursor_when_value_is_wrong_result_error_is_cursor_not_valid(t *testing.T) {
+    _, errNotBase64 := decodeCursor("!!!")
+    _, errNotJson := decodeCursor("bm90IGpzb24")
+    _, errEmpty := decodeCursor(encodeCursor(&listCursor{}))
+
+    assert.ErrorIs(t, errNotBase64, errCursorNotValid, "Для значения не в base64 должна вернуться errCursorNotValid")
+    assert.ErrorIs(t, errNotJson, errCursorNotValid, "Для значения не в json должна вернуться errCursorNotValid")
+    assert.ErrorIs(t, errEmpty, errCursorNotValid, "Для курсора без id должна вернуться errCursorNotValid")
+}
+
+func Test_next_cursor_point_to_last_question_when_more(t *testing.T) {
+    ql := &[]questions.Question{{ID: 5}, {ID: 3}}
+
+    next := nextCursor(ql, true)
+    last := nextCursor(ql, false)
+
+    assert.Equal(t, encodeCursor(&listCursor{ID: 3}), next, "Курсор должен указывать на последний вопрос страницы")
+    assert.Equal(t, "", last, "На последней странице курсор должен быть пустым")
+}
+
+func Test_cursor_apply_limit_query_by_questions_after_cursor(t *testing.T) {
+    desc := (&listCursor{ID: 7}).Apply(questions.NewQuery(), true)
+    asc := (&listCursor{ID: 7}).Apply(questions.NewQuery(), false)
+    first := (&listCursor{}).Apply(questions.NewQuery(), true)
+
+    assert.Equal(t, questions.NewQuery().Lt(questions.FieldId, uint64(7)), desc, "При сортировке от новых запрос должен выбирать вопросы старше курсора")
+    assert.Equal(t, questions.NewQuery().Gt(questions.FieldId, uint64(7)), asc, "При сортировке от старых запрос должен выбирать вопросы новее курсора")
+    assert.Empty(t, first.Where, "Для первой страницы условий быть не должно")
+}
@@ -0,0 +1,46 @@
+package gin
+
+import (
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+// ETag вопроса — его версия в кавычках, например "3"
+func formatETag(version uint64) string {
+    return strconv.Quote(strconv.FormatUint(version, 10))
+}
+
+func setETag(c *gin.Context, q *questions.Question) {
+    c.Header("ETag", formatETag(q.Version))
+}
+
+// Метод проверяет заголовок If-Match по версии вопроса. Без заголовка запрос
+// выполняется всегда, * совпадает с любой версией, слабые ETag не совпадают никогда
+func ifMatch(header string, version uint64) bool {
+    if strings.TrimSpace(header) == "" {
+        return true
+    }
+
+    etag := formatETag(version)
+    for _, tag := range strings.Split(header, ",") {
+        tag = strings.TrimSpace(tag)
+        if tag == "*" || tag == etag {
+            return true
+        }
+    }
+    return false
+}
+
+// Метод прерывает запрос со статусом 412, если If-Match не совпал с версией вопроса
+func checkIfMatch(c *gin.Context, q *questions.Question) bool {
+    if !ifMatch(c.GetHeader("If-Match"), q.Version) {
+        c.AbortWithStatus(http.StatusPreconditionFailed)
+        return false
+    }
+    return true
+}
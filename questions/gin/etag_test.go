@@ -0,0 +1,27 @@
+package gin
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_format_etag_return_quoted_version(t *testing.T) {
+    assert.Equal(t, `"3"`, formatETag(3), "ETag должен быть версией в кавычках")
+}
+
+func Test_if_match_without_header_always_match(t *testing.T) {
+    assert.True(t, ifMatch("", 3), "Запрос без If-Match должен выполняться")
+}
+
+func Test_if_match_when_header_contains_version_match(t *testing.T) {
+    assert.True(t, ifMatch(`"3"`, 3), "Совпадающий ETag должен проходить проверку")
+    assert.True(t, ifMatch(`"1", "3"`, 3), "ETag из списка должен проходить проверку")
+    assert.True(t, ifMatch("*", 3), "* должна совпадать с любой версией")
+}
+
+func Test_if_match_when_header_has_other_version_not_match(t *testing.T) {
+    assert.False(t, ifMatch(`"2"`, 3), "Другая версия не должна проходить проверку")
+    assert.False(t, ifMatch(`W/"3"`, 3), "Слабый ETag не должен проходить проверку")
+    assert.False(t, ifMatch("3", 3), "ETag без кавычек не должен проходить проверку")
+}
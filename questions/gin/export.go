@@ -0,0 +1,284 @@
+package gin
+
+import (
+    "context"
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/pkg/errors"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+    "github.com/chudoyoudo/remember-cards/questions/apkg"
+)
+
+// Количество вопросов, которое читается из хранилища за один запрос при экспорте
+const exportBatch = 500
+
+// По умолчанию экспорт выполняется в json
+type exportData struct {
+    Format      string   `form:"format" binding:"omitempty,oneof=json csv apkg"`
+    GroupId     []uint64 `form:"groupId"`
+    Descendants bool     `form:"descendants"`
+}
+
+func (d *exportData) GetFormat() string {
+    if d.Format == "" {
+        return "json"
+    }
+    return d.Format
+}
+
+var exportContentTypes = map[string]string{
+    "json": gin.MIMEJSON,
+    "csv":  "text/csv; charset=utf-8",
+    "apkg": "application/octet-stream",
+}
+
+// Вопрос вместе с состоянием расписания, которое не отдается в обычных ответах api
+type exportRecord struct {
+    ID         uint64    `json:"id"`
+    GroupId    uint64    `json:"groupId"`
+    Title      string    `json:"title"`
+    Body       string    `json:"body"`
+    Step       uint8     `json:"step"`
+    RepeatTime time.Time `json:"repeatTime"`
+    IsFailed   bool      `json:"isFailed"`
+    Ease       float64   `json:"ease"`
+    Interval   uint32    `json:"interval"`
+    Stability  float64   `json:"stability"`
+    Difficulty float64   `json:"difficulty"`
+    LastReview time.Time `json:"lastReview"`
+    Tags       []string  `json:"tags"`
+}
+
+func newExportRecord(q *questions.Question) *exportRecord {
+    return &exportRecord{
+        ID:         q.ID,
+        GroupId:    q.GroupId,
+        Title:      q.Title,
+        Body:       q.Body,
+        Step:       q.Step,
+        RepeatTime: q.RepeatTime,
+        IsFailed:   q.IsFailed,
+        Ease:       q.Ease,
+        Interval:   q.Interval,
+        Stability:  q.Stability,
+        Difficulty: q.Difficulty,
+        LastReview: q.LastReview,
+        Tags:       questions.TagNames(q.Tags),
+    }
+}
+
+type exportWriter interface {
+    Write(ql *[]questions.Question) error
+    // Метод дописывает окончание выгрузки после последней страницы
+    Finish() error
+    // Метод освобождает ресурсы writer, в том числе после ошибки
+    Close() error
+}
+
+// Имена колод для групп при экспорте в apkg
+type groupNamesFunc func(groupIds []uint64) (map[uint64]string, error)
+
+func newExportWriter(format string, out io.Writer, names groupNamesFunc) (exportWriter, error) {
+    switch format {
+    case "csv":
+        return &csvExportWriter{out: out, w: csv.NewWriter(out)}, nil
+    case "apkg":
+        w, err := apkg.NewWriter(time.Now())
+        if err != nil {
+            return nil, errors.Wrap(err, "Can't create apkg writer")
+        }
+        return &apkgExportWriter{out: out, w: w, names: names, decks: map[uint64]string{}}, nil
+    default:
+        return &jsonExportWriter{out: out}, nil
+    }
+}
+
+// Список вопросов json-массивом, который пишется по мере чтения из хранилища
+type jsonExportWriter struct {
+    out   io.Writer
+    count int
+}
+
+func (w *jsonExportWriter) Write(ql *[]questions.Question) error {
+    for i := range *ql {
+        prefix := ","
+        if w.count == 0 {
+            prefix = "["
+        }
+
+        b, err := json.Marshal(newExportRecord(&(*ql)[i]))
+        if err != nil {
+            return errors.Wrapf(err, "Can't marshal question %d", (*ql)[i].ID)
+        }
+        if _, err := io.WriteString(w.out, prefix+string(b)); err != nil {
+            return errors.Wrap(err, "Can't write question")
+        }
+        w.count++
+    }
+    flush(w.out)
+    return nil
+}
+
+func (w *jsonExportWriter) Finish() error {
+    end := "]"
+    if w.count == 0 {
+        end = "[]"
+    }
+    _, err := io.WriteString(w.out, end)
+    return errors.Wrap(err, "Can't write end of list")
+}
+
+func (w *jsonExportWriter) Close() error {
+    return nil
+}
+
+// Первые колонки совпадают с форматом импорта, поэтому выгрузку можно загрузить обратно
+var exportCsvHeader = []string{
+    "title", "body", "groupId", "tags",
+    "id", "step", "repeatTime", "isFailed", "ease", "interval", "stability", "difficulty", "lastReview",
+}
+
+type csvExportWriter struct {
+    out    io.Writer
+    w      *csv.Writer
+    header bool
+}
+
+func (w *csvExportWriter) Write(ql *[]questions.Question) error {
+    if !w.header {
+        if err := w.w.Write(exportCsvHeader); err != nil {
+            return errors.Wrap(err, "Can't write csv header")
+        }
+        w.header = true
+    }
+
+    for _, q := range *ql {
+        record := []string{
+            q.Title,
+            q.Body,
+            strconv.FormatUint(q.GroupId, 10),
+            strings.Join(questions.TagNames(q.Tags), " "),
+            strconv.FormatUint(q.ID, 10),
+            strconv.FormatUint(uint64(q.Step), 10),
+            formatTime(q.RepeatTime),
+            strconv.FormatBool(q.IsFailed),
+            strconv.FormatFloat(q.Ease, 'f', -1, 64),
+            strconv.FormatUint(uint64(q.Interval), 10),
+            strconv.FormatFloat(q.Stability, 'f', -1, 64),
+            strconv.FormatFloat(q.Difficulty, 'f', -1, 64),
+            formatTime(q.LastReview),
+        }
+        if err := w.w.Write(record); err != nil {
+            return errors.Wrapf(err, "Can't write question %d", q.ID)
+        }
+    }
+
+    w.w.Flush()
+    flush(w.out)
+    return errors.Wrap(w.w.Error(), "Can't flush csv")
+}
+
+func (w *csvExportWriter) Finish() error {
+    if w.header {
+        return nil
+    }
+    return w.Write(&[]questions.Question{})
+}
+
+func (w *csvExportWriter) Close() error {
+    return nil
+}
+
+// Колода собирается во временной SQLite-коллекции и пишется в ответ целиком при закрытии
+type apkgExportWriter struct {
+    out   io.Writer
+    w     apkg.Writer
+    names groupNamesFunc
+    decks map[uint64]string
+}
+
+func (w *apkgExportWriter) Write(ql *[]questions.Question) error {
+    unknown := []uint64{}
+    for _, q := range *ql {
+        if _, found := w.decks[q.GroupId]; !found {
+            w.decks[q.GroupId] = ""
+            unknown = append(unknown, q.GroupId)
+        }
+    }
+
+    if len(unknown) > 0 {
+        names, err := w.names(unknown)
+        if err != nil {
+            return errors.Wrapf(err, "Can't get names of groups %v", unknown)
+        }
+        for _, id := range unknown {
+            w.decks[id] = names[id]
+            if w.decks[id] == "" {
+                w.decks[id] = fmt.Sprintf("Group %d", id)
+            }
+        }
+    }
+
+    for i := range *ql {
+        q := &(*ql)[i]
+        if err := w.w.Add(q, w.decks[q.GroupId]); err != nil {
+            return errors.Wrapf(err, "Can't add question %d to apkg", q.ID)
+        }
+    }
+    return nil
+}
+
+func (w *apkgExportWriter) Finish() error {
+    return errors.Wrap(w.w.Save(w.out), "Can't save apkg")
+}
+
+func (w *apkgExportWriter) Close() error {
+    return w.w.Close()
+}
+
+// Метод читает вопросы постранично по возрастанию id и передает каждую страницу в writer.
+// Следующая страница начинается после последнего прочитанного id, поэтому вопросы,
+// удаленные или добавленные во время выгрузки, не сдвигают страницы.
+// Закрывать writer должен вызывающий код
+func exportQuestions(ctx context.Context, uc questions.Usecase, query *questions.Query, w exportWriter) error {
+    var lastId uint64
+    for {
+        page := query.Clone().Gt(questions.FieldId, lastId).OrderBy(questions.FieldId, false).Paginate(exportBatch, 0)
+        ql, more, err := uc.Find(ctx, page)
+        if err != nil {
+            return errors.Wrapf(err, "Can't find questions by query %v via usecase", *page)
+        }
+
+        if err := w.Write(ql); err != nil {
+            return errors.Wrap(err, "Can't write questions")
+        }
+
+        if !more || len(*ql) == 0 {
+            break
+        }
+        lastId = (*ql)[len(*ql)-1].ID
+    }
+    return errors.Wrap(w.Finish(), "Can't finish export")
+}
+
+func formatTime(t time.Time) string {
+    if t.IsZero() {
+        return ""
+    }
+    return t.UTC().Format(time.RFC3339)
+}
+
+func flush(w io.Writer) {
+    if f, ok := w.(http.Flusher); ok {
+        f.Flush()
+    }
+}
@@ -0,0 +1,148 @@
+package gin
+
+import (
+    "context"
+    "bytes"
+    "encoding/json"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+type exportWriterMock struct {
+    mock.Mock
+}
+
+func (m *exportWriterMock) Write(ql *[]questions.Question) error {
+    args := m.Called(ql)
+    return args.Error(0)
+}
+
+func (m *exportWriterMock) Finish() error {
+    args := m.Called()
+    return args.Error(0)
+}
+
+func (m *exportWriterMock) Close() error {
+    args := m.Called()
+    return args.Error(0)
+}
+
+func Test_export_questions_read_all_pages_and_finish_writer(t *testing.T) {
+    query := questions.NewQuery().Eq(questions.FieldUserId, uint64(1))
+    pageQuery := func(lastId uint64) *questions.Query {
+        return query.Clone().Gt(questions.FieldId, lastId).OrderBy(questions.FieldId, false).Paginate(exportBatch, 0)
+    }
+    firstPage := &[]questions.Question{{ID: 1}, {ID: 5}}
+    secondPage := &[]questions.Question{{ID: 7}}
+
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, pageQuery(0)).Return(firstPage, true, nil).Once()
+    uc.On("Find", mock.Anything, pageQuery(5)).Return(secondPage, false, nil).Once()
+    w := &exportWriterMock{}
+    w.On("Write", mock.Anything).Return(nil)
+    w.On("Finish").Return(nil)
+
+    errResult := exportQuestions(context.Background(), uc, query, w)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    w.AssertCalled(t, "Write", firstPage)
+    w.AssertCalled(t, "Write", secondPage)
+    w.AssertNumberOfCalls(t, "Finish", 1)
+}
+
+func Test_export_questions_usecase_work_wrong_result_error_not_empty_and_writer_not_finished(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, mock.Anything).Return(&[]questions.Question{}, false, usecaseErr)
+    w := &exportWriterMock{}
+
+    errResult := exportQuestions(context.Background(), uc, questions.NewQuery(), w)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+    w.AssertNotCalled(t, "Finish")
+}
+
+func Test_json_export_writer_write_questions_with_schedule(t *testing.T) {
+    rt := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+    out := &bytes.Buffer{}
+    w, _ := newExportWriter("json", out, nil)
+
+    require.Nil(t, w.Write(&[]questions.Question{{ID: 1, Step: 2, RepeatTime: rt, IsFailed: true, Tags: []questions.Tag{{Name: "weak"}}}}))
+    require.Nil(t, w.Write(&[]questions.Question{{ID: 2}}))
+    require.Nil(t, w.Finish())
+
+    records := []exportRecord{}
+    require.Nil(t, json.Unmarshal(out.Bytes(), &records), "Результат должен быть json-массивом")
+    require.Len(t, records, 2, "Результат должен содержать вопросы всех страниц")
+    assert.Equal(t, exportRecord{ID: 1, Step: 2, RepeatTime: rt, IsFailed: true, Tags: []string{"weak"}}, records[0], "Запись должна содержать состояние расписания и метки")
+}
+
+func Test_json_export_writer_without_questions_write_empty_list(t *testing.T) {
+    out := &bytes.Buffer{}
+    w, _ := newExportWriter("json", out, nil)
+
+    require.Nil(t, w.Finish())
+
+    assert.Equal(t, "[]", out.String(), "Пустая выгрузка должна быть пустым массивом")
+}
+
+func Test_csv_export_writer_result_can_be_imported_back(t *testing.T) {
+    rt := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+    out := &bytes.Buffer{}
+    w, _ := newExportWriter("csv", out, nil)
+
+    require.Nil(t, w.Write(&[]questions.Question{{ID: 1, GroupId: 2, Title: "Front, 1", Body: "Back", Step: 3, RepeatTime: rt, Tags: []questions.Tag{{Name: "exam-2026"}, {Name: "weak"}}}}))
+    require.Nil(t, w.Finish())
+
+    lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+    require.Len(t, lines, 2, "Выгрузка должна содержать заголовок и строку вопроса")
+    assert.Equal(t, strings.Join(exportCsvHeader, ","), lines[0], "Заголовок выгрузки неверный")
+    assert.Equal(t, `"Front, 1",Back,2,exam-2026 weak,1,3,2021-03-01T12:00:00Z,false,0,0,0,0,`, lines[1], "Строка вопроса неверная")
+
+    rows, err := parseImport(strings.NewReader(out.String()), ',', 0)
+    require.Nil(t, err, "Выгрузка должна читаться импортом")
+    assert.Equal(t, []importRow{{Row: 2, Title: "Front, 1", Body: "Back", GroupId: 2, Tags: []string{"exam-2026", "weak"}}}, rows, "Импорт выгрузки должен вернуть исходные вопросы")
+}
+
+func Test_apkg_export_writer_request_names_once_for_each_group(t *testing.T) {
+    calls := [][]uint64{}
+    names := func(groupIds []uint64) (map[uint64]string, error) {
+        calls = append(calls, groupIds)
+        return map[uint64]string{1: "Subject"}, nil
+    }
+    out := &bytes.Buffer{}
+    w, err := newExportWriter("apkg", out, names)
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    defer w.Close()
+
+    require.Nil(t, w.Write(&[]questions.Question{{ID: 1, GroupId: 1}, {ID: 2, GroupId: 2}}))
+    require.Nil(t, w.Write(&[]questions.Question{{ID: 3, GroupId: 1}}))
+    require.Nil(t, w.Finish())
+
+    assert.Equal(t, [][]uint64{{1, 2}}, calls, "Имена групп должны запрашиваться один раз")
+    assert.Equal(t, map[uint64]string{1: "Subject", 2: "Group 2"}, w.(*apkgExportWriter).decks, "Группа без имени должна получить имя по id")
+    assert.NotZero(t, out.Len(), "Архив колоды должен быть записан")
+}
+
+func Test_apkg_export_writer_names_work_wrong_result_error_not_empty(t *testing.T) {
+    namesErr := errors.New("Names error")
+    names := func(groupIds []uint64) (map[uint64]string, error) {
+        return nil, namesErr
+    }
+    w, _ := newExportWriter("apkg", &bytes.Buffer{}, names)
+    defer w.Close()
+
+    errResult := w.Write(&[]questions.Question{{ID: 1, GroupId: 1}})
+
+    require.ErrorIs(t, errResult, namesErr, "Возвращаемая ошибка должна содержать информацию из names")
+}
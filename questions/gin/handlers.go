@@ -1,14 +1,19 @@
 package gin
 
 import (
+    "context"
+    "io/ioutil"
     "net/http"
     "strconv"
+    "strings"
+    "time"
 
     rest_api_response_formatter "github.com/chudoyoudo/rest-api-response-formatter"
     "github.com/gin-gonic/gin"
     "github.com/golobby/container"
     "github.com/pkg/errors"
 
+    auth_gin "github.com/chudoyoudo/remember-cards/auth/gin"
     "github.com/chudoyoudo/remember-cards/questions"
 
     errors_formatter "github.com/chudoyoudo/errors-formatter"
@@ -19,15 +24,25 @@ func RegisterHandlers(r *gin.Engine, middleware ...gin.HandlerFunc) {
     v1 := r.Group("/v1").Use(middleware...)
     v1.POST("/question", addHandler)
     v1.GET("/question", listHandler)
+    v1.GET("/question/due", dueHandler)
+    v1.POST("/question/import", importHandler)
+    v1.POST("/question/batch", batchHandler)
+    v1.GET("/question/export", exportHandler)
+    v1.GET("/question/trash", trashHandler)
     v1.PUT("/question/:id", correctHandler)
+    v1.PATCH("/question/:id", patchHandler)
     v1.GET("/question/:id", viewHandler)
     v1.DELETE("/question/:id", deleteHandler)
+    v1.POST("/question/:id/answer", answerHandler)
+    v1.POST("/question/:id/restore", restoreHandler)
 }
 
+// Метки tags при изменении вопроса заменяют его метки, а если не переданы, остаются прежними
 type questionData struct {
-    Title   string `json:"title" binding:"required"`
-    Body    string `json:"body" binding:"required"`
-    GroupId uint64 `json:"groupId" binding:"required"`
+    Title   string    `json:"title" binding:"required"`
+    Body    string    `json:"body" binding:"required"`
+    GroupId uint64    `json:"groupId" binding:"required"`
+    Tags    *[]string `json:"tags" binding:"omitempty,max=50,dive,max=64"`
 }
 
 func (d *questionData) Bind(q *questions.Question) {
@@ -40,29 +55,153 @@ func (d *questionData) Bind(q *questions.Question) {
     if d.GroupId != 0 {
         q.GroupId = d.GroupId
     }
+    if d.Tags != nil {
+        q.Tags = questions.NewTags(*d.Tags)
+    }
+}
+
+// Оценка передается в grade (again, hard, good, easy)
+// или упрощенно флагом remembered, который соответствует good и again.
+// Время ответа responseTime передается в миллисекундах
+type answerData struct {
+    Grade        string `json:"grade" binding:"required_without=Remembered,omitempty,oneof=again hard good easy"`
+    Remembered   *bool  `json:"remembered" binding:"required_without=Grade"`
+    ResponseTime int64  `json:"responseTime" binding:"min=0"`
+}
+
+func (d *answerData) ToGrade() questions.Grade {
+    if d.Grade != "" {
+        g, err := questions.ParseGrade(d.Grade)
+        if err == nil {
+            return g
+        }
+    }
+    if d.Remembered != nil && *d.Remembered {
+        return questions.GradeGood
+    }
+    return questions.GradeAgain
 }
 
+// Вопросы с любой из меток tags или, при tagMode=all, со всеми метками сразу
+type tagFilter struct {
+    Tags    []string `form:"tags" binding:"max=50"`
+    TagMode string   `form:"tagMode" binding:"omitempty,oneof=any all"`
+}
+
+func (f *tagFilter) Apply(query *questions.Query) {
+    names := questions.TagNames(questions.NewTags(f.Tags))
+    if len(names) > 0 {
+        query.Tagged(names, f.TagMode == "all")
+    }
+}
+
+// Если задан q, возвращаются вопросы, в вопросе или ответе которых есть все слова запроса,
+// начиная с самых подходящих. Иначе список идет в порядке sort, по умолчанию от новых вопросов
+// к старым. При сортировке по id следующая страница запрашивается по nextCursor из ответа,
+// при остальных сортировках — по offset.
+// repeatBefore и repeatAfter ограничивают время повторения, например чтобы выбрать
+// проваленные карточки на эту неделю: isFailed=true&repeatBefore=...
+// С descendants=true выборка по groupId включает все вложенные группы
 type filter struct {
-    GroupId []uint64 `form:"groupId"`
-    UserId  []uint64 `form:"userId"`
-    Limit   int      `form:"limit"`
-    Offset  int      `form:"offset"`
+    tagFilter
+    GroupId      []uint64   `form:"groupId"`
+    Descendants  bool       `form:"descendants"`
+    Q            string     `form:"q" binding:"max=255"`
+    Sort         string     `form:"sort" binding:"omitempty,oneof=id -id repeatTime -repeatTime title -title"`
+    RepeatBefore *time.Time `form:"repeatBefore"`
+    RepeatAfter  *time.Time `form:"repeatAfter"`
+    IsFailed     *bool      `form:"isFailed"`
+    Step         *uint8     `form:"step"`
+    Cursor       string     `form:"cursor" binding:"omitempty,max=255,excluded_with=Q Offset"`
+    Limit        int        `form:"limit"`
+    Offset       int        `form:"offset"`
+}
+
+// Поля сортировки списка. Минус перед полем задает обратный порядок
+var listSorts = map[string]questions.Field{
+    "id":         questions.FieldId,
+    "repeatTime": questions.FieldRepeatTime,
+    "title":      questions.FieldTitle,
 }
 
-func (f *filter) ToConds() *map[string]interface{} {
-    result := map[string]interface{}{}
+func (f *filter) ToQuery(userId uint64) *questions.Query {
+    query := groupQuery(userId, f.GroupId).Paginate(f.Limit, f.Offset)
+
+    if f.RepeatBefore != nil {
+        query.Lt(questions.FieldRepeatTime, *f.RepeatBefore)
+    }
 
-    if len(f.GroupId) > 0 {
-        result[questions.QuestionGroupId] = f.GroupId
+    if f.RepeatAfter != nil {
+        query.Gt(questions.FieldRepeatTime, *f.RepeatAfter)
     }
-    if len(f.UserId) > 0 {
-        result[questions.QuestionUserId] = f.UserId
+
+    if f.IsFailed != nil {
+        query.Eq(questions.FieldIsFailed, *f.IsFailed)
     }
 
-    return &result
+    if f.Step != nil {
+        query.Eq(questions.FieldStep, *f.Step)
+    }
+
+    f.tagFilter.Apply(query)
+
+    // Вопросы с одинаковым значением поля идут по id, чтобы страницы не пересекались
+    field, desc := f.GetSort()
+    query.OrderBy(field, desc)
+    if field != questions.FieldId {
+        query.OrderBy(questions.FieldId, desc)
+    }
+
+    return query
+}
+
+func (f *filter) GetSort() (field questions.Field, desc bool) {
+    if f.Sort == "" {
+        return questions.FieldId, true
+    }
+    name := strings.TrimPrefix(f.Sort, "-")
+    return listSorts[name], name != f.Sort
+}
+
+type dueFilter struct {
+    tagFilter
+    GroupId     []uint64 `form:"groupId"`
+    Descendants bool     `form:"descendants"`
+    Limit       int      `form:"limit"`
+    Offset      int      `form:"offset"`
+}
+
+func (f *dueFilter) ToQuery(userId uint64) *questions.Query {
+    query := groupQuery(userId, f.GroupId).Paginate(f.Limit, f.Offset)
+    f.tagFilter.Apply(query)
+    return query
+}
+
+// Корзина идет от последних удаленных вопросов
+type trashFilter struct {
+    Limit  int `form:"limit"`
+    Offset int `form:"offset"`
+}
+
+func (f *trashFilter) ToQuery(userId uint64) *questions.Query {
+    return questions.NewQuery().Eq(questions.FieldUserId, userId).Paginate(f.Limit, f.Offset)
+}
+
+// Запрос вопросов пользователя. Без групп выборка идет по всем его группам
+func groupQuery(userId uint64, groupIds []uint64) *questions.Query {
+    query := questions.NewQuery().Eq(questions.FieldUserId, userId)
+    if len(groupIds) > 0 {
+        query.In(questions.FieldGroupId, groupIds)
+    }
+    return query
 }
 
 func listHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
     f := &filter{}
     if err := c.ShouldBindQuery(f); err != nil {
         errData := errors_formatter.FormatErrors(err)
@@ -71,16 +210,100 @@ func listHandler(c *gin.Context) {
         return
     }
 
-    conds := f.ToConds()
-    order := &[]interface{}{"id desc"}
+    sortField, sortDesc := f.GetSort()
+    byId := sortField == questions.FieldId
+    cur := &listCursor{}
+    if f.Cursor != "" {
+        var err error
+        cur, err = decodeCursor(f.Cursor)
+        if err == nil && !byId {
+            err = errCursorNotSupported
+        }
+        if err != nil {
+            response := rest_api_response_formatter.GetResponseData(&struct{}{}, &map[string][]string{
+                "cursor": {err.Error()},
+            })
+            c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+            return
+        }
+    }
+
     uc := getUsecase()
-    ql, more, err := getQuestionList(uc, conds, order, f.Limit, f.Offset)
+    if f.Descendants {
+        groupIds, err := getDescendantGroups(c.Request.Context(), uc, f.GroupId, userId)
+        if err != nil {
+            log.Error(errors.Wrap(err, "Can't get descendant groups"))
+            c.AbortWithStatus(http.StatusInternalServerError)
+            return
+        }
+        f.GroupId = groupIds
+    }
+
+    query := f.ToQuery(userId)
+
+    var ql *[]questions.Question
+    var more bool
+    var err error
+    next := ""
+    if text := strings.TrimSpace(f.Q); text != "" {
+        ql, more, err = getSearchList(c.Request.Context(), uc, text, query)
+    } else if byId {
+        if f.Offset == 0 {
+            cur.Apply(query, sortDesc)
+        }
+        ql, more, err = getQuestionList(c.Request.Context(), uc, query)
+        next = nextCursor(ql, more)
+    } else {
+        ql, more, err = getQuestionList(c.Request.Context(), uc, query)
+    }
     if err != nil {
         log.Error(errors.Wrap(err, "Can't get question list"))
         c.AbortWithStatus(http.StatusInternalServerError)
         return
     }
 
+    response := rest_api_response_formatter.GetResponseData(gin.H{
+        "list":       *ql,
+        "more":       more,
+        "nextCursor": next,
+    }, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+func dueHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    f := &dueFilter{}
+    if err := c.ShouldBindQuery(f); err != nil {
+        errData := errors_formatter.FormatErrors(err)
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    uc := getUsecase()
+    if f.Descendants {
+        groupIds, err := getDescendantGroups(c.Request.Context(), uc, f.GroupId, userId)
+        if err != nil {
+            log.Error(errors.Wrap(err, "Can't get descendant groups"))
+            c.AbortWithStatus(http.StatusInternalServerError)
+            return
+        }
+        f.GroupId = groupIds
+    }
+
+    query := f.ToQuery(userId)
+
+    ql, more, err := getDueList(c.Request.Context(), uc, query)
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't get due question list"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
     response := rest_api_response_formatter.GetResponseData(gin.H{
         "list": *ql,
         "more": more,
@@ -88,11 +311,97 @@ func listHandler(c *gin.Context) {
     c.Negotiate(http.StatusOK, *getNegotiate(response))
 }
 
+func trashHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    f := &trashFilter{}
+    if err := c.ShouldBindQuery(f); err != nil {
+        errData := errors_formatter.FormatErrors(err)
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    ql, more, err := getTrashList(c.Request.Context(), getUsecase(), f.ToQuery(userId))
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't get trash question list"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    response := rest_api_response_formatter.GetResponseData(gin.H{
+        "list": *ql,
+        "more": more,
+    }, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+// Вопросы пишутся в ответ по мере чтения из хранилища, поэтому ошибка
+// после начала выгрузки только прерывает ответ
+func exportHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    d := &exportData{}
+    if err := c.ShouldBindQuery(d); err != nil {
+        errData := errors_formatter.FormatErrors(err)
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    uc := getUsecase()
+    if d.Descendants {
+        groupIds, err := getDescendantGroups(c.Request.Context(), uc, d.GroupId, userId)
+        if err != nil {
+            log.Error(errors.Wrap(err, "Can't get descendant groups"))
+            c.AbortWithStatus(http.StatusInternalServerError)
+            return
+        }
+        d.GroupId = groupIds
+    }
+
+    names := func(groupIds []uint64) (map[uint64]string, error) {
+        return uc.GroupNames(c.Request.Context(), groupIds, userId)
+    }
+    format := d.GetFormat()
+    w, err := newExportWriter(format, c.Writer, names)
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't create export writer"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+    defer w.Close()
+
+    c.Header("Content-Type", exportContentTypes[format])
+    c.Header("Content-Disposition", `attachment; filename="questions.`+format+`"`)
+
+    if err := exportQuestions(c.Request.Context(), uc, groupQuery(userId, d.GroupId), w); err != nil {
+        log.Error(errors.Wrap(err, "Can't export questions"))
+        if !c.Writer.Written() {
+            c.Writer.Header().Del("Content-Disposition")
+            c.AbortWithStatus(http.StatusInternalServerError)
+            return
+        }
+        c.Abort()
+    }
+}
+
 func viewHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
     id := getIdFomRequest(c)
     uc := getUsecase()
 
-    q, err := getQuestion(uc, id)
+    q, err := getQuestion(c.Request.Context(), uc, id, userId)
     if err != nil {
         log.Error(errors.Wrap(err, "Can't get question"))
         c.AbortWithStatus(http.StatusInternalServerError)
@@ -104,11 +413,17 @@ func viewHandler(c *gin.Context) {
         return
     }
 
+    setETag(c, q)
     response := rest_api_response_formatter.GetResponseData(*q, &map[string][]string{})
     c.Negotiate(http.StatusOK, *getNegotiate(response))
 }
 
 func addHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
     d := &questionData{}
     if err := c.Bind(d); err != nil {
         errData := errors_formatter.FormatErrors(err)
@@ -117,24 +432,151 @@ func addHandler(c *gin.Context) {
         return
     }
 
-    q := &questions.Question{}
+    q := &questions.Question{UserId: userId}
     d.Bind(q)
     uc := getUsecase()
-    if err := addQuestion(uc, q); err != nil {
+    err := addQuestion(c.Request.Context(), uc, q)
+    if errors.Is(err, questions.ErrGroupNotFound) {
+        groupNotFound(c)
+        return
+    }
+    if err != nil {
         log.Error(errors.Wrap(err, "Can't add question"))
         c.AbortWithStatus(http.StatusInternalServerError)
         return
     }
 
+    setETag(c, q)
     response := rest_api_response_formatter.GetResponseData(*q, &map[string][]string{})
     c.Negotiate(http.StatusOK, *getNegotiate(response))
 }
 
+// Файл передается multipart-полем file. Строки с ошибками отклоняются,
+// остальные создаются в одной транзакции
+func importHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    // Тело запроса без Content-Length или с неверным Content-Length обрывается на maxImportSize
+    if c.Request.ContentLength > maxImportSize {
+        fileError(c, errFileTooLarge)
+        return
+    }
+    c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+    d := &importData{}
+    if err := c.Bind(d); err != nil {
+        errData := errors_formatter.FormatErrors(err)
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    fh, err := c.FormFile("file")
+    if err != nil {
+        fileError(c, errors.New("file is required"))
+        return
+    }
+
+    f, err := fh.Open()
+    if err != nil {
+        log.Error(errors.Wrapf(err, "Can't open import file %s", fh.Filename))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+    defer f.Close()
+
+    uc := getUsecase()
+    var rows []importRow
+    var newGroupIds []uint64
+    if d.IsApkg(fh.Filename) {
+        cards, err := parseApkgImport(f, fh.Size)
+        if err != nil {
+            fileError(c, errors.Cause(err))
+            return
+        }
+
+        rows, newGroupIds, err = newApkgImportRows(c.Request.Context(), uc, cards, d.GroupId, userId)
+        if err != nil {
+            log.Error(errors.Wrap(err, "Can't prepare apkg cards for import"))
+            c.AbortWithStatus(http.StatusInternalServerError)
+            return
+        }
+    } else {
+        rows, err = parseImport(f, d.Comma(fh.Filename), d.GroupId)
+        if err != nil {
+            fileError(c, errors.Cause(err))
+            return
+        }
+    }
+
+    results, err := importQuestions(c.Request.Context(), uc, rows, userId)
+    if err != nil {
+        removeImportGroups(uc, newGroupIds, userId)
+        log.Error(errors.Wrap(err, "Can't import questions"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    created := 0
+    for _, r := range results {
+        if r.Errors == nil {
+            created++
+        }
+    }
+    if created == 0 {
+        removeImportGroups(uc, newGroupIds, userId)
+    }
+
+    response := rest_api_response_formatter.GetResponseData(gin.H{
+        "created":  created,
+        "rejected": len(results) - created,
+        "rows":     results,
+    }, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+// Операции выполняются по порядку в одной транзакции. Операция с ошибкой пропускается,
+// а ее статус и ошибки возвращаются в results под тем же индексом
+func batchHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    d := &batchData{}
+    if err := c.Bind(d); err != nil {
+        errData := errors_formatter.FormatErrors(err)
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    results, err := batchQuestions(c.Request.Context(), getUsecase(), d.Operations, userId)
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't execute batch"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    response := rest_api_response_formatter.GetResponseData(gin.H{
+        "results": results,
+    }, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
 func correctHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
     id := getIdFomRequest(c)
     uc := getUsecase()
 
-    q, err := getQuestion(uc, id)
+    q, err := getQuestion(c.Request.Context(), uc, id, userId)
     if err != nil {
         log.Error(errors.Wrapf(err, "Can't get question by id %d", id))
         c.AbortWithStatus(http.StatusInternalServerError)
@@ -146,6 +588,10 @@ func correctHandler(c *gin.Context) {
         return
     }
 
+    if !checkIfMatch(c, q) {
+        return
+    }
+
     d := &questionData{}
     if err := c.Bind(d); err != nil {
         errData := errors_formatter.FormatErrors(err)
@@ -155,21 +601,106 @@ func correctHandler(c *gin.Context) {
     }
 
     d.Bind(q)
-    if err := correctQuestion(uc, q); err != nil {
+    err = correctQuestion(c.Request.Context(), uc, q)
+    if errors.Is(err, questions.ErrGroupNotFound) {
+        groupNotFound(c)
+        return
+    }
+    if errors.Is(err, questions.ErrVersionConflict) {
+        c.AbortWithStatus(http.StatusPreconditionFailed)
+        return
+    }
+    if err != nil {
         log.Error(errors.Wrap(err, "Can't correct question"))
         c.AbortWithStatus(http.StatusInternalServerError)
         return
     }
 
+    setETag(c, q)
+    response := rest_api_response_formatter.GetResponseData(*q, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+// Тело запроса — JSON Merge Patch, тип application/merge-patch+json или application/json
+func patchHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    id := getIdFomRequest(c)
+    uc := getUsecase()
+
+    q, err := getQuestion(c.Request.Context(), uc, id, userId)
+    if err != nil {
+        log.Error(errors.Wrapf(err, "Can't get question by id %d", id))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    if nil == q {
+        c.AbortWithStatus(http.StatusNotFound)
+        return
+    }
+
+    if !checkIfMatch(c, q) {
+        return
+    }
+
+    data, err := ioutil.ReadAll(c.Request.Body)
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't read merge patch"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    p, err := decodePatch(data)
+    if err != nil {
+        response := rest_api_response_formatter.GetResponseData(struct{}{}, &map[string][]string{
+            "patch": {errors.Cause(err).Error()},
+        })
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    if errData := p.Validate(); errData != nil {
+        response := rest_api_response_formatter.GetResponseData(struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    p.Bind(q)
+    err = patchQuestion(c.Request.Context(), uc, q, p.Fields())
+    if errors.Is(err, questions.ErrGroupNotFound) {
+        groupNotFound(c)
+        return
+    }
+    if errors.Is(err, questions.ErrVersionConflict) {
+        c.AbortWithStatus(http.StatusPreconditionFailed)
+        return
+    }
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't patch question"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    setETag(c, q)
     response := rest_api_response_formatter.GetResponseData(*q, &map[string][]string{})
     c.Negotiate(http.StatusOK, *getNegotiate(response))
 }
 
+// Вопрос переносится в корзину, откуда его можно восстановить, пока он не удален окончательно
 func deleteHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
     id := getIdFomRequest(c)
     uc := getUsecase()
 
-    q, err := getQuestion(uc, id)
+    q, err := getQuestion(c.Request.Context(), uc, id, userId)
     if err != nil {
         log.Error(errors.Wrap(err, "Can't get question"))
         c.AbortWithStatus(http.StatusInternalServerError)
@@ -181,7 +712,16 @@ func deleteHandler(c *gin.Context) {
         return
     }
 
-    if err := deleteQuestion(uc, q); err != nil {
+    if !checkIfMatch(c, q) {
+        return
+    }
+
+    err = deleteQuestion(c.Request.Context(), uc, q)
+    if errors.Is(err, questions.ErrVersionConflict) {
+        c.AbortWithStatus(http.StatusPreconditionFailed)
+        return
+    }
+    if err != nil {
         log.Error(errors.Wrap(err, "Can't delete question"))
         c.AbortWithStatus(http.StatusInternalServerError)
         return
@@ -191,6 +731,132 @@ func deleteHandler(c *gin.Context) {
     c.Negotiate(http.StatusOK, *getNegotiate(response))
 }
 
+func answerHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    id := getIdFomRequest(c)
+
+    d := &answerData{}
+    if err := c.Bind(d); err != nil {
+        errData := errors_formatter.FormatErrors(err)
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    uc := getUsecase()
+    q, err := getQuestion(c.Request.Context(), uc, id, userId)
+    if err != nil {
+        log.Error(errors.Wrapf(err, "Can't get question by id %d", id))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    if nil == q {
+        c.AbortWithStatus(http.StatusNotFound)
+        return
+    }
+
+    if !checkIfMatch(c, q) {
+        return
+    }
+
+    responseTime := time.Duration(d.ResponseTime) * time.Millisecond
+    q, err = answerQuestion(c.Request.Context(), uc, id, q.Version, d.ToGrade(), responseTime)
+    if errors.Is(err, questions.ErrVersionConflict) {
+        c.AbortWithStatus(http.StatusPreconditionFailed)
+        return
+    }
+    if err != nil {
+        log.Error(errors.Wrapf(err, "Can't answer question by id %d", id))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    if nil == q {
+        c.AbortWithStatus(http.StatusNotFound)
+        return
+    }
+
+    setETag(c, q)
+    response := rest_api_response_formatter.GetResponseData(*q, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+// Вопрос возвращается из корзины в свою группу с прежним расписанием
+func restoreHandler(c *gin.Context) {
+    userId, ok := getUserIdFromRequest(c)
+    if !ok {
+        return
+    }
+
+    id := getIdFomRequest(c)
+    uc := getUsecase()
+
+    q, err := getDeletedQuestion(c.Request.Context(), uc, id, userId)
+    if err != nil {
+        log.Error(errors.Wrapf(err, "Can't get deleted question by id %d", id))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    if nil == q {
+        c.AbortWithStatus(http.StatusNotFound)
+        return
+    }
+
+    if !checkIfMatch(c, q) {
+        return
+    }
+
+    err = restoreQuestion(c.Request.Context(), uc, q)
+    if errors.Is(err, questions.ErrGroupNotFound) {
+        groupNotFound(c)
+        return
+    }
+    if errors.Is(err, questions.ErrVersionConflict) {
+        c.AbortWithStatus(http.StatusPreconditionFailed)
+        return
+    }
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't restore question"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    setETag(c, q)
+    response := rest_api_response_formatter.GetResponseData(*q, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+func groupNotFound(c *gin.Context) {
+    response := rest_api_response_formatter.GetResponseData(&struct{}{}, &map[string][]string{
+        "groupId": {questions.ErrGroupNotFound.Error()},
+    })
+    c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+}
+
+func fileError(c *gin.Context, err error) {
+    response := rest_api_response_formatter.GetResponseData(&struct{}{}, &map[string][]string{
+        "file": {err.Error()},
+    })
+    c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+}
+
+// Метод возвращает id пользователя, определенный по токену,
+// и прерывает запрос со статусом 401, если пользователь не определен
+func getUserIdFromRequest(c *gin.Context) (uint64, bool) {
+    userId, found := auth_gin.UserId(c)
+    if !found {
+        c.AbortWithStatus(http.StatusUnauthorized)
+        return 0, false
+    }
+    return userId, true
+}
+
 func getIdFomRequest(c *gin.Context) uint64 {
     idFromUrl := c.Param("id")
     result, err := strconv.ParseUint(idFromUrl, 10, 64)
@@ -201,29 +867,122 @@ func getIdFomRequest(c *gin.Context) uint64 {
     return result
 }
 
-func addQuestion(uc questions.Usecase, q *questions.Question) error {
-    err := uc.Add(q)
+func addQuestion(ctx context.Context, uc questions.Usecase, q *questions.Question) error {
+    err := uc.Add(ctx, q)
     if err != nil {
         return errors.Wrapf(err, "Can't add question via usecase")
     }
     return nil
 }
 
-func correctQuestion(uc questions.Usecase, q *questions.Question) error {
-    err := uc.Correct(q)
+// Группы колод создаются до транзакции импорта, поэтому при неудачном импорте они удаляются отдельно.
+// Удаление не зависит от контекста запроса: импорт мог прерваться как раз из-за его отмены
+func removeImportGroups(uc questions.Usecase, groupIds []uint64, userId uint64) {
+    if len(groupIds) == 0 {
+        return
+    }
+    err := uc.RemoveGroups(context.Background(), groupIds, userId)
+    if err != nil {
+        log.Error(errors.Wrapf(err, "Can't remove groups %v created for import", groupIds))
+    }
+}
+
+// Метод создает вопросы из строк, прошедших проверку,
+// и возвращает результат по каждой строке в исходном порядке
+func importQuestions(ctx context.Context, uc questions.Usecase, rows []importRow, userId uint64) ([]importResult, error) {
+    results := make([]importResult, len(rows))
+    ql := []*questions.Question{}
+    indexes := []int{}
+    for i := range rows {
+        results[i].Row = rows[i].Row
+        if errs := rows[i].Validate(); errs != nil {
+            results[i].Errors = errs
+            continue
+        }
+
+        q := &questions.Question{UserId: userId}
+        rows[i].Bind(q)
+        ql = append(ql, q)
+        indexes = append(indexes, i)
+    }
+
+    if len(ql) == 0 {
+        return results, nil
+    }
+
+    rowErrs, err := uc.Import(ctx, ql)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't import %d questions via usecase", len(ql))
+    }
+
+    for j, q := range ql {
+        i := indexes[j]
+        if rowErrs[j] != nil {
+            results[i].Errors = map[string][]string{"groupId": {questions.ErrGroupNotFound.Error()}}
+            continue
+        }
+        results[i].Id = q.ID
+    }
+    return results, nil
+}
+
+// Метод выполняет операции, прошедшие проверку, одним пакетом
+// и возвращает результат по каждой операции в исходном порядке
+func batchQuestions(ctx context.Context, uc questions.Usecase, operations []batchOperation, userId uint64) ([]batchResult, error) {
+    results := make([]batchResult, len(operations))
+    ops := []questions.BatchOperation{}
+    indexes := []int{}
+    for i := range operations {
+        if errs := operations[i].Validate(); errs != nil {
+            results[i] = batchResult{Status: http.StatusBadRequest, Errors: errs}
+            continue
+        }
+
+        ops = append(ops, operations[i].ToOperation())
+        indexes = append(indexes, i)
+    }
+
+    if len(ops) == 0 {
+        return results, nil
+    }
+
+    opResults, err := uc.Batch(ctx, userId, ops)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't execute %d operations via usecase", len(ops))
+    }
+
+    for j, r := range opResults {
+        results[indexes[j]] = newBatchResult(r)
+    }
+    return results, nil
+}
+
+func correctQuestion(ctx context.Context, uc questions.Usecase, q *questions.Question) error {
+    err := uc.Correct(ctx, q)
     if err != nil {
         return errors.Wrapf(err, "Can't correct question via usecase")
     }
     return nil
 }
 
-func deleteQuestion(uc questions.Usecase, q *questions.Question) error {
+func patchQuestion(ctx context.Context, uc questions.Usecase, q *questions.Question, fields []string) error {
+    err := uc.Patch(ctx, q, fields)
+    if err != nil {
+        return errors.Wrapf(err, "Can't patch fields %v of question via usecase", fields)
+    }
+    return nil
+}
+
+func deleteQuestion(ctx context.Context, uc questions.Usecase, q *questions.Question) error {
     if q == nil {
         log.Warn(errors.New("Can't delete question. Question is empty"))
         return nil
     }
 
-    err := uc.Delete([]interface{}{"id=?", q.ID})
+    // Вопрос, измененный после проверки If-Match, не удаляется
+    query := questions.NewQuery().Eq(questions.FieldId, q.ID).Eq(questions.FieldUserId, q.UserId).
+        Eq(questions.FieldVersion, q.Version)
+    err := uc.Delete(ctx, query)
     if err != nil {
         return errors.Wrapf(err, "Can't delete question by id %d via usecase", q.ID)
     }
@@ -231,8 +990,25 @@ func deleteQuestion(uc questions.Usecase, q *questions.Question) error {
     return nil
 }
 
-func getQuestion(uc questions.Usecase, id uint64) (*questions.Question, error) {
-    ql, _, err := uc.Find(&map[string]interface{}{"id": id}, &[]interface{}{}, 1, 0)
+func restoreQuestion(ctx context.Context, uc questions.Usecase, q *questions.Question) error {
+    err := uc.Restore(ctx, q)
+    if err != nil {
+        return errors.Wrapf(err, "Can't restore question by id %d via usecase", q.ID)
+    }
+    return nil
+}
+
+func answerQuestion(ctx context.Context, uc questions.Usecase, id, version uint64, grade questions.Grade, responseTime time.Duration) (*questions.Question, error) {
+    q, err := uc.Answer(ctx, id, version, grade, responseTime)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't answer question by id %d via usecase", id)
+    }
+    return q, nil
+}
+
+func getQuestion(ctx context.Context, uc questions.Usecase, id, userId uint64) (*questions.Question, error) {
+    query := questions.NewQuery().Eq(questions.FieldId, id).Eq(questions.FieldUserId, userId).Paginate(1, 0)
+    ql, _, err := uc.Find(ctx, query)
     if err != nil {
         return nil, errors.Wrapf(err, "Can't get question by id %d via usecase", id)
     }
@@ -244,15 +1020,69 @@ func getQuestion(uc questions.Usecase, id uint64) (*questions.Question, error) {
     return &(*ql)[0], nil
 }
 
-func getQuestionList(uc questions.Usecase, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]questions.Question, more bool, err error) {
-    ql, more, err := uc.Find(conds, order, limit, offset)
+func getDeletedQuestion(ctx context.Context, uc questions.Usecase, id, userId uint64) (*questions.Question, error) {
+    query := questions.NewQuery().Eq(questions.FieldId, id).Eq(questions.FieldUserId, userId).Paginate(1, 0)
+    ql, _, err := uc.Trash(ctx, query)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't get deleted question by id %d via usecase", id)
+    }
+
+    if len(*ql) == 0 {
+        return nil, nil
+    }
+
+    return &(*ql)[0], nil
+}
+
+func getQuestionList(ctx context.Context, uc questions.Usecase, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    ql, more, err := uc.Find(ctx, query)
+    if err != nil {
+        return nil, false, errors.Wrapf(err, "Can't get question list by query: %v via usecase", *query)
+    }
+
+    return ql, more, err
+}
+
+func getSearchList(ctx context.Context, uc questions.Usecase, text string, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    ql, more, err := uc.Search(ctx, text, query)
+    if err != nil {
+        return nil, false, errors.Wrapf(err, "Can't search question list by text: %q query: %v via usecase", text, *query)
+    }
+
+    return ql, more, err
+}
+
+func getDueList(ctx context.Context, uc questions.Usecase, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    ql, more, err := uc.Due(ctx, query)
     if err != nil {
-        return nil, false, errors.Wrapf(err, "Can't get question list by conds: %v order: %v limit: %d offset: %d via usecase", conds, order, limit, offset)
+        return nil, false, errors.Wrapf(err, "Can't get due question list by query: %v via usecase", *query)
     }
 
     return ql, more, err
 }
 
+func getTrashList(ctx context.Context, uc questions.Usecase, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    ql, more, err := uc.Trash(ctx, query)
+    if err != nil {
+        return nil, false, errors.Wrapf(err, "Can't get deleted question list by query: %v via usecase", *query)
+    }
+
+    return ql, more, err
+}
+
+// Без переданных групп выборка и так идет по всем группам пользователя
+func getDescendantGroups(ctx context.Context, uc questions.Usecase, groupIds []uint64, userId uint64) ([]uint64, error) {
+    if len(groupIds) == 0 {
+        return groupIds, nil
+    }
+
+    ids, err := uc.DescendantGroups(ctx, groupIds, userId)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't get descendants of groups %v via usecase", groupIds)
+    }
+    return ids, nil
+}
+
 func getNegotiate(data *gin.H) *gin.Negotiate {
     return &gin.Negotiate{
         Offered: []string{gin.MIMEJSON, gin.MIMEXML},
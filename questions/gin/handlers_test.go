@@ -1,8 +1,15 @@
 package gin
 
 import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
     "testing"
+    "time"
 
+    "github.com/gin-gonic/gin"
+    "github.com/golobby/container"
     "github.com/pkg/errors"
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/mock"
@@ -15,23 +22,93 @@ type usecaseMock struct {
     mock.Mock
 }
 
-func (m *usecaseMock) Add(q *questions.Question) error {
-    args := m.Called(q)
+func (m *usecaseMock) Add(ctx context.Context, q *questions.Question) error {
+    args := m.Called(ctx, q)
     return args.Error(0)
 }
 
-func (m *usecaseMock) Correct(q *questions.Question) error {
-    args := m.Called(q)
+func (m *usecaseMock) Import(ctx context.Context, ql []*questions.Question) ([]error, error) {
+    args := m.Called(ctx, ql)
+    return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *usecaseMock) Batch(ctx context.Context, userId uint64, ops []questions.BatchOperation) ([]questions.BatchResult, error) {
+    args := m.Called(ctx, userId, ops)
+    return args.Get(0).([]questions.BatchResult), args.Error(1)
+}
+
+func (m *usecaseMock) Correct(ctx context.Context, q *questions.Question) error {
+    args := m.Called(ctx, q)
     return args.Error(0)
 }
 
-func (m *usecaseMock) Delete(conds []interface{}) error {
-    args := m.Called(conds)
+func (m *usecaseMock) Patch(ctx context.Context, q *questions.Question, fields []string) error {
+    args := m.Called(ctx, q, fields)
     return args.Error(0)
 }
 
-func (m *usecaseMock) Find(conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]questions.Question, more bool, err error) {
-    args := m.Called(conds, order, limit, offset)
+func (m *usecaseMock) Delete(ctx context.Context, query *questions.Query) error {
+    args := m.Called(ctx, query)
+    return args.Error(0)
+}
+
+func (m *usecaseMock) Trash(ctx context.Context, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    args := m.Called(ctx, query)
+    return args.Get(0).(*[]questions.Question), args.Bool(1), args.Error(2)
+}
+
+func (m *usecaseMock) Restore(ctx context.Context, q *questions.Question) error {
+    args := m.Called(ctx, q)
+    return args.Error(0)
+}
+
+func (m *usecaseMock) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+    args := m.Called(ctx, retention)
+    return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *usecaseMock) Answer(ctx context.Context, id, version uint64, grade questions.Grade, responseTime time.Duration) (*questions.Question, error) {
+    args := m.Called(ctx, id, version, grade, responseTime)
+    return args.Get(0).(*questions.Question), args.Error(1)
+}
+
+func (m *usecaseMock) Due(ctx context.Context, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    args := m.Called(ctx, query)
+    return args.Get(0).(*[]questions.Question), args.Bool(1), args.Error(2)
+}
+
+func (m *usecaseMock) Search(ctx context.Context, text string, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    args := m.Called(ctx, text, query)
+    return args.Get(0).(*[]questions.Question), args.Bool(1), args.Error(2)
+}
+
+func (m *usecaseMock) CountByGroup(ctx context.Context, query *questions.Query) (*[]questions.GroupCount, error) {
+    args := m.Called(ctx, query)
+    return args.Get(0).(*[]questions.GroupCount), args.Error(1)
+}
+
+func (m *usecaseMock) DescendantGroups(ctx context.Context, groupIds []uint64, userId uint64) ([]uint64, error) {
+    args := m.Called(ctx, groupIds, userId)
+    return args.Get(0).([]uint64), args.Error(1)
+}
+
+func (m *usecaseMock) GroupNames(ctx context.Context, groupIds []uint64, userId uint64) (map[uint64]string, error) {
+    args := m.Called(ctx, groupIds, userId)
+    return args.Get(0).(map[uint64]string), args.Error(1)
+}
+
+func (m *usecaseMock) EnsureGroups(ctx context.Context, names []string, userId uint64) (map[string]uint64, []uint64, error) {
+    args := m.Called(ctx, names, userId)
+    return args.Get(0).(map[string]uint64), args.Get(1).([]uint64), args.Error(2)
+}
+
+func (m *usecaseMock) RemoveGroups(ctx context.Context, groupIds []uint64, userId uint64) error {
+    args := m.Called(ctx, groupIds, userId)
+    return args.Error(0)
+}
+
+func (m *usecaseMock) Find(ctx context.Context, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    args := m.Called(ctx, query)
     return args.Get(0).(*[]questions.Question), args.Bool(1), args.Error(2)
 }
 
@@ -39,13 +116,17 @@ func (m *usecaseMock) Find(conds *map[string]interface{}, order *[]interface{},
 //--- Add ---
 //-----------
 
+func Test_register_handlers_not_panic(t *testing.T) {
+    assert.NotPanics(t, func() { RegisterHandlers(gin.New()) }, "Маршруты вопросов не должны конфликтовать")
+}
+
 func Test_handler_add_usecase_calls_is_correct(t *testing.T) {
     qIn := &questions.Question{}
 
     uc := &usecaseMock{}
-    uc.On("Add", qIn).Return(nil)
+    uc.On("Add", mock.Anything, qIn).Return(nil)
 
-    _ = addQuestion(uc, qIn)
+    _ = addQuestion(context.Background(), uc, qIn)
 
     addCalls := 1
     if !uc.AssertNumberOfCalls(t, "Add", addCalls) {
@@ -58,9 +139,9 @@ func Test_handler_add_when_usecase_work_success_result_error_is_empty(t *testing
     qIn := &questions.Question{}
 
     uc := &usecaseMock{}
-    uc.On("Add", qIn).Return(nil)
+    uc.On("Add", mock.Anything, qIn).Return(nil)
 
-    errResult := addQuestion(uc, qIn)
+    errResult := addQuestion(context.Background(), uc, qIn)
 
     assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
 }
@@ -70,9 +151,9 @@ func Test_handler_add_usecase_work_wrong_result_error_not_empty_and_have_info_fr
     usecaseErr := errors.New("Usecase mock error")
 
     uc := &usecaseMock{}
-    uc.On("Add", qIn).Return(usecaseErr)
+    uc.On("Add", mock.Anything, qIn).Return(usecaseErr)
 
-    errResult := addQuestion(uc, qIn)
+    errResult := addQuestion(context.Background(), uc, qIn)
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
     require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
@@ -82,16 +163,133 @@ func Test_handler_add_when_usecase_work_success_result_question_contains_data_fr
     qIn := &questions.Question{ID: 0}
 
     uc := &usecaseMock{}
-    uc.On("Add", qIn).Return(nil).Run(func(args mock.Arguments) {
-        qOut := args.Get(0).(*questions.Question)
+    uc.On("Add", mock.Anything, qIn).Return(nil).Run(func(args mock.Arguments) {
+        qOut := args.Get(1).(*questions.Question)
         qOut.ID = 1
     })
 
-    _ = addQuestion(uc, qIn)
+    _ = addQuestion(context.Background(), uc, qIn)
 
     assert.Equal(t, uint64(1), qIn.ID, "Результирующий объект question должен иметь изменения, внесенные в него в usecase")
 }
 
+//--------------
+//--- Import ---
+//--------------
+
+func Test_handler_import_create_valid_rows_and_report_rejected(t *testing.T) {
+    rows := []importRow{
+        {Row: 1, Title: "Question 1", Body: "Answer 1", GroupId: 2},
+        {Row: 2, Title: "Question 2", GroupId: 2},
+        {Row: 3, Title: "Question 3", Body: "Answer 3", GroupId: 3},
+    }
+
+    uc := &usecaseMock{}
+    uc.On("Import", mock.Anything, []*questions.Question{
+        {UserId: 1, Title: "Question 1", Body: "Answer 1", GroupId: 2},
+        {UserId: 1, Title: "Question 3", Body: "Answer 3", GroupId: 3},
+    }).Return([]error{nil, questions.ErrGroupNotFound}, nil).Run(func(args mock.Arguments) {
+        ql := args.Get(1).([]*questions.Question)
+        ql[0].ID = 10
+    })
+
+    results, errResult := importQuestions(context.Background(), uc, rows, 1)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, results, 3, "Результат должен содержать все строки")
+    assert.Equal(t, importResult{Row: 1, Id: 10}, results[0], "Созданная строка должна содержать id вопроса")
+    assert.Contains(t, results[1].Errors, "body", "Строка без ответа должна быть отклонена")
+    assert.Equal(t, map[string][]string{"groupId": {questions.ErrGroupNotFound.Error()}}, results[2].Errors, "Строка с чужой группой должна быть отклонена")
+}
+
+func Test_handler_import_when_all_rows_rejected_usecase_is_not_called(t *testing.T) {
+    uc := &usecaseMock{}
+
+    results, errResult := importQuestions(context.Background(), uc, []importRow{{Row: 1}}, 1)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.NotEmpty(t, results[0].Errors, "Пустая строка должна быть отклонена")
+    uc.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+}
+
+func Test_handler_import_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Import", mock.Anything, mock.Anything).Return([]error{}, usecaseErr)
+
+    _, errResult := importQuestions(context.Background(), uc, []importRow{{Row: 1, Title: "Question", Body: "Answer", GroupId: 2}}, 1)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+func Test_remove_import_groups_remove_groups_created_for_import(t *testing.T) {
+    uc := &usecaseMock{}
+    uc.On("RemoveGroups", mock.Anything, []uint64{3, 4}, uint64(1)).Return(nil)
+
+    removeImportGroups(uc, []uint64{3, 4}, 1)
+
+    uc.AssertCalled(t, "RemoveGroups", mock.Anything, []uint64{3, 4}, uint64(1))
+}
+
+func Test_remove_import_groups_without_created_groups_usecase_is_not_called(t *testing.T) {
+    uc := &usecaseMock{}
+
+    removeImportGroups(uc, nil, 1)
+
+    uc.AssertNotCalled(t, "RemoveGroups", mock.Anything, mock.Anything, mock.Anything)
+}
+
+//-------------
+//--- Batch ---
+//-------------
+
+func Test_handler_batch_execute_valid_operations_and_report_rejected(t *testing.T) {
+    operations := []batchOperation{
+        {Action: "delete", Id: 3, Version: 2},
+        {Action: "correct", Id: 4},
+        {Action: "answer", Id: 5, answerData: answerData{Grade: "good"}},
+    }
+    qAnswered := &questions.Question{ID: 5, Version: 2}
+
+    uc := &usecaseMock{}
+    uc.On("Batch", mock.Anything, uint64(1), []questions.BatchOperation{
+        {Action: questions.BatchDelete, ID: 3, Version: 2},
+        {Action: questions.BatchAnswer, ID: 5, Grade: questions.GradeGood},
+    }).Return([]questions.BatchResult{{Err: questions.ErrVersionConflict}, {Question: qAnswered}}, nil)
+
+    results, errResult := batchQuestions(context.Background(), uc, operations, 1)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, results, 3, "Результат должен содержать все операции")
+    assert.Equal(t, http.StatusPreconditionFailed, results[0].Status, "Операция с другой версией должна вернуть 412")
+    assert.Equal(t, http.StatusBadRequest, results[1].Status, "Операция без данных вопроса должна быть отклонена")
+    assert.Contains(t, results[1].Errors, "title", "Операция без данных вопроса должна содержать ошибки полей")
+    assert.Equal(t, batchResult{Status: http.StatusOK, Question: qAnswered}, results[2], "Выполненная операция должна содержать вопрос")
+}
+
+func Test_handler_batch_when_all_operations_rejected_usecase_is_not_called(t *testing.T) {
+    uc := &usecaseMock{}
+
+    results, errResult := batchQuestions(context.Background(), uc, []batchOperation{{Action: "delete"}}, 1)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Contains(t, results[0].Errors, "id", "Операция без id должна быть отклонена")
+    uc.AssertNotCalled(t, "Batch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_handler_batch_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Batch", mock.Anything, uint64(1), mock.Anything).Return([]questions.BatchResult{}, usecaseErr)
+
+    _, errResult := batchQuestions(context.Background(), uc, []batchOperation{{Action: "delete", Id: 3}}, 1)
+
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
 //---------------
 //--- Correct ---
 //---------------
@@ -100,9 +298,9 @@ func Test_handler_correct_usecase_calls_is_correct(t *testing.T) {
     qIn := &questions.Question{}
 
     uc := &usecaseMock{}
-    uc.On("Correct", qIn).Return(nil)
+    uc.On("Correct", mock.Anything, qIn).Return(nil)
 
-    _ = correctQuestion(uc, qIn)
+    _ = correctQuestion(context.Background(), uc, qIn)
 
     correctCalls := 1
     if !uc.AssertNumberOfCalls(t, "Correct", correctCalls) {
@@ -115,9 +313,9 @@ func Test_handler_correct_when_usecase_work_success_result_error_is_empty(t *tes
     qIn := &questions.Question{}
 
     uc := &usecaseMock{}
-    uc.On("Correct", qIn).Return(nil)
+    uc.On("Correct", mock.Anything, qIn).Return(nil)
 
-    errResult := correctQuestion(uc, qIn)
+    errResult := correctQuestion(context.Background(), uc, qIn)
 
     assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
 }
@@ -127,9 +325,9 @@ func Test_handler_correct_usecase_work_wrong_result_error_not_empty_and_have_inf
     usecaseErr := errors.New("Usecase mock error")
 
     uc := &usecaseMock{}
-    uc.On("Correct", qIn).Return(usecaseErr)
+    uc.On("Correct", mock.Anything, qIn).Return(usecaseErr)
 
-    errResult := correctQuestion(uc, qIn)
+    errResult := correctQuestion(context.Background(), uc, qIn)
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
     require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
@@ -139,28 +337,57 @@ func Test_handler_correct_when_usecase_work_success_result_question_contains_dat
     qIn := &questions.Question{Title: "Title 1"}
 
     uc := &usecaseMock{}
-    uc.On("Correct", qIn).Return(nil).Run(func(args mock.Arguments) {
-        qOut := args.Get(0).(*questions.Question)
+    uc.On("Correct", mock.Anything, qIn).Return(nil).Run(func(args mock.Arguments) {
+        qOut := args.Get(1).(*questions.Question)
         qOut.Title = "Title 2"
     })
 
-    _ = correctQuestion(uc, qIn)
+    _ = correctQuestion(context.Background(), uc, qIn)
 
     assert.Equal(t, "Title 2", qIn.Title, "Результирующий объект question должен иметь изменения, внесенные в него в usecase")
 }
 
+//-------------
+//--- Patch ---
+//-------------
+
+func Test_handler_patch_usecase_calls_is_correct(t *testing.T) {
+    qIn := &questions.Question{}
+    fields := []string{questions.QuestionTitle}
+
+    uc := &usecaseMock{}
+    uc.On("Patch", mock.Anything, qIn, fields).Return(nil)
+
+    errResult := patchQuestion(context.Background(), uc, qIn, fields)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    uc.AssertNumberOfCalls(t, "Patch", 1)
+}
+
+func Test_handler_patch_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Patch", mock.Anything, mock.Anything, mock.Anything).Return(usecaseErr)
+
+    errResult := patchQuestion(context.Background(), uc, &questions.Question{}, []string{questions.QuestionTitle})
+
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
 //--------------
 //--- Delete ---
 //--------------
 
 func Test_handler_delete_usecase_calls_is_correct(t *testing.T) {
-    qIn := &questions.Question{ID: 1}
-    conds := []interface{}{"id=?", qIn.ID}
+    qIn := &questions.Question{ID: 1, UserId: 2, Version: 3}
+    query := questions.NewQuery().Eq(questions.FieldId, qIn.ID).Eq(questions.FieldUserId, qIn.UserId).
+        Eq(questions.FieldVersion, qIn.Version)
 
     uc := &usecaseMock{}
-    uc.On("Delete", conds).Return(nil)
+    uc.On("Delete", mock.Anything, query).Return(nil)
 
-    _ = deleteQuestion(uc, qIn)
+    _ = deleteQuestion(context.Background(), uc, qIn)
 
     deleteCalls := 1
     if !uc.AssertNumberOfCalls(t, "Delete", deleteCalls) {
@@ -170,43 +397,101 @@ func Test_handler_delete_usecase_calls_is_correct(t *testing.T) {
 }
 
 func Test_handler_delete_when_usecase_work_success_result_error_is_empty(t *testing.T) {
-    qIn := &questions.Question{ID: 1}
-    conds := []interface{}{"id=?", qIn.ID}
+    qIn := &questions.Question{ID: 1, UserId: 2, Version: 3}
+    query := questions.NewQuery().Eq(questions.FieldId, qIn.ID).Eq(questions.FieldUserId, qIn.UserId).
+        Eq(questions.FieldVersion, qIn.Version)
 
     uc := &usecaseMock{}
-    uc.On("Delete", conds).Return(nil)
+    uc.On("Delete", mock.Anything, query).Return(nil)
 
-    errResult := deleteQuestion(uc, qIn)
+    errResult := deleteQuestion(context.Background(), uc, qIn)
 
     assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
 }
 
 func Test_handler_delete_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
-    qIn := &questions.Question{ID: 1}
-    conds := []interface{}{"id=?", qIn.ID}
+    qIn := &questions.Question{ID: 1, UserId: 2, Version: 3}
+    query := questions.NewQuery().Eq(questions.FieldId, qIn.ID).Eq(questions.FieldUserId, qIn.UserId).
+        Eq(questions.FieldVersion, qIn.Version)
     usecaseErr := errors.New("Usecase mock error")
 
     uc := &usecaseMock{}
-    uc.On("Delete", conds).Return(usecaseErr)
+    uc.On("Delete", mock.Anything, query).Return(usecaseErr)
 
-    errResult := deleteQuestion(uc, qIn)
+    errResult := deleteQuestion(context.Background(), uc, qIn)
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
     require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
 }
 
+//--------------
+//--- Answer ---
+//--------------
+
+func Test_handler_answer_usecase_calls_is_correct(t *testing.T) {
+    id := uint64(1)
+
+    uc := &usecaseMock{}
+    uc.On("Answer", mock.Anything, id, uint64(0), questions.GradeGood, time.Second).Return(&questions.Question{ID: id}, nil)
+
+    _, _ = answerQuestion(context.Background(), uc, id, 0, questions.GradeGood, time.Second)
+
+    answerCalls := 1
+    if !uc.AssertNumberOfCalls(t, "Answer", answerCalls) {
+        t.Errorf("Метод Answer у usecase должен вызваться %d раз", answerCalls)
+        t.Fail()
+    }
+}
+
+func Test_handler_answer_when_usecase_work_success_result_error_is_empty(t *testing.T) {
+    id := uint64(1)
+
+    uc := &usecaseMock{}
+    uc.On("Answer", mock.Anything, id, uint64(0), questions.GradeAgain, time.Second).Return(&questions.Question{ID: id}, nil)
+
+    _, errResult := answerQuestion(context.Background(), uc, id, 0, questions.GradeAgain, time.Second)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+}
+
+func Test_handler_answer_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    id := uint64(1)
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Answer", mock.Anything, id, uint64(0), questions.GradeGood, time.Second).Return((*questions.Question)(nil), usecaseErr)
+
+    _, errResult := answerQuestion(context.Background(), uc, id, 0, questions.GradeGood, time.Second)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+func Test_handler_answer_when_usecase_work_success_result_question_contains_data_from_usecase(t *testing.T) {
+    id := uint64(1)
+    qExpected := &questions.Question{ID: id, Step: 2}
+
+    uc := &usecaseMock{}
+    uc.On("Answer", mock.Anything, id, uint64(0), questions.GradeGood, time.Second).Return(qExpected, nil)
+
+    qResult, _ := answerQuestion(context.Background(), uc, id, 0, questions.GradeGood, time.Second)
+
+    assert.Equal(t, *qExpected, *qResult, "Результирующий объект question должен быть идентичен тому, что вернул usecase")
+}
+
 //------------
 //--- View ---
 //------------
 
 func Test_handler_view_usecase_calls_is_correct(t *testing.T) {
     id := uint64(1)
-    conds := &map[string]interface{}{"id": id}
+    userId := uint64(2)
+    query := questions.NewQuery().Eq(questions.FieldId, id).Eq(questions.FieldUserId, userId).Paginate(1, 0)
 
     uc := &usecaseMock{}
-    uc.On("Find", conds, &[]interface{}{}, 1, 0).Return(&[]questions.Question{}, false, nil)
+    uc.On("Find", mock.Anything, query).Return(&[]questions.Question{}, false, nil)
 
-    _, _ = getQuestion(uc, id)
+    _, _ = getQuestion(context.Background(), uc, id, userId)
 
     findCalls := 1
     if !uc.AssertNumberOfCalls(t, "Find", findCalls) {
@@ -217,12 +502,13 @@ func Test_handler_view_usecase_calls_is_correct(t *testing.T) {
 
 func Test_handler_view_when_usecase_work_success_result_error_is_empty(t *testing.T) {
     id := uint64(1)
-    conds := &map[string]interface{}{"id": id}
+    userId := uint64(2)
+    query := questions.NewQuery().Eq(questions.FieldId, id).Eq(questions.FieldUserId, userId).Paginate(1, 0)
 
     uc := &usecaseMock{}
-    uc.On("Find", conds, &[]interface{}{}, 1, 0).Return(&[]questions.Question{}, false, nil)
+    uc.On("Find", mock.Anything, query).Return(&[]questions.Question{}, false, nil)
 
-    _, errResult := getQuestion(uc, id)
+    _, errResult := getQuestion(context.Background(), uc, id, userId)
 
     assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
 }
@@ -230,12 +516,13 @@ func Test_handler_view_when_usecase_work_success_result_error_is_empty(t *testin
 func Test_handler_view_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
     usecaseErr := errors.New("Usecase mock error")
     id := uint64(1)
-    conds := &map[string]interface{}{"id": id}
+    userId := uint64(2)
+    query := questions.NewQuery().Eq(questions.FieldId, id).Eq(questions.FieldUserId, userId).Paginate(1, 0)
 
     uc := &usecaseMock{}
-    uc.On("Find", conds, &[]interface{}{}, 1, 0).Return(&[]questions.Question{}, false, usecaseErr)
+    uc.On("Find", mock.Anything, query).Return(&[]questions.Question{}, false, usecaseErr)
 
-    _, errResult := getQuestion(uc, id)
+    _, errResult := getQuestion(context.Background(), uc, id, userId)
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
     require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
@@ -244,12 +531,13 @@ func Test_handler_view_usecase_work_wrong_result_error_not_empty_and_have_info_f
 func Test_handler_view_when_usecase_work_success_result_question_contains_data_from_usecase(t *testing.T) {
     qExpected := &questions.Question{Title: "Title 1"}
     id := uint64(1)
-    conds := &map[string]interface{}{"id": id}
+    userId := uint64(2)
+    query := questions.NewQuery().Eq(questions.FieldId, id).Eq(questions.FieldUserId, userId).Paginate(1, 0)
 
     uc := &usecaseMock{}
-    uc.On("Find", conds, &[]interface{}{}, 1, 0).Return(&[]questions.Question{*qExpected}, false, nil)
+    uc.On("Find", mock.Anything, query).Return(&[]questions.Question{*qExpected}, false, nil)
 
-    qResult, _ := getQuestion(uc, id)
+    qResult, _ := getQuestion(context.Background(), uc, id, userId)
 
     assert.Equal(t, *qExpected, *qResult, "Результирующий объект question должен быть идентичен тому, что вернул usecase")
 }
@@ -259,15 +547,12 @@ func Test_handler_view_when_usecase_work_success_result_question_contains_data_f
 //------------
 
 func Test_handler_list_usecase_calls_is_correct(t *testing.T) {
-    conds := &map[string]interface{}{"id": 1}
-    order := &[]interface{}{"id asc"}
-    limit := 1
-    offset := 1
+    query := questions.NewQuery().Eq(questions.FieldId, uint64(1)).OrderBy(questions.FieldId, false).Paginate(1, 1)
 
     uc := &usecaseMock{}
-    uc.On("Find", conds, order, limit, offset).Return(&[]questions.Question{}, false, nil)
+    uc.On("Find", mock.Anything, query).Return(&[]questions.Question{}, false, nil)
 
-    _, _, _ = getQuestionList(uc, conds, order, limit, offset)
+    _, _, _ = getQuestionList(context.Background(), uc, query)
 
     findCalls := 1
     if !uc.AssertNumberOfCalls(t, "Find", findCalls) {
@@ -277,30 +562,24 @@ func Test_handler_list_usecase_calls_is_correct(t *testing.T) {
 }
 
 func Test_handler_list_when_usecase_work_success_result_error_is_empty(t *testing.T) {
-    conds := &map[string]interface{}{"id": 1}
-    order := &[]interface{}{"id asc"}
-    limit := 1
-    offset := 1
+    query := questions.NewQuery().Eq(questions.FieldId, uint64(1)).OrderBy(questions.FieldId, false).Paginate(1, 1)
 
     uc := &usecaseMock{}
-    uc.On("Find", conds, order, limit, offset).Return(&[]questions.Question{}, false, nil)
+    uc.On("Find", mock.Anything, query).Return(&[]questions.Question{}, false, nil)
 
-    _, _, errResult := getQuestionList(uc, conds, order, limit, offset)
+    _, _, errResult := getQuestionList(context.Background(), uc, query)
 
     assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
 }
 
 func Test_handler_list_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
     usecaseErr := errors.New("Usecase mock error")
-    conds := &map[string]interface{}{"id": 1}
-    order := &[]interface{}{"id asc"}
-    limit := 1
-    offset := 1
+    query := questions.NewQuery().Eq(questions.FieldId, uint64(1)).OrderBy(questions.FieldId, false).Paginate(1, 1)
 
     uc := &usecaseMock{}
-    uc.On("Find", conds, order, limit, offset).Return(&[]questions.Question{}, false, usecaseErr)
+    uc.On("Find", mock.Anything, query).Return(&[]questions.Question{}, false, usecaseErr)
 
-    _, _, errResult := getQuestionList(uc, conds, order, limit, offset)
+    _, _, errResult := getQuestionList(context.Background(), uc, query)
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
     require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
@@ -309,39 +588,373 @@ func Test_handler_list_usecase_work_wrong_result_error_not_empty_and_have_info_f
 func Test_handler_list_when_usecase_work_success_result_question_contains_data_from_usecase(t *testing.T) {
     qlExpected := &[]questions.Question{{ID: 1}, {ID: 2}, {ID: 3}}
     moreExpected := true
-    conds := &map[string]interface{}{}
-    order := &[]interface{}{}
-    limit := 3
-    offset := 1
+    query := questions.NewQuery().Paginate(3, 1)
 
     uc := &usecaseMock{}
-    uc.On("Find", conds, order, limit, offset).Return(qlExpected, moreExpected, nil)
+    uc.On("Find", mock.Anything, query).Return(qlExpected, moreExpected, nil)
 
-    qlResult, moreResult, _ := getQuestionList(uc, conds, order, limit, offset)
+    qlResult, moreResult, _ := getQuestionList(context.Background(), uc, query)
 
     assert.Equal(t, *qlExpected, *qlResult, "Результирующий список объект question должен быть идентичен тому, что вернул usecase")
     assert.Equal(t, moreExpected, moreResult, "Результирующий флаг more должен быть идентичен тому, что вернул usecase")
 }
 
+//--------------
+//--- Search ---
+//--------------
+
+func Test_handler_search_when_usecase_work_success_result_is_data_from_usecase(t *testing.T) {
+    query := questions.NewQuery().Eq(questions.FieldUserId, uint64(1)).Paginate(1, 2)
+    ql := &[]questions.Question{{ID: 1}}
+
+    uc := &usecaseMock{}
+    uc.On("Search", mock.Anything, "word", query).Return(ql, true, nil)
+
+    listResult, moreResult, errResult := getSearchList(context.Background(), uc, "word", query)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, ql, listResult, "Возвращаемый список отличается от того, который вернул usecase")
+    assert.True(t, moreResult, "Флаг more должен браться из usecase")
+}
+
+func Test_handler_search_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+    query := questions.NewQuery()
+
+    uc := &usecaseMock{}
+    uc.On("Search", mock.Anything, "word", query).Return(&[]questions.Question{}, false, usecaseErr)
+
+    _, _, errResult := getSearchList(context.Background(), uc, "word", query)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+//-----------
+//--- Due ---
+//-----------
+
+func Test_handler_due_usecase_calls_is_correct(t *testing.T) {
+    query := questions.NewQuery().In(questions.FieldGroupId, []uint64{1}).Paginate(1, 1)
+
+    uc := &usecaseMock{}
+    uc.On("Due", mock.Anything, query).Return(&[]questions.Question{}, false, nil)
+
+    _, _, _ = getDueList(context.Background(), uc, query)
+
+    dueCalls := 1
+    if !uc.AssertNumberOfCalls(t, "Due", dueCalls) {
+        t.Errorf("Метод Due у usecase должен вызваться %d раз", dueCalls)
+        t.Fail()
+    }
+}
+
+func Test_handler_due_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+    query := questions.NewQuery()
+
+    uc := &usecaseMock{}
+    uc.On("Due", mock.Anything, query).Return(&[]questions.Question{}, false, usecaseErr)
+
+    _, _, errResult := getDueList(context.Background(), uc, query)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+func Test_handler_due_when_usecase_work_success_result_question_contains_data_from_usecase(t *testing.T) {
+    qlExpected := &[]questions.Question{{ID: 1}, {ID: 2}}
+    moreExpected := true
+    query := questions.NewQuery()
+
+    uc := &usecaseMock{}
+    uc.On("Due", mock.Anything, query).Return(qlExpected, moreExpected, nil)
+
+    qlResult, moreResult, _ := getDueList(context.Background(), uc, query)
+
+    assert.Equal(t, *qlExpected, *qlResult, "Результирующий список объект question должен быть идентичен тому, что вернул usecase")
+    assert.Equal(t, moreExpected, moreResult, "Результирующий флаг more должен быть идентичен тому, что вернул usecase")
+}
+
+//-------------------------
+//--- Descendant groups ---
+//-------------------------
+
+func Test_handler_descendant_groups_when_usecase_work_success_result_is_data_from_usecase(t *testing.T) {
+    uc := &usecaseMock{}
+    uc.On("DescendantGroups", mock.Anything, []uint64{1}, uint64(2)).Return([]uint64{1, 3}, nil)
+
+    idsResult, errResult := getDescendantGroups(context.Background(), uc, []uint64{1}, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []uint64{1, 3}, idsResult, "Результирующий список групп должен быть идентичен тому, что вернул usecase")
+}
+
+func Test_handler_descendant_groups_without_groups_usecase_is_not_called(t *testing.T) {
+    uc := &usecaseMock{}
+
+    idsResult, errResult := getDescendantGroups(context.Background(), uc, []uint64{}, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Empty(t, idsResult, "Результирующий список групп должен быть пустым")
+    uc.AssertNotCalled(t, "DescendantGroups", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_handler_descendant_groups_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("DescendantGroups", mock.Anything, []uint64{1}, uint64(2)).Return([]uint64{}, usecaseErr)
+
+    _, errResult := getDescendantGroups(context.Background(), uc, []uint64{1}, 2)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+//------------
+//--- User ---
+//------------
+
+func Test_handler_get_user_id_when_user_not_authenticated_request_is_aborted_with_unauthorized(t *testing.T) {
+    w := httptest.NewRecorder()
+    c, _ := gin.CreateTestContext(w)
+
+    _, ok := getUserIdFromRequest(c)
+
+    assert.False(t, ok, "Пользователь не должен быть определен")
+    assert.True(t, c.IsAborted(), "Запрос должен быть прерван")
+    assert.Equal(t, http.StatusUnauthorized, w.Code, "Статус ответа должен быть 401")
+}
+
+//---------------
+//--- Restore ---
+//---------------
+
+func Test_handler_restore_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    qIn := &questions.Question{ID: 1, UserId: 2}
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Restore", mock.Anything, qIn).Return(usecaseErr)
+
+    errResult := restoreQuestion(context.Background(), uc, qIn)
+
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+func Test_handler_get_deleted_question_search_only_in_trash_of_user(t *testing.T) {
+    query := questions.NewQuery().Eq(questions.FieldId, uint64(1)).Eq(questions.FieldUserId, uint64(2)).Paginate(1, 0)
+
+    uc := &usecaseMock{}
+    uc.On("Trash", mock.Anything, query).Return(&[]questions.Question{}, false, nil)
+
+    qResult, errResult := getDeletedQuestion(context.Background(), uc, 1, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Nil(t, qResult, "Вопрос не из корзины не должен находиться")
+}
+
+func Test_handler_restore_when_question_not_in_trash_request_is_aborted_with_not_found(t *testing.T) {
+    uc := &usecaseMock{}
+    uc.On("Trash", mock.Anything, mock.Anything).Return(&[]questions.Question{}, false, nil)
+    container.Singleton(func() questions.Usecase { return uc })
+
+    w := httptest.NewRecorder()
+    c, _ := gin.CreateTestContext(w)
+    c.Set("auth.userId", uint64(7))
+    c.Params = gin.Params{{Key: "id", Value: "1"}}
+    c.Request = httptest.NewRequest(http.MethodPost, "/v1/question/1/restore", nil)
+
+    restoreHandler(c)
+
+    assert.Equal(t, http.StatusNotFound, w.Code, "Статус ответа должен быть 404")
+    uc.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+}
+
+func Test_trash_filter_to_query_retern_correct_query(t *testing.T) {
+    queryExpected := questions.NewQuery().Eq(questions.FieldUserId, uint64(4)).Paginate(10, 20)
+    f := &trashFilter{Limit: 10, Offset: 20}
+
+    queryResult := f.ToQuery(4)
+
+    assert.Equal(t, queryExpected, queryResult, "Результирующий запрос неверный")
+}
+
+//------------
+//--- ETag ---
+//------------
+
+func Test_handler_patch_when_if_match_differs_request_is_aborted_with_precondition_failed(t *testing.T) {
+    q := questions.Question{ID: 1, UserId: 7, Version: 2}
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, mock.Anything).Return(&[]questions.Question{q}, false, nil)
+    container.Singleton(func() questions.Usecase { return uc })
+
+    w := httptest.NewRecorder()
+    c, _ := gin.CreateTestContext(w)
+    c.Set("auth.userId", uint64(7))
+    c.Params = gin.Params{{Key: "id", Value: "1"}}
+    c.Request = httptest.NewRequest(http.MethodPatch, "/v1/question/1", strings.NewReader(`{"title":"New"}`))
+    c.Request.Header.Set("If-Match", `"1"`)
+
+    patchHandler(c)
+
+    assert.Equal(t, http.StatusPreconditionFailed, w.Code, "Статус ответа должен быть 412")
+    uc.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_handler_patch_when_version_conflict_request_is_aborted_with_precondition_failed(t *testing.T) {
+    q := questions.Question{ID: 1, UserId: 7, Version: 2}
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, mock.Anything).Return(&[]questions.Question{q}, false, nil)
+    uc.On("Patch", mock.Anything, mock.Anything, []string{questions.QuestionTitle}).Return(questions.ErrVersionConflict)
+    container.Singleton(func() questions.Usecase { return uc })
+
+    w := httptest.NewRecorder()
+    c, _ := gin.CreateTestContext(w)
+    c.Set("auth.userId", uint64(7))
+    c.Params = gin.Params{{Key: "id", Value: "1"}}
+    c.Request = httptest.NewRequest(http.MethodPatch, "/v1/question/1", strings.NewReader(`{"title":"New"}`))
+    c.Request.Header.Set("If-Match", `"2"`)
+
+    patchHandler(c)
+
+    assert.Equal(t, http.StatusPreconditionFailed, w.Code, "Статус ответа должен быть 412")
+}
+
+func Test_handler_delete_when_version_conflict_request_is_aborted_with_precondition_failed(t *testing.T) {
+    q := questions.Question{ID: 1, UserId: 7, Version: 2}
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, mock.Anything).Return(&[]questions.Question{q}, false, nil)
+    uc.On("Delete", mock.Anything, mock.Anything).Return(questions.ErrVersionConflict)
+    container.Singleton(func() questions.Usecase { return uc })
+
+    w := httptest.NewRecorder()
+    c, _ := gin.CreateTestContext(w)
+    c.Set("auth.userId", uint64(7))
+    c.Params = gin.Params{{Key: "id", Value: "1"}}
+    c.Request = httptest.NewRequest(http.MethodDelete, "/v1/question/1", nil)
+    c.Request.Header.Set("If-Match", `"2"`)
+
+    deleteHandler(c)
+
+    assert.Equal(t, http.StatusPreconditionFailed, w.Code, "Статус ответа должен быть 412")
+}
+
+func Test_handler_view_response_has_etag_with_question_version(t *testing.T) {
+    q := questions.Question{ID: 1, UserId: 7, Version: 4}
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, mock.Anything).Return(&[]questions.Question{q}, false, nil)
+    container.Singleton(func() questions.Usecase { return uc })
+
+    w := httptest.NewRecorder()
+    c, _ := gin.CreateTestContext(w)
+    c.Set("auth.userId", uint64(7))
+    c.Params = gin.Params{{Key: "id", Value: "1"}}
+    c.Request = httptest.NewRequest(http.MethodGet, "/v1/question/1", nil)
+    c.Request.Header.Set("Accept", "application/json")
+
+    viewHandler(c)
+
+    assert.Equal(t, http.StatusOK, w.Code, "Статус ответа должен быть 200")
+    assert.Equal(t, `"4"`, w.Header().Get("ETag"), "ETag должен содержать версию вопроса")
+}
+
 //--------------
 //--- Filter ---
 //--------------
 
-func Test_filter_to_conds_retern_correct_conditions(t *testing.T) {
+func Test_filter_to_query_retern_correct_query(t *testing.T) {
     groupIdList := []uint64{1, 2, 3}
-    userIdList := []uint64{3, 4, 5}
-    condsExpected := &map[string]interface{}{
-        questions.QuestionGroupId: groupIdList,
-        questions.QuestionUserId:  userIdList,
-    }
+    userId := uint64(4)
+    queryExpected := questions.NewQuery().
+        Eq(questions.FieldUserId, userId).
+        In(questions.FieldGroupId, groupIdList).
+        OrderBy(questions.FieldId, true).
+        Paginate(10, 20)
     f := &filter{
         GroupId: groupIdList,
-        UserId:  userIdList,
+        Limit:   10,
+        Offset:  20,
     }
 
-    condsResult := f.ToConds()
+    queryResult := f.ToQuery(userId)
+
+    assert.Equal(t, queryExpected, queryResult, "Результирующий запрос неверный")
+}
+
+func Test_filter_to_query_with_ranges_retern_correct_query(t *testing.T) {
+    before := time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)
+    after := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+    isFailed := true
+    step := uint8(2)
+    queryExpected := questions.NewQuery().
+        Eq(questions.FieldUserId, uint64(4)).
+        Lt(questions.FieldRepeatTime, before).
+        Gt(questions.FieldRepeatTime, after).
+        Eq(questions.FieldIsFailed, true).
+        Eq(questions.FieldStep, uint8(2)).
+        OrderBy(questions.FieldId, true)
+    f := &filter{RepeatBefore: &before, RepeatAfter: &after, IsFailed: &isFailed, Step: &step}
+
+    queryResult := f.ToQuery(4)
+
+    assert.Equal(t, queryExpected, queryResult, "Результирующий запрос неверный")
+}
+
+func Test_filter_to_query_sort_by_field_then_by_id_in_same_direction(t *testing.T) {
+    desc := (&filter{Sort: "-repeatTime"}).ToQuery(4)
+    asc := (&filter{Sort: "title"}).ToQuery(4)
+    byId := (&filter{Sort: "id"}).ToQuery(4)
 
-    assert.Equal(t, *condsExpected, *condsResult, "Результирующий список кондишенов неверный")
+    assert.Equal(t, []questions.Sort{{Field: questions.FieldRepeatTime, Desc: true}, {Field: questions.FieldId, Desc: true}}, desc.Sort, "Сортировка по убыванию неверная")
+    assert.Equal(t, []questions.Sort{{Field: questions.FieldTitle}, {Field: questions.FieldId}}, asc.Sort, "Сортировка по возрастанию неверная")
+    assert.Equal(t, []questions.Sort{{Field: questions.FieldId}}, byId.Sort, "Сортировка по id не должна повторяться")
+}
+
+func Test_due_filter_to_query_retern_correct_query(t *testing.T) {
+    groupIdList := []uint64{1, 2, 3}
+    userId := uint64(4)
+    queryExpected := questions.NewQuery().
+        Eq(questions.FieldUserId, userId).
+        In(questions.FieldGroupId, groupIdList).
+        Paginate(5, 0)
+    f := &dueFilter{GroupId: groupIdList, Limit: 5}
+
+    queryResult := f.ToQuery(userId)
+
+    assert.Equal(t, queryExpected, queryResult, "Результирующий запрос неверный")
+}
+
+func Test_due_filter_to_query_without_group_retern_only_user_condition(t *testing.T) {
+    userId := uint64(4)
+    f := &dueFilter{}
+
+    queryResult := f.ToQuery(userId)
+
+    assert.Equal(t, questions.NewQuery().Eq(questions.FieldUserId, userId), queryResult, "Запрос должен содержать только условие на пользователя")
+}
+
+//-------------------
+//--- Answer data ---
+//-------------------
+
+func Test_answer_data_to_grade_use_grade_name(t *testing.T) {
+    d := &answerData{Grade: "hard"}
+
+    assert.Equal(t, questions.GradeHard, d.ToGrade(), "Результирующая оценка неверная")
+}
+
+func Test_answer_data_to_grade_map_remembered_flag_to_good_and_again(t *testing.T) {
+    remembered := true
+    forgot := false
+
+    dRemembered := &answerData{Remembered: &remembered}
+    dForgot := &answerData{Remembered: &forgot}
+
+    assert.Equal(t, questions.GradeGood, dRemembered.ToGrade(), "Для remembered=true оценка должна быть good")
+    assert.Equal(t, questions.GradeAgain, dForgot.ToGrade(), "Для remembered=false оценка должна быть again")
 }
 
 //---------------------
@@ -369,3 +982,61 @@ func Test_question_data_bind_retern_correct_question_object(t *testing.T) {
 
     assert.Equal(t, *qExpected, *qIn, "Результирующий объект question неверный")
 }
+
+func Test_question_data_bind_when_tags_are_set_replace_tags_of_question(t *testing.T) {
+    qWithTags := &questions.Question{Tags: []questions.Tag{{ID: 1, Name: "old"}}}
+    qWithoutTags := &questions.Question{Tags: []questions.Tag{{ID: 1, Name: "old"}}}
+
+    (&questionData{Tags: &[]string{"weak", "exam 2026"}}).Bind(qWithTags)
+    (&questionData{}).Bind(qWithoutTags)
+
+    assert.Equal(t, []questions.Tag{{Name: "weak"}, {Name: "exam_2026"}}, qWithTags.Tags, "Переданные метки должны заменить метки вопроса")
+    assert.Equal(t, []questions.Tag{{ID: 1, Name: "old"}}, qWithoutTags.Tags, "Без переданных меток метки вопроса не должны меняться")
+}
+
+//------------------
+//--- Tag filter ---
+//------------------
+
+func Test_tag_filter_apply_add_tags_condition_to_query(t *testing.T) {
+    query := questions.NewQuery().Eq(questions.FieldUserId, uint64(1))
+
+    f := &tagFilter{Tags: []string{"weak", "exam", "weak"}, TagMode: "all"}
+    f.Apply(query)
+
+    assert.Equal(t, questions.NewQuery().Eq(questions.FieldUserId, uint64(1)).Tagged([]string{"weak", "exam"}, true), query, "Выборка должна быть ограничена вопросами с метками")
+}
+
+func Test_tag_filter_apply_without_tags_query_is_not_changed(t *testing.T) {
+    query := questions.NewQuery()
+
+    f := &tagFilter{}
+    f.Apply(query)
+
+    assert.Empty(t, query.Where, "Без меток условия выборки не должны меняться")
+}
+
+//---------------
+//--- Context ---
+//---------------
+
+type ctxKey string
+
+func Test_handler_view_pass_request_context_to_usecase(t *testing.T) {
+    ctx := context.WithValue(context.Background(), ctxKey("request"), "view")
+    q := questions.Question{ID: 1, UserId: 7, Version: 1}
+    uc := &usecaseMock{}
+    uc.On("Find", ctx, mock.Anything).Return(&[]questions.Question{q}, false, nil)
+    container.Singleton(func() questions.Usecase { return uc })
+
+    w := httptest.NewRecorder()
+    c, _ := gin.CreateTestContext(w)
+    c.Set("auth.userId", uint64(7))
+    c.Params = gin.Params{{Key: "id", Value: "1"}}
+    c.Request = httptest.NewRequest(http.MethodGet, "/v1/question/1", nil).WithContext(ctx)
+
+    viewHandler(c)
+
+    assert.Equal(t, http.StatusOK, w.Code, "Статус ответа должен быть 200")
+    uc.AssertCalled(t, "Find", ctx, mock.Anything)
+}
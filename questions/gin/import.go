@@ -0,0 +1,236 @@
+package gin
+
+import (
+    "context"
+    "encoding/csv"
+    "io"
+    "path/filepath"
+    "strconv"
+    "strings"
+
+    errors_formatter "github.com/chudoyoudo/errors-formatter"
+    "github.com/gin-gonic/gin/binding"
+    "github.com/pkg/errors"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+    "github.com/chudoyoudo/remember-cards/questions/apkg"
+)
+
+const (
+    // Максимальное количество строк в одном файле импорта
+    maxImportRows = 5000
+    // Максимальный размер запроса с файлом импорта
+    maxImportSize = 64 << 20
+)
+
+var (
+    errTooManyRows     = errors.Errorf("File must contain at most %d rows", maxImportRows)
+    errFileTooLarge    = errors.Errorf("File must be at most %d MB", maxImportSize>>20)
+    errGroupIdNotValid = errors.New("groupId must be a positive number")
+)
+
+// Параметры импорта. Формат по умолчанию определяется по расширению файла:
+// .tsv и .txt (экспорт Anki) читаются как TSV, .apkg как колода Anki, остальные как CSV.
+// groupId используется для строк, в которых группа не указана.
+// Карточки колоды Anki попадают в группы с именами их колод, а если указан groupId, то в эту группу
+type importData struct {
+    Format  string `form:"format" binding:"omitempty,oneof=csv tsv apkg"`
+    GroupId uint64 `form:"groupId"`
+}
+
+func (d *importData) IsApkg(filename string) bool {
+    if d.Format == "" {
+        return strings.ToLower(filepath.Ext(filename)) == ".apkg"
+    }
+    return d.Format == "apkg"
+}
+
+func (d *importData) Comma(filename string) rune {
+    format := d.Format
+    if format == "" {
+        switch strings.ToLower(filepath.Ext(filename)) {
+        case ".tsv", ".txt":
+            format = "tsv"
+        }
+    }
+
+    if format == "tsv" {
+        return '\t'
+    }
+    return ','
+}
+
+// Строка файла в порядке колонок: вопрос, ответ, группа, метки через пробел, как в экспорте Anki.
+// У карточек колоды Anki есть расписание, которое сохраняется при импорте
+type importRow struct {
+    Row     int
+    Title   string   `binding:"required"`
+    Body    string   `binding:"required"`
+    GroupId uint64   `binding:"required"`
+    Tags    []string `binding:"-"`
+
+    groupErr error
+    schedule *questions.Question
+}
+
+func (r *importRow) Validate() map[string][]string {
+    if r.groupErr != nil {
+        return map[string][]string{"groupId": {r.groupErr.Error()}}
+    }
+
+    if err := binding.Validator.ValidateStruct(r); err != nil {
+        return errors_formatter.FormatErrors(err)
+    }
+    return nil
+}
+
+func (r *importRow) Bind(q *questions.Question) {
+    q.Title = r.Title
+    q.Body = r.Body
+    q.GroupId = r.GroupId
+    if len(r.Tags) > 0 {
+        q.Tags = questions.NewTags(r.Tags)
+    }
+
+    if r.schedule != nil {
+        q.Step = r.schedule.Step
+        q.RepeatTime = r.schedule.RepeatTime
+        q.IsFailed = r.schedule.IsFailed
+        q.Ease = r.schedule.Ease
+        q.Interval = r.schedule.Interval
+        q.Stability = r.schedule.Stability
+        q.Difficulty = r.schedule.Difficulty
+        q.LastReview = r.schedule.LastReview
+    }
+}
+
+// Результат импорта строки: id созданного вопроса или ошибки, по которым строка отклонена
+type importResult struct {
+    Row    int                 `json:"row"`
+    Id     uint64              `json:"id,omitempty"`
+    Errors map[string][]string `json:"errors,omitempty"`
+}
+
+// Метод читает строки файла импорта. Строки, начинающиеся с #, считаются комментариями,
+// как заголовки экспорта Anki, а первая строка пропускается, если это заголовок колонок.
+// Номер строки считается по записям файла без комментариев
+func parseImport(r io.Reader, comma rune, defaultGroupId uint64) ([]importRow, error) {
+    reader := csv.NewReader(r)
+    reader.Comma = comma
+    reader.Comment = '#'
+    reader.FieldsPerRecord = -1
+    reader.LazyQuotes = true
+
+    rows := []importRow{}
+    for n := 1; ; n++ {
+        record, err := reader.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, errors.Wrapf(err, "Can't read row %d", n)
+        }
+
+        if n == 1 && isImportHeader(record) {
+            continue
+        }
+        if len(rows) == maxImportRows {
+            return nil, errTooManyRows
+        }
+
+        rows = append(rows, newImportRow(n, record, defaultGroupId))
+    }
+
+    return rows, nil
+}
+
+func newImportRow(n int, record []string, defaultGroupId uint64) importRow {
+    row := importRow{Row: n, GroupId: defaultGroupId}
+    column := func(i int) string {
+        if i < len(record) {
+            return strings.TrimSpace(record[i])
+        }
+        return ""
+    }
+
+    row.Title = column(0)
+    row.Body = column(1)
+
+    if group := column(2); group != "" {
+        groupId, err := strconv.ParseUint(group, 10, 64)
+        if err != nil || groupId == 0 {
+            row.groupErr = errGroupIdNotValid
+        }
+        row.GroupId = groupId
+    }
+
+    if tags := column(3); tags != "" {
+        row.Tags = strings.Fields(tags)
+    }
+
+    return row
+}
+
+func isImportHeader(record []string) bool {
+    if len(record) == 0 {
+        return false
+    }
+    switch strings.ToLower(strings.TrimSpace(record[0])) {
+    case "front", "title", "question":
+        return true
+    }
+    return false
+}
+
+// Метод читает карточки колоды Anki. Номер строки — порядковый номер карточки
+func parseApkgImport(r io.ReaderAt, size int64) ([]apkg.Card, error) {
+    cards, err := apkg.Read(r, size)
+    if err != nil {
+        return nil, errors.Wrap(err, "Can't read apkg package")
+    }
+    if len(cards) > maxImportRows {
+        return nil, errTooManyRows
+    }
+    return cards, nil
+}
+
+// Метод переводит карточки Anki в строки импорта. Группы колод находятся по именам
+// или создаются, если groupId не указан. Возвращает id созданных групп,
+// чтобы удалить их, если импорт не создаст ни одного вопроса
+func newApkgImportRows(ctx context.Context, uc questions.Usecase, cards []apkg.Card, defaultGroupId, userId uint64) ([]importRow, []uint64, error) {
+    groupIds := map[string]uint64{}
+    created := []uint64{}
+    if defaultGroupId == 0 {
+        names := []string{}
+        for _, card := range cards {
+            if _, found := groupIds[card.Deck]; !found {
+                groupIds[card.Deck] = 0
+                names = append(names, card.Deck)
+            }
+        }
+
+        var err error
+        groupIds, created, err = uc.EnsureGroups(ctx, names, userId)
+        if err != nil {
+            return nil, nil, errors.Wrapf(err, "Can't ensure groups for decks %v", names)
+        }
+    }
+
+    rows := make([]importRow, 0, len(cards))
+    for i := range cards {
+        card := &cards[i]
+        groupId := defaultGroupId
+        if groupId == 0 {
+            groupId = groupIds[card.Deck]
+        }
+        rows = append(rows, importRow{
+            Row:      i + 1,
+            Title:    strings.TrimSpace(card.Question.Title),
+            Body:     strings.TrimSpace(card.Question.Body),
+            GroupId:  groupId,
+            Tags:     questions.TagNames(card.Question.Tags),
+            schedule: &card.Question,
+        })
+    }
+    return rows, created, nil
+}
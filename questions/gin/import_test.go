@@ -0,0 +1,164 @@
+package gin
+
+import (
+    "context"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+    "github.com/chudoyoudo/remember-cards/questions/apkg"
+)
+
+func Test_parse_import_read_csv_rows_and_skip_header(t *testing.T) {
+    file := "front,back,group,tags\n" +
+        "Question 1,Answer 1,2,go basics\n" +
+        "\"Question, 2\",Answer 2\n"
+
+    rows, err := parseImport(strings.NewReader(file), ',', 7)
+
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []importRow{
+        {Row: 2, Title: "Question 1", Body: "Answer 1", GroupId: 2, Tags: []string{"go", "basics"}},
+        {Row: 3, Title: "Question, 2", Body: "Answer 2", GroupId: 7},
+    }, rows, "Результирующие строки неверные")
+}
+
+func Test_parse_import_read_anki_tsv_and_skip_comments(t *testing.T) {
+    file := "#separator:tab\n#html:true\n" +
+        "Question 1\tAnswer \"1\"\n"
+
+    rows, err := parseImport(strings.NewReader(file), '\t', 3)
+
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []importRow{
+        {Row: 1, Title: "Question 1", Body: "Answer \"1\"", GroupId: 3},
+    }, rows, "Результирующие строки неверные")
+}
+
+func Test_parse_import_when_group_is_not_number_row_has_group_error(t *testing.T) {
+    rows, err := parseImport(strings.NewReader("Term,Definition,Deck\n"), ',', 3)
+
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, rows, 1, "Строка с ошибкой группы не должна пропускаться")
+    assert.Equal(t, map[string][]string{"groupId": {errGroupIdNotValid.Error()}}, rows[0].Validate(), "Строка должна содержать ошибку группы")
+}
+
+func Test_parse_import_when_file_has_too_many_rows_result_error_not_empty(t *testing.T) {
+    file := strings.Repeat("Term,Definition\n", maxImportRows+1)
+
+    _, err := parseImport(strings.NewReader(file), ',', 1)
+
+    assert.Equal(t, errTooManyRows, err, "Возвращаемая ошибка должна быть errTooManyRows")
+}
+
+func Test_import_row_validate_return_errors_of_empty_fields(t *testing.T) {
+    row := &importRow{Title: "Question"}
+
+    errs := row.Validate()
+
+    assert.Contains(t, errs, "body", "Пустой ответ должен быть ошибкой")
+    assert.Contains(t, errs, "groupId", "Пустая группа должна быть ошибкой")
+    assert.NotContains(t, errs, "title", "Заполненный вопрос не должен быть ошибкой")
+}
+
+func Test_import_row_bind_retern_correct_question_object(t *testing.T) {
+    row := &importRow{Title: "Question", Body: "Answer", GroupId: 2}
+    q := &questions.Question{UserId: 1}
+
+    row.Bind(q)
+
+    assert.Equal(t, questions.Question{UserId: 1, Title: "Question", Body: "Answer", GroupId: 2}, *q, "Результирующий объект question неверный")
+}
+
+func Test_import_row_bind_set_tags_of_question(t *testing.T) {
+    row := &importRow{Title: "Question", Body: "Answer", GroupId: 2, Tags: []string{"weak", "exam-2026"}}
+    q := &questions.Question{UserId: 1}
+
+    row.Bind(q)
+
+    assert.Equal(t, []questions.Tag{{Name: "weak"}, {Name: "exam-2026"}}, q.Tags, "Метки строки должны стать метками вопроса")
+}
+
+func Test_import_row_bind_copy_schedule_of_anki_card(t *testing.T) {
+    repeatTime := time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC)
+    schedule := &questions.Question{Step: 3, Interval: 6, Ease: 2.36, RepeatTime: repeatTime}
+    row := &importRow{Title: "Question", Body: "Answer", GroupId: 2, schedule: schedule}
+    q := &questions.Question{UserId: 1}
+
+    row.Bind(q)
+
+    assert.Equal(t, questions.Question{
+        UserId:     1,
+        Title:      "Question",
+        Body:       "Answer",
+        GroupId:    2,
+        Step:       3,
+        Interval:   6,
+        Ease:       2.36,
+        RepeatTime: repeatTime,
+    }, *q, "Результирующий объект question должен содержать расписание карточки")
+}
+
+func Test_import_data_is_apkg_by_format_or_file_extension(t *testing.T) {
+    assert.True(t, (&importData{}).IsApkg("deck.APKG"), "Файл .apkg должен читаться как колода Anki")
+    assert.False(t, (&importData{}).IsApkg("deck.txt"), "Файл .txt не должен читаться как колода Anki")
+    assert.True(t, (&importData{Format: "apkg"}).IsApkg("deck.zip"), "Явно указанный формат важнее расширения")
+}
+
+func Test_new_apkg_import_rows_put_cards_to_groups_of_decks(t *testing.T) {
+    cards := []apkg.Card{
+        {Deck: "Subject", Question: questions.Question{Title: " Question 1 ", Body: "Answer 1", Tags: []questions.Tag{{Name: "weak"}}}},
+        {Deck: "Subject::Topic", Question: questions.Question{Title: "Question 2", Body: "Answer 2"}},
+        {Deck: "Subject", Question: questions.Question{Title: "Question 3", Body: "Answer 3"}},
+    }
+
+    uc := &usecaseMock{}
+    uc.On("EnsureGroups", mock.Anything, []string{"Subject", "Subject::Topic"}, uint64(1)).Return(map[string]uint64{"Subject": 2, "Subject::Topic": 3}, []uint64{3}, nil)
+
+    rows, createdResult, errResult := newApkgImportRows(context.Background(), uc, cards, 0, 1)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []uint64{3}, createdResult, "Должны вернуться id созданных групп")
+    require.Len(t, rows, 3, "Для каждой карточки должна быть строка")
+    assert.Equal(t, importRow{Row: 1, Title: "Question 1", Body: "Answer 1", GroupId: 2, Tags: []string{"weak"}, schedule: &cards[0].Question}, rows[0], "Строка карточки неверная")
+    assert.Equal(t, uint64(3), rows[1].GroupId, "Карточка должна попасть в группу своей колоды")
+    assert.Equal(t, 3, rows[2].Row, "Номер строки должен быть порядковым номером карточки")
+}
+
+func Test_new_apkg_import_rows_when_group_is_set_decks_are_ignored(t *testing.T) {
+    cards := []apkg.Card{{Deck: "Subject", Question: questions.Question{Title: "Question", Body: "Answer"}}}
+
+    uc := &usecaseMock{}
+
+    rows, _, errResult := newApkgImportRows(context.Background(), uc, cards, 5, 1)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, uint64(5), rows[0].GroupId, "Карточка должна попасть в указанную группу")
+    uc.AssertNotCalled(t, "EnsureGroups", mock.Anything)
+}
+
+func Test_new_apkg_import_rows_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+    cards := []apkg.Card{{Deck: "Subject"}}
+
+    uc := &usecaseMock{}
+    uc.On("EnsureGroups", mock.Anything, []string{"Subject"}, uint64(1)).Return(map[string]uint64{}, []uint64{}, usecaseErr)
+
+    _, _, errResult := newApkgImportRows(context.Background(), uc, cards, 0, 1)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+func Test_import_data_comma_by_format_or_file_extension(t *testing.T) {
+    assert.Equal(t, '\t', (&importData{}).Comma("deck.txt"), "Экспорт Anki должен читаться как TSV")
+    assert.Equal(t, '\t', (&importData{}).Comma("cards.TSV"), "Файл .tsv должен читаться как TSV")
+    assert.Equal(t, ',', (&importData{}).Comma("cards.csv"), "Файл .csv должен читаться как CSV")
+    assert.Equal(t, ',', (&importData{Format: "csv"}).Comma("cards.txt"), "Явно указанный формат важнее расширения")
+}
@@ -0,0 +1,104 @@
+package gin
+
+import (
+    "bytes"
+    "encoding/json"
+
+    errors_formatter "github.com/chudoyoudo/errors-formatter"
+    "github.com/gin-gonic/gin/binding"
+    "github.com/pkg/errors"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+var (
+    errPatchNotObject   = errors.New("merge patch must be a json object")
+    errFieldNotEditable = errors.New("field is not editable")
+    errFieldNotNullable = errors.New("field can't be null")
+)
+
+// Поля, которые можно очистить через null
+var patchNullable = map[string]bool{
+    questions.QuestionBody: true,
+    questions.QuestionTags: true,
+}
+
+// Изменение вопроса в формате JSON Merge Patch (RFC 7396): меняются только переданные поля.
+// null очищает ответ или метки, а вопрос и группу очистить нельзя
+type questionPatch struct {
+    Title   *string   `json:"title" binding:"omitempty,min=1"`
+    Body    *string   `json:"body"`
+    GroupId *uint64   `json:"groupId" binding:"omitempty,min=1"`
+    Tags    *[]string `json:"tags" binding:"omitempty,max=50,dive,max=64"`
+
+    keys map[string]json.RawMessage
+}
+
+func decodePatch(data []byte) (*questionPatch, error) {
+    p := &questionPatch{}
+    if err := json.Unmarshal(data, &p.keys); err != nil {
+        return nil, errors.Wrap(err, "Can't decode merge patch keys")
+    }
+    if p.keys == nil {
+        return nil, errPatchNotObject
+    }
+
+    if err := json.Unmarshal(data, p); err != nil {
+        return nil, errors.Wrap(err, "Can't decode merge patch")
+    }
+    return p, nil
+}
+
+func (p *questionPatch) Validate() map[string][]string {
+    result := map[string][]string{}
+    for key, raw := range p.keys {
+        if !questions.IsEditable(key) {
+            result[key] = []string{errFieldNotEditable.Error()}
+            continue
+        }
+        if bytes.Equal(raw, []byte("null")) && !patchNullable[key] {
+            result[key] = []string{errFieldNotNullable.Error()}
+        }
+    }
+    if len(result) > 0 {
+        return result
+    }
+
+    if err := binding.Validator.ValidateStruct(p); err != nil {
+        return errors_formatter.FormatErrors(err)
+    }
+    return nil
+}
+
+// Метод возвращает поля вопроса, переданные в patch
+func (p *questionPatch) Fields() []string {
+    fields := []string{}
+    for _, field := range questions.EditableFields {
+        if _, ok := p.keys[field]; ok {
+            fields = append(fields, field)
+        }
+    }
+    return fields
+}
+
+func (p *questionPatch) Bind(q *questions.Question) {
+    for _, field := range p.Fields() {
+        switch field {
+        case questions.QuestionGroupId:
+            q.GroupId = *p.GroupId
+        case questions.QuestionTitle:
+            q.Title = *p.Title
+        case questions.QuestionBody:
+            q.Body = ""
+            if p.Body != nil {
+                q.Body = *p.Body
+            }
+        case questions.QuestionTags:
+            tags := []string{}
+            if p.Tags != nil {
+                tags = *p.Tags
+            }
+            q.Tags = questions.NewTags(tags)
+        }
+    }
+}
@@ -0,0 +1,65 @@
+package gin
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+func Test_question_patch_bind_change_only_passed_fields(t *testing.T) {
+    q := &questions.Question{Title: "Front", Body: "Back", GroupId: 1, Tags: []questions.Tag{{Name: "weak"}}}
+
+    p, err := decodePatch([]byte(`{"title": "New front"}`))
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, p.Validate(), "Patch должен пройти проверку")
+    p.Bind(q)
+
+    assert.Equal(t, []string{questions.QuestionTitle}, p.Fields(), "Изменяемые поля должны совпадать с переданными")
+    assert.Equal(t, &questions.Question{Title: "New front", Body: "Back", GroupId: 1, Tags: []questions.Tag{{Name: "weak"}}}, q, "Непереданные поля не должны меняться")
+}
+
+func Test_question_patch_bind_when_null_clear_body_and_tags(t *testing.T) {
+    q := &questions.Question{Title: "Front", Body: "Back", Tags: []questions.Tag{{Name: "weak"}}}
+
+    p, err := decodePatch([]byte(`{"tags": null, "body": null}`))
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, p.Validate(), "Patch должен пройти проверку")
+    p.Bind(q)
+
+    assert.Equal(t, []string{questions.QuestionBody, questions.QuestionTags}, p.Fields(), "Изменяемые поля должны идти в порядке EditableFields")
+    assert.Equal(t, "", q.Body, "null должен очищать ответ")
+    assert.Equal(t, []questions.Tag{}, q.Tags, "null должен удалять метки")
+}
+
+func Test_question_patch_validate_reject_not_editable_and_not_nullable_fields(t *testing.T) {
+    p, err := decodePatch([]byte(`{"repeatTime": "2026-01-01T00:00:00Z", "title": null, "groupId": 0}`))
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+
+    errData := p.Validate()
+
+    assert.Equal(t, map[string][]string{
+        "repeatTime": {errFieldNotEditable.Error()},
+        "title":      {errFieldNotNullable.Error()},
+    }, errData, "Ошибки проверки неверные")
+}
+
+func Test_question_patch_validate_check_values(t *testing.T) {
+    p, err := decodePatch([]byte(`{"title": "", "groupId": 0}`))
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+
+    errData := p.Validate()
+
+    assert.Contains(t, errData, "title", "Пустой вопрос не должен проходить проверку")
+    assert.Contains(t, errData, "groupId", "Нулевая группа не должна проходить проверку")
+}
+
+func Test_decode_patch_when_body_is_not_object_result_error_not_empty(t *testing.T) {
+    _, errNull := decodePatch([]byte(`null`))
+    _, errArray := decodePatch([]byte(`["title"]`))
+
+    assert.ErrorIs(t, errNull, errPatchNotObject, "Для null должна вернуться errPatchNotObject")
+    assert.NotNil(t, errArray, "Для массива должна вернуться ошибка")
+}
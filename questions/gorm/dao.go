@@ -1,48 +1,177 @@
 package gorm
 
 import (
+	"context"
+	"time"
+
 	gorm "github.com/chudoyoudo/gorm-interface"
+	"github.com/golobby/container"
 	"github.com/pkg/errors"
+	gorm_db "gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/chudoyoudo/remember-cards/connection"
 	"github.com/chudoyoudo/remember-cards/questions"
 )
 
 type dao struct {
 	c gorm.Connection
+	// Для агрегирующих запросов, которые не поддерживает gorm.Connection
+	db *gorm_db.DB
 }
 
-func (dao *dao) Create(q *questions.Question) error {
-	result := dao.getConnection().Create(q)
-	err := result.Error()
+func (dao *dao) Create(ctx context.Context, q *questions.Question) error {
+	err := dao.ensureTags(ctx, q.UserId, q.Tags)
+	if err != nil {
+		return errors.Wrap(err, "Can't ensure tags of question")
+	}
+
+	q.Version = 1
+	result := dao.getConnection(ctx).Create(q)
+	err = result.Error()
 	if err != nil {
 		return errors.Wrapf(err, "Can't create question via connection %v", *q)
 	}
 	return nil
 }
 
-func (dao *dao) Update(q *questions.Question, fields []string) error {
+// Вопрос обновляется, только если его версия в базе совпадает с q.Version.
+// Иначе вопрос уже изменил другой запрос, и возвращается ErrVersionConflict.
+// Вопрос в корзине тоже не обновляется: gorm не добавляет условие мягкого удаления в Updates.
+// Условное обновление не поддерживает gorm.Connection, поэтому запрос идет через db
+func (dao *dao) Update(ctx context.Context, q *questions.Question, fields []string) error {
 	data := q.ToMap(fields)
-	result := dao.getConnection().Model(q).Updates(*data)
-	err := result.Error()
+	version := clause.Column{Name: "version"}
+	(*data)["version"] = gorm_db.Expr("? + 1", version)
+
+	result := dao.getDb(ctx).Model(&questions.Question{}).
+		Where(clause.Eq{Column: clause.PrimaryColumn, Value: q.ID}).
+		Where(clause.Eq{Column: version, Value: q.Version}).
+		Where(clause.Eq{Column: deletedAt, Value: nil}).
+		Updates(*data)
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "Can't update question with id %d via db %v", q.ID, data)
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrapf(questions.ErrVersionConflict, "Question with id %d has no version %d", q.ID, q.Version)
+	}
+	q.Version++
+
+	for _, field := range fields {
+		if field == questions.QuestionTags {
+			return dao.replaceTags(ctx, q)
+		}
+	}
+	return nil
+}
+
+func (dao *dao) Delete(ctx context.Context, query *questions.Query) error {
+	exprs, err := whereExprs(query)
+	if err != nil {
+		return errors.Wrapf(err, "Can't build conds of query %v", *query)
+	}
+
+	result := dao.getConnection(ctx).Delete(&questions.Question{}, conds(exprs)...)
+	err = result.Error()
 	if err != nil {
-		return errors.Wrapf(err, "Can't update question with id %d via connection %v", q.ID, data)
+		return errors.Wrapf(err, "Can't delete question via connection by query %v", *query)
+	}
+	if result.RowsAffected() == 0 && query.Has(questions.FieldVersion) {
+		return errors.Wrapf(questions.ErrVersionConflict, "No question with version by query %v", *query)
 	}
 	return nil
+}
 
+func (dao *dao) Find(ctx context.Context, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+	list, more, err = dao.find(ctx, query)
+	if err != nil {
+		return list, more, errors.Wrapf(err, "Can't find question via connection by query %v", *query)
+	}
+	return list, more, nil
 }
 
-func (dao *dao) Delete(conds ...interface{}) error {
-	result := dao.getConnection().Delete(&questions.Question{}, conds...)
-	err := result.Error()
+func (dao *dao) Search(ctx context.Context, text string, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+	ql := []questions.Question{}
+	exprs, err := whereExprs(query)
 	if err != nil {
-		return errors.Wrapf(err, "Can't delete question via connection by conds %v", conds)
+		return &ql, false, errors.Wrapf(err, "Can't build conds of query %v", *query)
 	}
-	return nil
+
+	db := dao.getDb(ctx).Model(&questions.Question{})
+	if len(exprs) > 0 {
+		db = db.Clauses(clause.Where{Exprs: exprs})
+	}
+	db = searchScope(db, text)
+
+	limit, offset := query.Page.Limit, query.Page.Offset
+	if limit > 0 {
+		db = db.Limit(limit + 1)
+	}
+
+	if offset > 0 {
+		db = db.Offset(offset)
+	}
+
+	result := db.Find(&ql)
+	if result.Error != nil {
+		return &ql, false, errors.Wrapf(result.Error, "Can't search questions via db by text %q and query %v", text, *query)
+	}
+
+	if limit > 0 && len(ql) >= limit+1 {
+		ql = ql[:limit]
+		more = true
+	}
+
+	err = dao.loadTags(ctx, ql)
+	if err != nil {
+		return &ql, false, errors.Wrap(err, "Can't load tags of found questions")
+	}
+
+	return &ql, more, nil
+}
+
+func (dao *dao) CountByGroup(ctx context.Context, query *questions.Query, dueBefore time.Time) (*[]questions.GroupCount, error) {
+	counts := []questions.GroupCount{}
+	exprs, err := whereExprs(query)
+	if err != nil {
+		return &counts, errors.Wrapf(err, "Can't build conds of query %v", *query)
+	}
+
+	groupId := clause.Column{Name: questions.QuestionGroupId}
+	repeatTime := clause.Column{Name: questions.QuestionRepeatTime}
+	db := dao.getDb(ctx).Model(&questions.Question{}).
+		Select("? AS group_id, count(*) AS total, count(CASE WHEN ? <= ? THEN 1 END) AS due", groupId, repeatTime, dueBefore)
+	if len(exprs) > 0 {
+		db = db.Clauses(clause.Where{Exprs: exprs})
+	}
+
+	result := db.Group(questions.QuestionGroupId).Scan(&counts)
+	if result.Error != nil {
+		return &counts, errors.Wrapf(result.Error, "Can't count questions by groups via db by query %v", *query)
+	}
+	return &counts, nil
 }
 
-func (dao *dao) Find(conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]questions.Question, more bool, err error) {
+func (dao *dao) WithTx(ctx context.Context, fc func(dao questions.Dao, reviewDao questions.ReviewDao) error) error {
+	return dao.getConnection(ctx).Transaction(func(tx *gorm_db.DB) error {
+		return fc(newTxDao(tx), newTxReviewDao(tx))
+	})
+}
+
+func (dao *dao) find(ctx context.Context, query *questions.Query) (list *[]questions.Question, more bool, err error) {
 	ql := []questions.Question{}
-	c := dao.getConnection()
+	exprs, err := whereExprs(query)
+	if err != nil {
+		return &ql, false, err
+	}
+
+	order, err := orderColumns(query)
+	if err != nil {
+		return &ql, false, err
+	}
+
+	c := dao.getConnection(ctx)
+	limit, offset := query.Page.Limit, query.Page.Offset
 
 	if limit > 0 {
 		c = c.Limit(limit + 1)
@@ -52,17 +181,15 @@ func (dao *dao) Find(conds *map[string]interface{}, order *[]interface{}, limit,
 		c = c.Offset(offset)
 	}
 
-	if len(*order) > 0 {
-		for _, o := range *order {
-			c = c.Order(o)
-		}
+	for _, o := range order {
+		c = c.Order(o)
 	}
 
-	result := c.Find(&ql, *conds)
+	result := c.Find(&ql, conds(exprs)...)
 
 	err = result.Error()
 	if err != nil {
-		return &ql, false, errors.Wrapf(err, "Can't find question via connection by conds %v", conds)
+		return &ql, false, err
 	}
 
 	more = false
@@ -71,12 +198,31 @@ func (dao *dao) Find(conds *map[string]interface{}, order *[]interface{}, limit,
 		more = true
 	}
 
+	err = dao.loadTags(ctx, ql)
+	if err != nil {
+		return &ql, false, err
+	}
+
 	return &ql, more, nil
 }
 
-func (dao *dao) getConnection() gorm.Connection {
+// Dao, все запросы которого выполняются в транзакции tx
+func newTxDao(tx *gorm_db.DB) *dao {
+	return &dao{db: tx}
+}
+
+func (dao *dao) getDb(ctx context.Context) *gorm_db.DB {
+	if dao.db == nil {
+		container.Make(&dao.db)
+	}
+	return dao.db.WithContext(ctx)
+}
+
+// Соединение строится поверх того же db, что и агрегирующие запросы, а не глобального
+// соединения gorm.NewConnection, поэтому dao транзакции целиком работает в ней
+func (dao *dao) getConnection(ctx context.Context) gorm.Connection {
 	if dao.c == nil {
-		dao.c = gorm.NewConnection()
+		return connection.New(dao.getDb(ctx))
 	}
 	return dao.c
 }
@@ -1,13 +1,19 @@
 package gorm
 
 import (
+    "context"
     "testing"
+    "time"
 
     gorm "github.com/chudoyoudo/gorm-interface"
     "github.com/pkg/errors"
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/mock"
     "github.com/stretchr/testify/require"
+    "gorm.io/driver/sqlite"
+    gorm_db "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+    "gorm.io/gorm/logger"
 
     "github.com/chudoyoudo/remember-cards/questions"
 )
@@ -23,7 +29,7 @@ func Test_dao_create_connection_calls_is_correct(t *testing.T) {
     c.On("Create", qIn).Return(c)
     dao := &dao{c: c}
 
-    _ = dao.Create(qIn)
+    _ = dao.Create(context.Background(), qIn)
 
     createCalls := 1
     if !c.AssertNumberOfCalls(t, "Create", createCalls) {
@@ -39,7 +45,7 @@ func Test_dao_create_when_connection_work_success_result_error_is_empty(t *testi
     c.On("Create", qIn).Return(&gorm.ConnectionMock{})
     dao := &dao{c: c}
 
-    errResult := dao.Create(qIn)
+    errResult := dao.Create(context.Background(), qIn)
 
     assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
 }
@@ -55,11 +61,23 @@ func Test_dao_create_when_connection_work_success_we_have_correct_result_questio
     })
     dao := &dao{c: c}
 
-    _ = dao.Create(qIn)
+    _ = dao.Create(context.Background(), qIn)
 
     assert.Equal(t, qExpected.ID, qIn.ID, "Результируещий объект question должен содержать данные, пришедшие из connection")
 }
 
+func Test_dao_create_question_has_first_version(t *testing.T) {
+    qIn := &questions.Question{Version: 5}
+
+    c := &gorm.ConnectionMock{}
+    c.On("Create", qIn).Return(&gorm.ConnectionMock{})
+    dao := &dao{c: c}
+
+    _ = dao.Create(context.Background(), qIn)
+
+    assert.Equal(t, uint64(1), qIn.Version, "Новый вопрос должен получить первую версию")
+}
+
 func Test_dao_create_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
     qIn := &questions.Question{}
     connectionErr := errors.New("Connection mock error")
@@ -68,7 +86,7 @@ func Test_dao_create_when_connection_work_wrong_result_error_not_empty_and_have_
     c.On("Create", qIn).Return(&gorm.ConnectionMock{Err: connectionErr})
     dao := &dao{c: c}
 
-    errResult := dao.Create(qIn)
+    errResult := dao.Create(context.Background(), qIn)
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
     assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
@@ -78,97 +96,66 @@ func Test_dao_create_when_connection_work_wrong_result_error_not_empty_and_have_
 // ---- Update ----
 // ----------------
 
-func Test_dao_update_connection_calls_is_correct(t *testing.T) {
-    qIn := &questions.Question{}
-    fields := []string{}
-
-    c := &gorm.ConnectionMock{}
-    c.On("Model", qIn).Return(c)
-    c.On("Updates", *qIn.ToMap(fields)).Return(c)
-    dao := &dao{c: c}
-
-    _ = dao.Update(qIn, fields)
-
-    modelCalls := 1
-    if !c.AssertNumberOfCalls(t, "Model", modelCalls) {
-        t.Errorf("Метод Model у connection должен вызваться %d раз", modelCalls)
-        t.Fail()
-    }
-    updatesCalls := 1
-    if !c.AssertNumberOfCalls(t, "Updates", updatesCalls) {
-        t.Errorf("Метод Updates у connection должен вызваться %d раз", updatesCalls)
-        t.Fail()
-    }
+func Test_dao_update_when_version_match_question_is_updated_and_version_is_incremented(t *testing.T) {
+    db := getTestDb(t)
+    qIn := &questions.Question{UserId: 1, GroupId: 1, Title: "Test 1", Version: 1}
+    require.Nil(t, db.Create(qIn).Error)
+    dao := &dao{db: db}
+
+    qIn.Title = "Test 2"
+    errResult := dao.Update(context.Background(), qIn, []string{questions.QuestionTitle})
+
+    stored := &questions.Question{}
+    db.First(stored, qIn.ID)
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, "Test 2", stored.Title, "Поле вопроса должно быть изменено")
+    assert.Equal(t, uint64(2), stored.Version, "Версия вопроса в базе должна увеличиться")
+    assert.Equal(t, uint64(2), qIn.Version, "Версия вопроса должна совпадать с версией в базе")
 }
 
-func Test_dao_update_when_connection_work_success_result_error_is_empty(t *testing.T) {
-    qIn := &questions.Question{}
-    fields := []string{}
-
-    c := &gorm.ConnectionMock{}
-    c.On("Model", qIn).Return(c)
-    c.On("Updates", *qIn.ToMap(fields)).Return(c)
-    dao := &dao{c: c}
-
-    errResult := dao.Update(qIn, fields)
-
-    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+func Test_dao_update_when_version_is_stale_result_error_is_version_conflict(t *testing.T) {
+    db := getTestDb(t)
+    qIn := &questions.Question{UserId: 1, GroupId: 1, Title: "Test 1", Version: 2}
+    require.Nil(t, db.Create(qIn).Error)
+    dao := &dao{db: db}
+
+    qIn.Title = "Test 2"
+    qIn.Version = 1
+    errResult := dao.Update(context.Background(), qIn, []string{questions.QuestionTitle})
+
+    stored := &questions.Question{}
+    db.First(stored, qIn.ID)
+    require.ErrorIs(t, errResult, questions.ErrVersionConflict, "Возвращаемая ошибка должна быть ErrVersionConflict")
+    assert.Equal(t, "Test 1", stored.Title, "Вопрос с другой версией не должен измениться")
+    assert.Equal(t, uint64(1), qIn.Version, "Версия вопроса при конфликте не должна меняться")
 }
 
-func Test_dao_update_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
-    qIn := &questions.Question{}
-    fields := []string{}
-    connectionErr := errors.New("Connection mock error")
+func Test_dao_update_when_db_work_wrong_result_error_not_empty(t *testing.T) {
+    db := getTestDb(t)
+    require.Nil(t, db.Migrator().DropTable(&questions.Question{}))
+    dao := &dao{db: db}
 
-    c := &gorm.ConnectionMock{}
-    c.On("Model", qIn).Return(c)
-    c.On("Updates", *qIn.ToMap(fields)).Return(&gorm.ConnectionMock{Err: connectionErr})
-    dao := &dao{c: c}
-
-    errResult := dao.Update(qIn, fields)
+    errResult := dao.Update(context.Background(), &questions.Question{ID: 1, Version: 1}, []string{questions.QuestionTitle})
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
-    assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
-}
-
-func Test_dao_update_when_connection_work_success_we_have_correct_result_question(t *testing.T) {
-    fields := []string{}
-    qIn := &questions.Question{
-        ID:    1,
-        Title: "Test 1",
-    }
-    qExpected := &questions.Question{
-        ID:    1,
-        Title: "Test 2",
-    }
-
-    cUpdates := &gorm.ConnectionMock{}
-    cUpdates.On("Updates", *qIn.ToMap(fields)).Return(&gorm.ConnectionMock{})
-    c := &gorm.ConnectionMock{}
-    c.On("Model", qIn).Return(cUpdates).Run(func(args mock.Arguments) {
-        qIn := args.Get(0).(*questions.Question)
-        qIn.Title = "Test 2"
-    })
-    dao := &dao{c: c}
-
-    _ = dao.Update(qIn, fields)
-
-    assert.Equal(t, qExpected, qIn, "Результируещий объект question не содержит изменения из connection")
+    assert.False(t, errors.Is(errResult, questions.ErrVersionConflict), "Ошибка базы не должна быть конфликтом версий")
 }
 
 // ----------------
 // ---- Delete ----
 // ----------------
 
+var idEq = clause.Eq{Column: clause.Column{Name: "id"}, Value: uint64(1)}
+
 func Test_dao_delete_connection_calls_is_correct(t *testing.T) {
     q := &questions.Question{}
-    conds := []interface{}{uint64(1)}
+    query := questions.NewQuery().Eq(questions.FieldId, uint64(1))
 
     c := &gorm.ConnectionMock{}
-    c.On("Delete", q, conds).Return(c)
+    c.On("Delete", q, []interface{}{idEq}).Return(c)
     dao := &dao{c: c}
 
-    _ = dao.Delete(conds...)
+    _ = dao.Delete(context.Background(), query)
 
     deleteCalls := 1
     if !c.AssertNumberOfCalls(t, "Delete", deleteCalls) {
@@ -179,45 +166,54 @@ func Test_dao_delete_connection_calls_is_correct(t *testing.T) {
 
 func Test_dao_delete_when_connection_work_success_result_error_is_empty(t *testing.T) {
     q := &questions.Question{}
-    conds := []interface{}{uint64(1)}
+    query := questions.NewQuery().Eq(questions.FieldId, uint64(1))
 
     c := &gorm.ConnectionMock{}
-    c.On("Delete", q, conds).Return(&gorm.ConnectionMock{})
+    c.On("Delete", q, []interface{}{idEq}).Return(&gorm.ConnectionMock{})
     dao := &dao{c: c}
 
-    errResult := dao.Delete(conds...)
+    errResult := dao.Delete(context.Background(), query)
 
     assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
 }
 
 func Test_dao_delete_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
     q := &questions.Question{}
-    conds := []interface{}{uint64(1)}
+    query := questions.NewQuery().Eq(questions.FieldId, uint64(1))
     connectionErr := errors.New("Connection mock error")
 
     c := &gorm.ConnectionMock{}
-    c.On("Delete", q, conds).Return(&gorm.ConnectionMock{Err: connectionErr})
+    c.On("Delete", q, []interface{}{idEq}).Return(&gorm.ConnectionMock{Err: connectionErr})
     dao := &dao{c: c}
 
-    errResult := dao.Delete(conds...)
+    errResult := dao.Delete(context.Background(), query)
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
     assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
 }
 
+func Test_dao_delete_when_field_not_supported_connection_is_not_called(t *testing.T) {
+    c := &gorm.ConnectionMock{}
+    dao := &dao{c: c}
+
+    errResult := dao.Delete(context.Background(), questions.NewQuery().Eq(questions.Field("1=1 OR id"), 1))
+
+    require.ErrorIs(t, errResult, questions.ErrFieldNotSupported, "Возвращаемая ошибка должна быть ErrFieldNotSupported")
+    c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
 // --------------
 // ---- Find ----
 // --------------
 
 func Test_dao_find_when_set_conds_connection_calls_is_correct(t *testing.T) {
-    conds := &map[string]interface{}{"id": 1}
-    order := &[]interface{}{}
+    query := questions.NewQuery().Eq(questions.FieldId, uint64(1))
 
     c := &gorm.ConnectionMock{}
-    c.On("Find", &[]questions.Question{}, []interface{}{*conds}).Return(c)
+    c.On("Find", &[]questions.Question{}, []interface{}{idEq}).Return(c)
     dao := &dao{c: c}
 
-    _, _, _ = dao.Find(conds, order, 0, 0)
+    _, _, _ = dao.Find(context.Background(), query)
 
     findCalls := 1
     if !c.AssertNumberOfCalls(t, "Find", findCalls) {
@@ -226,16 +222,15 @@ func Test_dao_find_when_set_conds_connection_calls_is_correct(t *testing.T) {
 }
 
 func Test_dao_find_when_set_offset_connection_calls_is_correct(t *testing.T) {
-   conds := &map[string]interface{}{}
-   order := &[]interface{}{}
    offset := 1
+   query := questions.NewQuery().Paginate(0, offset)
 
    c := &gorm.ConnectionMock{}
-   c.On("Find", &[]questions.Question{}, []interface{}{*conds}).Return(c)
+   c.On("Find", &[]questions.Question{}, []interface{}{}).Return(c)
    c.On("Offset", offset).Return(c)
    dao := &dao{c: c}
 
-   _, _, _ = dao.Find(conds, order, 0, offset)
+   _, _, _ = dao.Find(context.Background(), query)
 
    offsetCalls := 1
    if !c.AssertNumberOfCalls(t, "Offset", offsetCalls) {
@@ -244,16 +239,15 @@ func Test_dao_find_when_set_offset_connection_calls_is_correct(t *testing.T) {
 }
 
 func Test_dao_find_when_set_limit_connection_calls_is_correct(t *testing.T) {
-   conds := &map[string]interface{}{}
-   order := &[]interface{}{}
    limit := 1
+   query := questions.NewQuery().Paginate(limit, 0)
 
    c := &gorm.ConnectionMock{}
    c.On("Limit", limit+1).Return(c)
-   c.On("Find", &[]questions.Question{}, []interface{}{*conds}).Return(c)
+   c.On("Find", &[]questions.Question{}, []interface{}{}).Return(c)
    dao := &dao{c: c}
 
-   _, _, _ = dao.Find(conds, order, limit, 0)
+   _, _, _ = dao.Find(context.Background(), query)
 
    limitCalls := 1
    if !c.AssertNumberOfCalls(t, "Limit", limitCalls) {
@@ -262,78 +256,265 @@ func Test_dao_find_when_set_limit_connection_calls_is_correct(t *testing.T) {
 }
 
 func Test_dao_find_when_set_order_connection_calls_is_correct(t *testing.T) {
-   conds := &map[string]interface{}{}
-   orderCond1 := "id asc"
-   orderCond2 := "id desc"
-   order := &[]interface{}{orderCond1, orderCond2}
+   query := questions.NewQuery().OrderBy(questions.FieldRepeatTime, false).OrderBy(questions.FieldId, true)
+   repeatTimeOrder := clause.OrderByColumn{Column: clause.Column{Name: questions.QuestionRepeatTime}}
+   idOrder := clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: true}
 
    c := &gorm.ConnectionMock{}
-   c.On("Order", orderCond1).Return(c)
-   c.On("Order", orderCond2).Return(c)
-   c.On("Find", &[]questions.Question{}, []interface{}{*conds}).Return(c)
+   c.On("Order", repeatTimeOrder).Return(c).Once()
+   c.On("Order", idOrder).Return(c).Once()
+   c.On("Find", &[]questions.Question{}, []interface{}{}).Return(c)
    dao := &dao{c: c}
 
-   _, _, _ = dao.Find(conds, order, 0, 0)
+   _, _, _ = dao.Find(context.Background(), query)
 
-   orderCalls := len(*order)
-   if !c.AssertNumberOfCalls(t, "Order", orderCalls) {
-       t.Errorf("Метод Order у connection должен вызваться %d раз", orderCalls)
-   }
+   c.AssertExpectations(t)
+   assert.Equal(t, repeatTimeOrder, c.Calls[0].Arguments.Get(0), "Сортировка должна идти в порядке запроса")
 }
 
 func Test_dao_find_when_we_have_not_more_then_limit_records_in_connection_result_more_is_false(t *testing.T) {
-   conds := &map[string]interface{}{}
-   order := &[]interface{}{}
    limit := 2
 
    cFind := &gorm.ConnectionMock{}
    cLimit := &gorm.ConnectionMock{}
-   cFind.On("Find", &[]questions.Question{}, []interface{}{*conds}).Return(&gorm.ConnectionMock{}).Run(func(args mock.Arguments) {
+   cFind.On("Find", &[]questions.Question{}, []interface{}{}).Return(&gorm.ConnectionMock{}).Run(func(args mock.Arguments) {
        qlOut := args.Get(0).(*[]questions.Question)
        *qlOut = append(*qlOut, questions.Question{ID: 1}, questions.Question{ID: 2})
    })
    cLimit.On("Limit", limit+1).Return(cFind)
 
-   dao := &dao{c: cLimit}
+   dao := &dao{c: cLimit, db: getTestDb(t)}
 
-   _, resultMore, _ := dao.Find(conds, order, limit, 0)
+   _, resultMore, _ := dao.Find(context.Background(), questions.NewQuery().Paginate(limit, 0))
 
    assert.Equal(t, false, resultMore, "Возвращаемый more флаг должно быть false")
 }
 
 func Test_dao_find_when_we_have_more_then_limit_records_in_connection_result_more_is_true(t *testing.T) {
-   conds := &map[string]interface{}{}
-   order := &[]interface{}{}
    limit := 2
 
    cFind := &gorm.ConnectionMock{}
-   cFind.On("Find", &[]questions.Question{}, []interface{}{*conds}).Return(&gorm.ConnectionMock{}).Run(func(args mock.Arguments) {
+   cFind.On("Find", &[]questions.Question{}, []interface{}{}).Return(&gorm.ConnectionMock{}).Run(func(args mock.Arguments) {
        qlOut := args.Get(0).(*[]questions.Question)
        *qlOut = append(*qlOut, questions.Question{ID: 1}, questions.Question{ID: 2}, questions.Question{ID: 3})
    })
    c := &gorm.ConnectionMock{}
    c.On("Limit", limit+1).Return(cFind)
 
-   dao := &dao{c: c}
+   dao := &dao{c: c, db: getTestDb(t)}
 
-   qlResult, resultMore, _ := dao.Find(conds, order, limit, 0)
+   qlResult, resultMore, _ := dao.Find(context.Background(), questions.NewQuery().Paginate(limit, 0))
 
    assert.Equal(t, true, resultMore, "Возвращаемый more флаг должно быть true")
    assert.Equal(t, limit, len(*qlResult), "Лишние объекты question, использовавшиеся для вычисления флага more, должны быть убраны из возвращаемого списка объектов")
 }
 
 func Test_dao_find_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
-   conds := &map[string]interface{}{}
-   order := &[]interface{}{}
    qlOut := &[]questions.Question{}
    connectionErr := errors.New("Connection mock error")
 
    c := &gorm.ConnectionMock{}
-   c.On("Find", qlOut, []interface{}{*conds}).Return(&gorm.ConnectionMock{Err: connectionErr})
+   c.On("Find", qlOut, []interface{}{}).Return(&gorm.ConnectionMock{Err: connectionErr})
    dao := &dao{c: c}
 
-   _, _, errResult := dao.Find(conds, order, 0, 0)
+   _, _, errResult := dao.Find(context.Background(), questions.NewQuery())
 
    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
    assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
 }
+
+func Test_dao_find_when_sort_field_not_supported_result_error_is_field_not_supported(t *testing.T) {
+    c := &gorm.ConnectionMock{}
+    dao := &dao{c: c}
+
+    _, _, errResult := dao.Find(context.Background(), questions.NewQuery().OrderBy(questions.Field("title; DROP TABLE questions"), false))
+
+    require.ErrorIs(t, errResult, questions.ErrFieldNotSupported, "Возвращаемая ошибка должна быть ErrFieldNotSupported")
+    c.AssertNotCalled(t, "Find", mock.Anything, mock.Anything)
+}
+
+func Test_dao_find_return_due_questions_from_most_overdue(t *testing.T) {
+    now := time.Now().UTC()
+    db := getTestDb(t)
+    db.Create(&[]questions.Question{
+        {UserId: 1, RepeatTime: now.Add(-time.Hour)},
+        {UserId: 1, RepeatTime: now.Add(time.Hour)},
+        {UserId: 1, RepeatTime: now.Add(-2 * time.Hour)},
+        {UserId: 2, RepeatTime: now.Add(-3 * time.Hour)},
+    })
+    dao := &dao{db: db}
+    query := questions.NewQuery().
+        Eq(questions.FieldUserId, uint64(1)).
+        Lte(questions.FieldRepeatTime, now).
+        OrderBy(questions.FieldRepeatTime, false).
+        OrderBy(questions.FieldId, false)
+
+    list, _, errResult := dao.Find(context.Background(), query)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []uint64{3, 1}, questionIds(list), "Должны вернуться вопросы пользователя к повторению от самых просроченных")
+}
+
+func Test_dao_find_by_id_before_return_pages_from_newest_and_not_shift_on_insert(t *testing.T) {
+    db := getTestDb(t)
+    db.Create(&[]questions.Question{{UserId: 1}, {UserId: 1}, {UserId: 1}, {UserId: 2}, {UserId: 1}})
+    dao := &dao{db: db}
+    page := func() *questions.Query {
+        return questions.NewQuery().Eq(questions.FieldUserId, uint64(1)).OrderBy(questions.FieldId, true).Paginate(2, 0)
+    }
+
+    first, moreFirst, errFirst := dao.Find(context.Background(), page())
+    db.Create(&questions.Question{UserId: 1})
+    second, moreSecond, errSecond := dao.Find(context.Background(), page().Lt(questions.FieldId, (*first)[1].ID))
+
+    require.Nil(t, errFirst, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errSecond, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []uint64{5, 3}, questionIds(first), "Первая страница должна начинаться с новых вопросов")
+    assert.True(t, moreFirst, "Флаг more первой страницы должен быть true")
+    assert.Equal(t, []uint64{2, 1}, questionIds(second), "Следующая страница не должна сдвигаться от добавленных вопросов")
+    assert.False(t, moreSecond, "Флаг more последней страницы должен быть false")
+}
+
+func Test_dao_find_by_id_in_return_only_listed_questions(t *testing.T) {
+    db := getTestDb(t)
+    db.Create(&[]questions.Question{{UserId: 1}, {UserId: 1}, {UserId: 1}})
+    dao := &dao{db: db}
+
+    list, _, errResult := dao.Find(context.Background(), questions.NewQuery().In(questions.FieldId, []uint64{1, 3}).OrderBy(questions.FieldId, false))
+    empty, _, errEmpty := dao.Find(context.Background(), questions.NewQuery().In(questions.FieldId, []uint64{}))
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errEmpty, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []uint64{1, 3}, questionIds(list), "Должны вернуться только перечисленные вопросы")
+    assert.Empty(t, *empty, "Для пустого списка id вопросов быть не должно")
+}
+
+func questionIds(ql *[]questions.Question) []uint64 {
+    ids := []uint64{}
+    for _, q := range *ql {
+        ids = append(ids, q.ID)
+    }
+    return ids
+}
+
+// ----------------------
+// ---- CountByGroup ----
+// ----------------------
+
+func getTestDb(t *testing.T) *gorm_db.DB {
+    db, err := gorm_db.Open(sqlite.Open("file::memory:"), &gorm_db.Config{Logger: logger.Discard})
+    require.Nil(t, err, "Не удалось открыть тестовую базу")
+    // У каждого соединения с памятью своя база, поэтому пул ограничен одним соединением
+    sqlDb, err := db.DB()
+    require.Nil(t, err, "Не удалось получить соединение тестовой базы")
+    sqlDb.SetMaxOpenConns(1)
+    require.Nil(t, db.AutoMigrate(&questions.Tag{}, &questions.Question{}, &questions.Review{}), "Не удалось создать таблицы вопросов")
+    return db
+}
+
+func Test_dao_count_by_group_return_total_and_due_counts_for_each_group(t *testing.T) {
+    now := time.Now().UTC()
+    db := getTestDb(t)
+    db.Create(&[]questions.Question{
+        {UserId: 1, GroupId: 1, RepeatTime: now.Add(-time.Hour)},
+        {UserId: 1, GroupId: 1, RepeatTime: now.Add(time.Hour)},
+        {UserId: 1, GroupId: 2, RepeatTime: now.Add(-time.Hour)},
+        {UserId: 2, GroupId: 1, RepeatTime: now.Add(-time.Hour)},
+    })
+    dao := &dao{db: db}
+
+    counts, errResult := dao.CountByGroup(context.Background(), questions.NewQuery().Eq(questions.FieldUserId, uint64(1)), now)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.ElementsMatch(t, []questions.GroupCount{
+        {GroupId: 1, Total: 2, Due: 1},
+        {GroupId: 2, Total: 1, Due: 1},
+    }, *counts, "Количество вопросов по группам неверное")
+}
+
+func Test_dao_count_by_group_when_db_work_wrong_result_error_not_empty(t *testing.T) {
+    db := getTestDb(t)
+    require.Nil(t, db.Migrator().DropTable(&questions.Question{}))
+    dao := &dao{db: db}
+
+    _, errResult := dao.CountByGroup(context.Background(), questions.NewQuery(), time.Now())
+
+    assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
+
+// ----------------
+// ---- WithTx ----
+// ----------------
+
+func Test_dao_without_connection_use_db_for_connection_queries(t *testing.T) {
+    db := getTestDb(t)
+    dao := &dao{db: db}
+
+    errResult := dao.Create(context.Background(), &questions.Question{UserId: 1, GroupId: 1})
+
+    var count int64
+    db.Model(&questions.Question{}).Count(&count)
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, int64(1), count, "Вопрос должен сохраниться в db dao")
+}
+
+func Test_dao_with_tx_when_fc_work_success_changes_are_committed(t *testing.T) {
+    db := getTestDb(t)
+    dao := &dao{db: db}
+
+    errResult := dao.WithTx(context.Background(), func(txDao questions.Dao, txReviewDao questions.ReviewDao) error {
+        if err := txDao.Create(context.Background(), &questions.Question{UserId: 1, GroupId: 1}); err != nil {
+            return err
+        }
+        return txDao.Create(context.Background(), &questions.Question{UserId: 1, GroupId: 1})
+    })
+
+    var count int64
+    db.Model(&questions.Question{}).Count(&count)
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, int64(2), count, "Вопросы, созданные в транзакции, должны быть сохранены")
+}
+
+func Test_dao_with_tx_when_fc_work_wrong_changes_are_rolled_back_and_result_error_have_info_from_fc(t *testing.T) {
+    db := getTestDb(t)
+    dao := &dao{db: db}
+    fcErr := errors.New("Fc error")
+
+    errResult := dao.WithTx(context.Background(), func(txDao questions.Dao, txReviewDao questions.ReviewDao) error {
+        q := &questions.Question{UserId: 1, GroupId: 1}
+        if err := txDao.Create(context.Background(), q); err != nil {
+            return err
+        }
+        if err := txReviewDao.Create(context.Background(), &questions.Review{QuestionId: q.ID}); err != nil {
+            return err
+        }
+        return fcErr
+    })
+
+    var count, reviewCount int64
+    db.Model(&questions.Question{}).Count(&count)
+    db.Model(&questions.Review{}).Count(&reviewCount)
+    require.ErrorIs(t, errResult, fcErr, "Возвращаемая ошибка должна содержать информацию из fc")
+    assert.Equal(t, int64(0), count, "Вопросы, созданные в откаченной транзакции, не должны сохраниться")
+    assert.Equal(t, int64(0), reviewCount, "История повторений, записанная в откаченной транзакции, не должна сохраниться")
+}
+
+// -----------------
+// ---- Context ----
+// -----------------
+
+func Test_dao_when_context_is_canceled_queries_are_not_executed(t *testing.T) {
+    db := getTestDb(t)
+    dao := &dao{db: db}
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+
+    errCreate := dao.Create(ctx, &questions.Question{UserId: 1, GroupId: 1})
+    _, _, errFind := dao.Find(ctx, questions.NewQuery())
+
+    var count int64
+    db.Model(&questions.Question{}).Count(&count)
+    assert.ErrorIs(t, errCreate, context.Canceled, "Возвращаемая ошибка должна быть отменой контекста")
+    assert.ErrorIs(t, errFind, context.Canceled, "Возвращаемая ошибка должна быть отменой контекста")
+    assert.Equal(t, int64(0), count, "Запрос с отмененным контекстом не должен выполниться")
+}
@@ -10,4 +10,7 @@ func init() {
     container.Transient(func() questions.Dao {
         return &dao{}
     })
+    container.Transient(func() questions.ReviewDao {
+        return &reviewDao{}
+    })
 }
@@ -0,0 +1,33 @@
+package gorm
+
+import (
+	"github.com/pkg/errors"
+	gorm_db "gorm.io/gorm"
+
+	"github.com/chudoyoudo/remember-cards/questions"
+)
+
+// Колонки, которые в старых базах названы по правилам gorm, и их новые имена
+var renamedColumns = [][2]string{
+	{"repeat_time", "repeatTime"},
+	{"is_failed", "isFailed"},
+}
+
+// Метод переименовывает колонки вопросов из старых баз. Вызывается до AutoMigrate,
+// иначе AutoMigrate добавит пустые колонки, и карточки потеряют расписание
+func RenameColumns(db *gorm_db.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&questions.Question{}) {
+		return nil
+	}
+
+	for _, c := range renamedColumns {
+		if !m.HasColumn(&questions.Question{}, c[0]) || m.HasColumn(&questions.Question{}, c[1]) {
+			continue
+		}
+		if err := m.RenameColumn(&questions.Question{}, c[0], c[1]); err != nil {
+			return errors.Wrapf(err, "Can't rename questions column %s to %s", c[0], c[1])
+		}
+	}
+	return nil
+}
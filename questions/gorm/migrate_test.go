@@ -0,0 +1,48 @@
+package gorm
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "gorm.io/driver/sqlite"
+    gorm_db "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+func getEmptyTestDb(t *testing.T) *gorm_db.DB {
+    db, err := gorm_db.Open(sqlite.Open("file::memory:"), &gorm_db.Config{Logger: logger.Discard})
+    require.Nil(t, err, "Не удалось открыть тестовую базу")
+    sqlDb, err := db.DB()
+    require.Nil(t, err, "Не удалось получить соединение тестовой базы")
+    sqlDb.SetMaxOpenConns(1)
+    return db
+}
+
+func Test_rename_columns_keep_schedule_of_questions_from_old_database(t *testing.T) {
+    db := getEmptyTestDb(t)
+    repeatTime := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+    require.Nil(t, db.Exec(`CREATE TABLE questions (id integer PRIMARY KEY, "userId" integer, "groupId" integer, title text, body text, step integer, repeat_time datetime, is_failed numeric)`).Error)
+    require.Nil(t, db.Exec(`INSERT INTO questions (id, "userId", step, repeat_time, is_failed) VALUES (1, 1, 3, ?, true)`, repeatTime).Error)
+
+    errResult := RenameColumns(db)
+    require.Nil(t, db.AutoMigrate(&questions.Question{}), "Не удалось обновить таблицу вопросов")
+
+    var q questions.Question
+    require.Nil(t, db.First(&q, 1).Error)
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.True(t, repeatTime.Equal(q.RepeatTime), "Время повторения должно сохраниться")
+    assert.True(t, q.IsFailed, "Признак ошибки должен сохраниться")
+    assert.False(t, db.Migrator().HasColumn(&questions.Question{}, "repeat_time"), "Старая колонка должна исчезнуть")
+}
+
+func Test_rename_columns_when_table_is_new_or_not_exists_result_error_is_empty(t *testing.T) {
+    errNew := RenameColumns(getTestDb(t))
+    errNotExists := RenameColumns(getEmptyTestDb(t))
+
+    assert.Nil(t, errNew, "Возвращаемая ошибка должна быть пустой")
+    assert.Nil(t, errNotExists, "Возвращаемая ошибка должна быть пустой")
+}
@@ -0,0 +1,145 @@
+package gorm
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm/clause"
+
+	"github.com/chudoyoudo/remember-cards/questions"
+)
+
+// Колонки, по которым разрешено фильтровать и сортировать вопросы
+var queryColumns = map[questions.Field]string{
+	questions.FieldId:         "id",
+	questions.FieldUserId:     questions.QuestionUserId,
+	questions.FieldGroupId:    questions.QuestionGroupId,
+	questions.FieldRepeatTime: questions.QuestionRepeatTime,
+	questions.FieldTitle:      "title",
+	questions.FieldStep:       "step",
+	questions.FieldIsFailed:   "isFailed",
+	questions.FieldVersion:    "version",
+	questions.FieldDeletedAt:  "deletedAt",
+}
+
+// Колонки, по которым разрешено фильтровать и сортировать историю повторений
+var reviewColumns = map[questions.Field]string{
+	questions.FieldId:         "id",
+	questions.FieldUserId:     questions.ReviewUserId,
+	questions.FieldQuestionId: questions.ReviewQuestionId,
+	questions.FieldAnsweredAt: "answeredAt",
+}
+
+// Метод переводит условия запроса в выражения gorm. Имена колонок берутся
+// только из queryColumns, а значения передаются параметрами запроса
+func whereExprs(q *questions.Query) ([]clause.Expression, error) {
+	exprs := []clause.Expression{}
+	for _, p := range q.Where {
+		if p.Field == questions.FieldTags {
+			expr, err := taggedExpr(p)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+			continue
+		}
+
+		expr, err := compareExpr(queryColumns, p)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+// Метод переводит условия запроса к истории повторений в выражения gorm
+func reviewWhereExprs(q *questions.Query) ([]clause.Expression, error) {
+	exprs := []clause.Expression{}
+	for _, p := range q.Where {
+		expr, err := compareExpr(reviewColumns, p)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+func compareExpr(columns map[questions.Field]string, p questions.Predicate) (clause.Expression, error) {
+	column, err := tableColumn(columns, p.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p.Op {
+	case questions.OpEq:
+		return clause.Eq{Column: column, Value: p.Value}, nil
+	case questions.OpIn:
+		return clause.IN{Column: column, Values: inValues(p.Value)}, nil
+	case questions.OpLt:
+		return clause.Lt{Column: column, Value: p.Value}, nil
+	case questions.OpLte:
+		return clause.Lte{Column: column, Value: p.Value}, nil
+	case questions.OpGt:
+		return clause.Gt{Column: column, Value: p.Value}, nil
+	case questions.OpGte:
+		return clause.Gte{Column: column, Value: p.Value}, nil
+	}
+	return nil, errors.Errorf("Operation %q is not supported in query", p.Op)
+}
+
+func orderColumns(q *questions.Query) ([]clause.OrderByColumn, error) {
+	return sortColumns(queryColumns, q)
+}
+
+func reviewOrderColumns(q *questions.Query) ([]clause.OrderByColumn, error) {
+	return sortColumns(reviewColumns, q)
+}
+
+func sortColumns(columns map[questions.Field]string, q *questions.Query) ([]clause.OrderByColumn, error) {
+	result := []clause.OrderByColumn{}
+	for _, s := range q.Sort {
+		column, err := tableColumn(columns, s.Field)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, clause.OrderByColumn{Column: column, Desc: s.Desc})
+	}
+	return result, nil
+}
+
+// Метод передает выражения условиями в gorm.Connection, который не принимает clause.Where
+func conds(exprs []clause.Expression) []interface{} {
+	result := make([]interface{}, len(exprs))
+	for i, e := range exprs {
+		result[i] = e
+	}
+	return result
+}
+
+func queryColumn(f questions.Field) (clause.Column, error) {
+	return tableColumn(queryColumns, f)
+}
+
+func tableColumn(columns map[questions.Field]string, f questions.Field) (clause.Column, error) {
+	name, ok := columns[f]
+	if !ok {
+		return clause.Column{}, errors.Wrapf(questions.ErrFieldNotSupported, "Field %q", f)
+	}
+	return clause.Column{Name: name}, nil
+}
+
+// Метод раскладывает срез значений для IN. Одиночное значение сравнивается как есть
+func inValues(value interface{}) []interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		return []interface{}{value}
+	}
+
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values
+}
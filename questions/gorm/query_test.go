@@ -0,0 +1,61 @@
+package gorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/clause"
+
+	"github.com/chudoyoudo/remember-cards/questions"
+)
+
+func Test_where_exprs_translate_predicates_to_clauses(t *testing.T) {
+	now := time.Now()
+	query := questions.NewQuery().
+		Eq(questions.FieldUserId, uint64(1)).
+		In(questions.FieldGroupId, []uint64{2, 3}).
+		Lt(questions.FieldId, uint64(10)).
+		Gt(questions.FieldId, uint64(1)).
+		Lte(questions.FieldRepeatTime, now).
+		Gte(questions.FieldRepeatTime, now)
+
+	exprs, err := whereExprs(query)
+
+	require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+	assert.Equal(t, []clause.Expression{
+		clause.Eq{Column: clause.Column{Name: questions.QuestionUserId}, Value: uint64(1)},
+		clause.IN{Column: clause.Column{Name: questions.QuestionGroupId}, Values: []interface{}{uint64(2), uint64(3)}},
+		clause.Lt{Column: clause.Column{Name: "id"}, Value: uint64(10)},
+		clause.Gt{Column: clause.Column{Name: "id"}, Value: uint64(1)},
+		clause.Lte{Column: clause.Column{Name: questions.QuestionRepeatTime}, Value: now},
+		clause.Gte{Column: clause.Column{Name: questions.QuestionRepeatTime}, Value: now},
+	}, exprs, "Условия запроса переведены неверно")
+}
+
+func Test_where_exprs_when_field_not_supported_result_error_is_field_not_supported(t *testing.T) {
+	_, err := whereExprs(questions.NewQuery().Eq(questions.Field("body"), "text"))
+
+	assert.ErrorIs(t, err, questions.ErrFieldNotSupported, "Для неизвестного поля должна вернуться ErrFieldNotSupported")
+}
+
+func Test_where_exprs_when_operation_not_supported_result_error_not_empty(t *testing.T) {
+	query := &questions.Query{Where: []questions.Predicate{{Field: questions.FieldId, Op: questions.Op("LIKE"), Value: 1}}}
+
+	_, err := whereExprs(query)
+
+	assert.NotNil(t, err, "Для неизвестной операции должна вернуться ошибка")
+}
+
+func Test_order_columns_translate_sort_to_clauses(t *testing.T) {
+	query := questions.NewQuery().OrderBy(questions.FieldRepeatTime, false).OrderBy(questions.FieldId, true)
+
+	columns, err := orderColumns(query)
+
+	require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+	assert.Equal(t, []clause.OrderByColumn{
+		{Column: clause.Column{Name: questions.QuestionRepeatTime}},
+		{Column: clause.Column{Name: "id"}, Desc: true},
+	}, columns, "Сортировка переведена неверно")
+}
@@ -0,0 +1,89 @@
+package gorm
+
+import (
+	"context"
+
+	gorm "github.com/chudoyoudo/gorm-interface"
+	"github.com/golobby/container"
+	"github.com/pkg/errors"
+	gorm_db "gorm.io/gorm"
+
+	"github.com/chudoyoudo/remember-cards/connection"
+	"github.com/chudoyoudo/remember-cards/questions"
+)
+
+type reviewDao struct {
+	c  gorm.Connection
+	db *gorm_db.DB
+}
+
+func (dao *reviewDao) Create(ctx context.Context, r *questions.Review) error {
+	result := dao.getConnection(ctx).Create(r)
+	err := result.Error()
+	if err != nil {
+		return errors.Wrapf(err, "Can't create review via connection %v", *r)
+	}
+	return nil
+}
+
+func (dao *reviewDao) Find(ctx context.Context, query *questions.Query) (list *[]questions.Review, more bool, err error) {
+	rl := []questions.Review{}
+	exprs, err := reviewWhereExprs(query)
+	if err != nil {
+		return &rl, false, errors.Wrapf(err, "Can't build conds of query %v", *query)
+	}
+
+	order, err := reviewOrderColumns(query)
+	if err != nil {
+		return &rl, false, errors.Wrapf(err, "Can't build order of query %v", *query)
+	}
+
+	c := dao.getConnection(ctx)
+	limit, offset := query.Page.Limit, query.Page.Offset
+
+	if limit > 0 {
+		c = c.Limit(limit + 1)
+	}
+
+	if offset > 0 {
+		c = c.Offset(offset)
+	}
+
+	for _, o := range order {
+		c = c.Order(o)
+	}
+
+	result := c.Find(&rl, conds(exprs)...)
+
+	err = result.Error()
+	if err != nil {
+		return &rl, false, errors.Wrapf(err, "Can't find review via connection by query %v", *query)
+	}
+
+	more = false
+	if limit > 0 && len(rl) >= limit+1 {
+		rl = rl[:limit]
+		more = true
+	}
+
+	return &rl, more, nil
+}
+
+// История повторений, все запросы которой выполняются в транзакции tx
+func newTxReviewDao(tx *gorm_db.DB) *reviewDao {
+	return &reviewDao{db: tx}
+}
+
+func (dao *reviewDao) getDb(ctx context.Context) *gorm_db.DB {
+	if dao.db == nil {
+		container.Make(&dao.db)
+	}
+	return dao.db.WithContext(ctx)
+}
+
+func (dao *reviewDao) getConnection(ctx context.Context) gorm.Connection {
+	if dao.c == nil {
+		return connection.New(dao.getDb(ctx))
+	}
+	return dao.c
+}
@@ -0,0 +1,120 @@
+package gorm
+
+import (
+    "context"
+    "testing"
+
+    gorm "github.com/chudoyoudo/gorm-interface"
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+    "github.com/stretchr/testify/require"
+    "gorm.io/gorm/clause"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+// ----------------
+// ---- Create ----
+// ----------------
+
+func Test_review_dao_create_connection_calls_is_correct(t *testing.T) {
+    rIn := &questions.Review{}
+
+    c := &gorm.ConnectionMock{}
+    c.On("Create", rIn).Return(c)
+    dao := &reviewDao{c: c}
+
+    _ = dao.Create(context.Background(), rIn)
+
+    createCalls := 1
+    if !c.AssertNumberOfCalls(t, "Create", createCalls) {
+        t.Errorf("Метод Create у connection должен вызваться %d раз", createCalls)
+        t.Fail()
+    }
+}
+
+func Test_review_dao_create_when_connection_work_success_result_error_is_empty(t *testing.T) {
+    rIn := &questions.Review{}
+
+    c := &gorm.ConnectionMock{}
+    c.On("Create", rIn).Return(&gorm.ConnectionMock{})
+    dao := &reviewDao{c: c}
+
+    errResult := dao.Create(context.Background(), rIn)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+}
+
+func Test_review_dao_create_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
+    rIn := &questions.Review{}
+    connectionErr := errors.New("Connection mock error")
+
+    c := &gorm.ConnectionMock{}
+    c.On("Create", rIn).Return(&gorm.ConnectionMock{Err: connectionErr})
+    dao := &reviewDao{c: c}
+
+    errResult := dao.Create(context.Background(), rIn)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
+}
+
+// --------------
+// ---- Find ----
+// --------------
+
+func Test_review_dao_find_connection_calls_is_correct(t *testing.T) {
+    query := questions.NewQuery().Eq(questions.FieldQuestionId, uint64(1)).OrderBy(questions.FieldId, true).Paginate(1, 1)
+    questionIdEq := clause.Eq{Column: clause.Column{Name: questions.ReviewQuestionId}, Value: uint64(1)}
+    idOrder := clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: true}
+
+    c := &gorm.ConnectionMock{}
+    c.On("Limit", 2).Return(c)
+    c.On("Offset", 1).Return(c)
+    c.On("Order", idOrder).Return(c)
+    c.On("Find", &[]questions.Review{}, []interface{}{questionIdEq}).Return(c)
+    dao := &reviewDao{c: c}
+
+    _, _, _ = dao.Find(context.Background(), query)
+
+    c.AssertExpectations(t)
+}
+
+func Test_review_dao_find_when_we_have_more_then_limit_records_in_connection_result_more_is_true(t *testing.T) {
+    limit := 2
+
+    c := &gorm.ConnectionMock{}
+    c.On("Limit", limit+1).Return(c)
+    c.On("Find", &[]questions.Review{}, []interface{}{}).Return(&gorm.ConnectionMock{}).Run(func(args mock.Arguments) {
+        rlOut := args.Get(0).(*[]questions.Review)
+        *rlOut = append(*rlOut, questions.Review{ID: 1}, questions.Review{ID: 2}, questions.Review{ID: 3})
+    })
+    dao := &reviewDao{c: c}
+
+    rlResult, resultMore, _ := dao.Find(context.Background(), questions.NewQuery().Paginate(limit, 0))
+
+    assert.Equal(t, true, resultMore, "Возвращаемый more флаг должно быть true")
+    assert.Equal(t, limit, len(*rlResult), "Лишние объекты review, использовавшиеся для вычисления флага more, должны быть убраны из возвращаемого списка объектов")
+}
+
+func Test_review_dao_find_when_field_is_not_review_field_result_error_is_field_not_supported(t *testing.T) {
+    dao := &reviewDao{c: &gorm.ConnectionMock{}}
+
+    _, _, errResult := dao.Find(context.Background(), questions.NewQuery().Eq(questions.FieldGroupId, uint64(1)))
+
+    assert.ErrorIs(t, errResult, questions.ErrFieldNotSupported, "Возвращаемая ошибка должна быть ErrFieldNotSupported")
+}
+
+func Test_review_dao_find_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
+    connectionErr := errors.New("Connection mock error")
+
+    c := &gorm.ConnectionMock{}
+    c.On("Find", &[]questions.Review{}, []interface{}{}).Return(&gorm.ConnectionMock{Err: connectionErr})
+    dao := &reviewDao{c: c}
+
+    _, _, errResult := dao.Find(context.Background(), questions.NewQuery())
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
+}
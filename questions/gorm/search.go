@@ -0,0 +1,44 @@
+package gorm
+
+import (
+	"strings"
+
+	gorm_db "gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Текст вопроса и ответа для полнотекстового поиска Postgres. Конфигурация simple
+// не зависит от языка карточек. Выражение совпадает с выражением индекса, иначе индекс не используется
+const searchVector = "to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(body, ''))"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Метод создает GIN индекс для полнотекстового поиска. Для остальных диалектов
+// поиск идет через LIKE, и индекс не создается
+func Migrate(db *gorm_db.DB) error {
+	if !isPostgres(db) {
+		return nil
+	}
+	return db.Exec("CREATE INDEX IF NOT EXISTS idx_questions_search ON questions USING GIN (" + searchVector + ")").Error
+}
+
+// Postgres ищет по словоформам и сортирует по релевантности.
+// Остальные диалекты ищут каждое слово запроса как подстроку и сортируют по id
+func searchScope(db *gorm_db.DB, query string) *gorm_db.DB {
+	if isPostgres(db) {
+		tsQuery := "plainto_tsquery('simple', ?)"
+		return db.Where(searchVector+" @@ "+tsQuery, query).Clauses(clause.OrderBy{
+			Expression: clause.Expr{SQL: "ts_rank(" + searchVector + ", " + tsQuery + ") DESC, id DESC", Vars: []interface{}{query}},
+		})
+	}
+
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		pattern := "%" + likeEscaper.Replace(word) + "%"
+		db = db.Where(`(lower(title) LIKE ? ESCAPE '\' OR lower(body) LIKE ? ESCAPE '\')`, pattern, pattern)
+	}
+	return db.Order("id DESC")
+}
+
+func isPostgres(db *gorm_db.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
@@ -0,0 +1,99 @@
+package gorm
+
+import (
+    "context"
+    "database/sql"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "gorm.io/driver/postgres"
+    gorm_db "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+func Test_search_scope_for_postgres_use_full_text_search_and_rank(t *testing.T) {
+    conn, err := sql.Open("sqlite3", ":memory:")
+    require.Nil(t, err)
+    t.Cleanup(func() { _ = conn.Close() })
+    // Запрос только строится, поэтому соединение Postgres не нужно
+    db, err := gorm_db.Open(postgres.New(postgres.Config{Conn: conn}), &gorm_db.Config{DryRun: true, Logger: logger.Discard})
+    require.Nil(t, err, "Не удалось открыть тестовую базу")
+
+    stmt := searchScope(db.Model(&questions.Question{}), "present perfect").Find(&[]questions.Question{}).Statement
+
+    assert.Contains(t, stmt.SQL.String(), searchVector+" @@ plainto_tsquery('simple', $1)", "Поиск должен идти по tsvector вопроса и ответа")
+    assert.Contains(t, stmt.SQL.String(), "ORDER BY ts_rank(", "Результаты должны сортироваться по релевантности")
+    assert.Equal(t, []interface{}{"present perfect", "present perfect"}, stmt.Vars, "Запрос должен передаваться параметром")
+}
+
+func Test_dao_search_when_dialect_is_not_postgres_find_all_words_in_title_or_body(t *testing.T) {
+    db := getTestDb(t)
+    db.Create(&[]questions.Question{
+        {UserId: 1, Title: "Present Perfect", Body: "have + V3"},
+        {UserId: 1, Title: "Past simple", Body: "V2"},
+        {UserId: 1, Title: "Perfect", Body: "Present of have"},
+        {UserId: 2, Title: "Present perfect", Body: "have + V3"},
+    })
+    dao := &dao{db: db}
+
+    list, more, errResult := dao.Search(context.Background(), "present PERFECT", questions.NewQuery().Eq(questions.FieldUserId, uint64(1)))
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.False(t, more, "Флаг more должен быть false")
+    require.Len(t, *list, 2, "Должны найтись вопросы пользователя со всеми словами запроса")
+    assert.Equal(t, "Perfect", (*list)[0].Title, "Найденные вопросы должны идти от новых к старым")
+    assert.Equal(t, "Present Perfect", (*list)[1].Title, "Найденные вопросы должны идти от новых к старым")
+}
+
+func Test_dao_search_when_dialect_is_not_postgres_like_wildcards_are_escaped(t *testing.T) {
+    db := getTestDb(t)
+    db.Create(&[]questions.Question{
+        {UserId: 1, Title: "100% sure"},
+        {UserId: 1, Title: "1000 sure"},
+    })
+    dao := &dao{db: db}
+
+    list, _, errResult := dao.Search(context.Background(), "0%", questions.NewQuery())
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *list, 1, "Символ % в запросе должен искаться как есть")
+    assert.Equal(t, "100% sure", (*list)[0].Title, "Найден неверный вопрос")
+}
+
+func Test_dao_search_return_more_when_limit_is_exceeded(t *testing.T) {
+    db := getTestDb(t)
+    db.Create(&[]questions.Question{
+        {UserId: 1, Title: "word 1"},
+        {UserId: 1, Title: "word 2"},
+        {UserId: 1, Title: "word 3"},
+    })
+    dao := &dao{db: db}
+
+    list, more, errResult := dao.Search(context.Background(), "word", questions.NewQuery().Paginate(1, 1))
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.True(t, more, "Флаг more должен быть true")
+    require.Len(t, *list, 1, "Количество вопросов должно быть ограничено limit")
+    assert.Equal(t, "word 2", (*list)[0].Title, "Вопросы должны пропускаться на offset")
+}
+
+func Test_dao_search_when_db_work_wrong_result_error_not_empty(t *testing.T) {
+    db := getTestDb(t)
+    require.Nil(t, db.Migrator().DropTable(&questions.Question{}))
+    dao := &dao{db: db}
+
+    _, _, errResult := dao.Search(context.Background(), "word", questions.NewQuery())
+
+    assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
+
+func Test_migrate_when_dialect_is_not_postgres_result_error_is_empty(t *testing.T) {
+    db := getTestDb(t)
+
+    errResult := Migrate(db)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+}
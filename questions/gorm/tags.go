@@ -0,0 +1,119 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm/clause"
+
+	"github.com/chudoyoudo/remember-cards/questions"
+)
+
+// Строка связи вопроса с меткой вместе с самой меткой
+type questionTag struct {
+	QuestionId    uint64
+	questions.Tag `gorm:"embedded"`
+}
+
+// Метод строит условие на метки подзапросом, поэтому id отобранных вопросов не передаются
+// параметрами запроса. Метки связаны только с вопросами своего пользователя,
+// и пользователя ограничивает условие на вопросы
+func taggedExpr(p questions.Predicate) (clause.Expression, error) {
+	names, ok := p.Value.([]string)
+	if !ok {
+		return nil, errors.Errorf("Tags value %v is not a list of names", p.Value)
+	}
+
+	sql := "? IN (SELECT question_tags.question_id FROM question_tags" +
+		" JOIN tags ON tags.id = question_tags.tag_id WHERE tags.name IN ?"
+	vars := []interface{}{clause.Column{Name: queryColumns[questions.FieldId]}, names}
+	switch p.Op {
+	case questions.OpAny:
+		sql += ")"
+	case questions.OpAll:
+		sql += " GROUP BY question_tags.question_id HAVING count(DISTINCT tags.id) = ?)"
+		vars = append(vars, len(questions.NewTags(names)))
+	default:
+		return nil, errors.Errorf("Operation %q is not supported for tags", p.Op)
+	}
+	return clause.Expr{SQL: sql, Vars: vars}, nil
+}
+
+// Метод заполняет id меток пользователя по именам и создает недостающие метки
+func (dao *dao) ensureTags(ctx context.Context, userId uint64, tags []questions.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	db := dao.getDb(ctx)
+	names := questions.TagNames(tags)
+	missing := make([]questions.Tag, 0, len(tags))
+	for _, t := range tags {
+		missing = append(missing, questions.Tag{UserId: userId, Name: t.Name})
+	}
+	// Метки, которые уже есть, в том числе созданные параллельным запросом, пропускаются
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&missing)
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "Can't create tags %v via db", names)
+	}
+
+	existing := []questions.Tag{}
+	result = db.Where(map[string]interface{}{questions.QuestionUserId: userId, "name": names}).Find(&existing)
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "Can't find tags %v via db", names)
+	}
+
+	byName := map[string]questions.Tag{}
+	for _, t := range existing {
+		byName[t.Name] = t
+	}
+	for i := range tags {
+		tags[i] = byName[tags[i].Name]
+	}
+	return nil
+}
+
+func (dao *dao) replaceTags(ctx context.Context, q *questions.Question) error {
+	err := dao.ensureTags(ctx, q.UserId, q.Tags)
+	if err != nil {
+		return err
+	}
+
+	err = dao.getDb(ctx).Model(q).Association("Tags").Replace(q.Tags)
+	if err != nil {
+		return errors.Wrapf(err, "Can't replace tags of question %d via db", q.ID)
+	}
+	return nil
+}
+
+// Метод загружает метки найденных вопросов одним запросом
+func (dao *dao) loadTags(ctx context.Context, ql []questions.Question) error {
+	if len(ql) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, 0, len(ql))
+	for _, q := range ql {
+		ids = append(ids, q.ID)
+	}
+
+	rows := []questionTag{}
+	result := dao.getDb(ctx).Table("tags").
+		Select("question_tags.question_id, tags.*").
+		Joins("JOIN question_tags ON question_tags.tag_id = tags.id").
+		Where("question_tags.question_id IN ?", ids).
+		Order("tags.name").
+		Scan(&rows)
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "Can't load tags of questions %v via db", ids)
+	}
+
+	byQuestion := map[uint64][]questions.Tag{}
+	for _, r := range rows {
+		byQuestion[r.QuestionId] = append(byQuestion[r.QuestionId], r.Tag)
+	}
+	for i := range ql {
+		ql[i].Tags = byQuestion[ql[i].ID]
+	}
+	return nil
+}
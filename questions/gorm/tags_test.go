@@ -0,0 +1,90 @@
+package gorm
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+func getTagTestDao(t *testing.T) *dao {
+    db := getTestDb(t)
+    return &dao{db: db}
+}
+
+func Test_dao_create_save_tags_and_reuse_existing_tags_of_user(t *testing.T) {
+    dao := getTagTestDao(t)
+    q1 := &questions.Question{UserId: 1, Tags: questions.NewTags([]string{"weak", "exam"})}
+    q2 := &questions.Question{UserId: 1, Tags: questions.NewTags([]string{"weak"})}
+    q3 := &questions.Question{UserId: 2, Tags: questions.NewTags([]string{"weak"})}
+
+    require.Nil(t, dao.Create(context.Background(), q1), "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, dao.Create(context.Background(), q2), "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, dao.Create(context.Background(), q3), "Возвращаемая ошибка должна быть пустой")
+
+    var tags int64
+    require.Nil(t, dao.db.Model(&questions.Tag{}).Count(&tags).Error)
+    assert.Equal(t, int64(3), tags, "Метка должна создаваться один раз для каждого пользователя")
+    assert.Equal(t, q1.Tags[0].ID, q2.Tags[0].ID, "Существующая метка пользователя должна переиспользоваться")
+    assert.NotEqual(t, q1.Tags[0].ID, q3.Tags[0].ID, "У разных пользователей должны быть разные метки")
+}
+
+func Test_dao_find_load_tags_of_questions(t *testing.T) {
+    dao := getTagTestDao(t)
+    require.Nil(t, dao.Create(context.Background(), &questions.Question{UserId: 1, Tags: questions.NewTags([]string{"weak", "exam"})}))
+    require.Nil(t, dao.Create(context.Background(), &questions.Question{UserId: 1}))
+
+    list, _, errResult := dao.Find(context.Background(), questions.NewQuery().OrderBy(questions.FieldId, false))
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *list, 2)
+    assert.Equal(t, []string{"exam", "weak"}, questions.TagNames((*list)[0].Tags), "Метки вопроса должны загружаться по имени")
+    assert.Empty(t, (*list)[1].Tags, "У вопроса без меток не должно быть меток")
+}
+
+func Test_dao_update_when_tags_in_fields_replace_tags_of_question(t *testing.T) {
+    dao := getTagTestDao(t)
+    q := &questions.Question{UserId: 1, Title: "Question", Tags: questions.NewTags([]string{"weak", "exam"})}
+    require.Nil(t, dao.Create(context.Background(), q))
+
+    q.Tags = questions.NewTags([]string{"exam", "grammar"})
+    errResult := dao.Update(context.Background(), q, []string{"title", questions.QuestionTags})
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    list, _, err := dao.Find(context.Background(), questions.NewQuery().Eq(questions.FieldId, q.ID))
+    require.Nil(t, err)
+    assert.Equal(t, []string{"exam", "grammar"}, questions.TagNames((*list)[0].Tags), "Метки вопроса должны замениться")
+}
+
+func Test_dao_find_with_tags_return_questions_with_any_or_all_tags(t *testing.T) {
+    dao := getTagTestDao(t)
+    qBoth := &questions.Question{UserId: 1, Tags: questions.NewTags([]string{"weak", "exam"})}
+    qWeak := &questions.Question{UserId: 1, Tags: questions.NewTags([]string{"weak"})}
+    qForeign := &questions.Question{UserId: 2, Tags: questions.NewTags([]string{"weak", "exam"})}
+    require.Nil(t, dao.Create(context.Background(), qBoth))
+    require.Nil(t, dao.Create(context.Background(), qWeak))
+    require.Nil(t, dao.Create(context.Background(), qForeign))
+
+    userQuery := func() *questions.Query {
+        return questions.NewQuery().Eq(questions.FieldUserId, uint64(1))
+    }
+    anyResult, _, errAny := dao.Find(context.Background(), userQuery().Tagged([]string{"weak", "exam"}, false))
+    allResult, _, errAll := dao.Find(context.Background(), userQuery().Tagged([]string{"weak", "exam"}, true))
+
+    require.Nil(t, errAny, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errAll, "Возвращаемая ошибка должна быть пустой")
+    assert.ElementsMatch(t, []uint64{qBoth.ID, qWeak.ID}, questionIds(anyResult), "Должны вернуться вопросы пользователя с любой из меток")
+    assert.Equal(t, []uint64{qBoth.ID}, questionIds(allResult), "Должны вернуться вопросы пользователя со всеми метками")
+}
+
+func Test_dao_find_with_tags_when_db_work_wrong_result_error_not_empty(t *testing.T) {
+    dao := getTagTestDao(t)
+    require.Nil(t, dao.db.Migrator().DropTable(&questions.Tag{}))
+
+    _, _, errResult := dao.Find(context.Background(), questions.NewQuery().Tagged([]string{"weak"}, false))
+
+    assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
@@ -0,0 +1,113 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	gorm_db "gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/chudoyoudo/remember-cards/questions"
+)
+
+var deletedAt = clause.Column{Name: "deletedAt"}
+
+// Вопросы в корзине не видны через gorm.Connection, поэтому запросы к корзине
+// идут через db без мягкого удаления
+func (dao *dao) FindDeleted(ctx context.Context, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+	ql := []questions.Question{}
+	exprs, err := whereExprs(query)
+	if err != nil {
+		return &ql, false, errors.Wrapf(err, "Can't build conds of query %v", *query)
+	}
+
+	order, err := orderColumns(query)
+	if err != nil {
+		return &ql, false, errors.Wrapf(err, "Can't build order of query %v", *query)
+	}
+
+	exprs = append(exprs, clause.Neq{Column: deletedAt, Value: nil})
+	db := dao.getDb(ctx).Unscoped().Model(&questions.Question{}).Clauses(clause.Where{Exprs: exprs})
+	for _, o := range order {
+		db = db.Order(o)
+	}
+
+	limit, offset := query.Page.Limit, query.Page.Offset
+	if limit > 0 {
+		db = db.Limit(limit + 1)
+	}
+
+	if offset > 0 {
+		db = db.Offset(offset)
+	}
+
+	result := db.Find(&ql)
+	if result.Error != nil {
+		return &ql, false, errors.Wrapf(result.Error, "Can't find deleted questions via db by query %v", *query)
+	}
+
+	if limit > 0 && len(ql) >= limit+1 {
+		ql = ql[:limit]
+		more = true
+	}
+
+	err = dao.loadTags(ctx, ql)
+	if err != nil {
+		return &ql, false, errors.Wrap(err, "Can't load tags of deleted questions")
+	}
+
+	return &ql, more, nil
+}
+
+func (dao *dao) Restore(ctx context.Context, q *questions.Question) error {
+	version := clause.Column{Name: "version"}
+	result := dao.getDb(ctx).Unscoped().Model(&questions.Question{}).
+		Where(clause.Eq{Column: clause.PrimaryColumn, Value: q.ID}).
+		Where(clause.Eq{Column: version, Value: q.Version}).
+		Where(clause.Neq{Column: deletedAt, Value: nil}).
+		Updates(map[string]interface{}{
+			"deletedAt": nil,
+			"version":   gorm_db.Expr("? + 1", version),
+		})
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "Can't restore question with id %d via db", q.ID)
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrapf(questions.ErrVersionConflict, "Deleted question with id %d has no version %d", q.ID, q.Version)
+	}
+
+	q.Version++
+	q.DeletedAt = gorm_db.DeletedAt{}
+	return nil
+}
+
+func (dao *dao) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var count int64
+	err := dao.getDb(ctx).Transaction(func(tx *gorm_db.DB) error {
+		expired := tx.Unscoped().Model(&questions.Question{}).
+			Select("id").
+			Where(clause.Lt{Column: deletedAt, Value: deletedBefore})
+
+		result := tx.Exec("DELETE FROM question_tags WHERE question_id IN (?)", expired)
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "Can't delete tags of expired questions")
+		}
+
+		result = tx.Where("? IN (?)", clause.Column{Name: questions.ReviewQuestionId}, expired).Delete(&questions.Review{})
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "Can't delete reviews of expired questions")
+		}
+
+		result = tx.Unscoped().Where(clause.Lt{Column: deletedAt, Value: deletedBefore}).Delete(&questions.Question{})
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "Can't delete expired questions")
+		}
+		count = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "Can't purge questions deleted before %v via db", deletedBefore)
+	}
+	return count, nil
+}
@@ -0,0 +1,139 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gorm_db "gorm.io/gorm"
+
+	"github.com/chudoyoudo/remember-cards/questions"
+)
+
+func Test_dao_delete_move_question_to_trash(t *testing.T) {
+	db := getTestDb(t)
+	require.Nil(t, db.Create(&[]questions.Question{{UserId: 1}, {UserId: 1}}).Error)
+	dao := &dao{db: db}
+
+	require.Nil(t, dao.Delete(context.Background(), questions.NewQuery().Eq(questions.FieldId, uint64(1))))
+
+	found, _, err := dao.Find(context.Background(), questions.NewQuery())
+	require.Nil(t, err)
+	deleted, _, err := dao.FindDeleted(context.Background(), questions.NewQuery())
+	require.Nil(t, err)
+	assert.Equal(t, []uint64{2}, questionIds(found), "Удаленный вопрос не должен находиться")
+	assert.Equal(t, []uint64{1}, questionIds(deleted), "Удаленный вопрос должен быть в корзине")
+	assert.True(t, (*deleted)[0].DeletedAt.Valid, "У вопроса в корзине должно быть время удаления")
+}
+
+func Test_dao_delete_when_version_differs_result_error_is_version_conflict(t *testing.T) {
+	db := getTestDb(t)
+	q := &questions.Question{UserId: 1, Version: 2}
+	require.Nil(t, db.Create(q).Error)
+	dao := &dao{db: db}
+
+	errVersion := dao.Delete(context.Background(), questions.NewQuery().Eq(questions.FieldId, q.ID).Eq(questions.FieldVersion, uint64(1)))
+	errNoVersion := dao.Delete(context.Background(), questions.NewQuery().Eq(questions.FieldId, q.ID+1))
+
+	found, _, err := dao.Find(context.Background(), questions.NewQuery())
+	require.Nil(t, err)
+	assert.ErrorIs(t, errVersion, questions.ErrVersionConflict, "Возвращаемая ошибка должна быть ErrVersionConflict")
+	assert.Nil(t, errNoVersion, "Без проверки версии отсутствие вопросов не должно быть ошибкой")
+	assert.Equal(t, []uint64{q.ID}, questionIds(found), "Вопрос другой версии не должен удаляться")
+}
+
+func Test_dao_update_when_question_in_trash_result_error_is_version_conflict(t *testing.T) {
+	db := getTestDb(t)
+	qIn := &questions.Question{UserId: 1, Title: "Test 1", Version: 1}
+	require.Nil(t, db.Create(qIn).Error)
+	require.Nil(t, db.Delete(qIn).Error)
+	dao := &dao{db: db}
+
+	qIn.Title = "Test 2"
+	errResult := dao.Update(context.Background(), qIn, []string{questions.QuestionTitle})
+
+	require.ErrorIs(t, errResult, questions.ErrVersionConflict, "Вопрос в корзине не должен изменяться")
+}
+
+func Test_dao_restore_return_question_from_trash_and_increment_version(t *testing.T) {
+	db := getTestDb(t)
+	qIn := &questions.Question{UserId: 1, Version: 1}
+	require.Nil(t, db.Create(qIn).Error)
+	require.Nil(t, db.Delete(qIn).Error)
+	dao := &dao{db: db}
+
+	errResult := dao.Restore(context.Background(), qIn)
+
+	found, _, err := dao.Find(context.Background(), questions.NewQuery())
+	require.Nil(t, err)
+	require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+	require.Equal(t, []uint64{qIn.ID}, questionIds(found), "Восстановленный вопрос должен находиться")
+	assert.Equal(t, uint64(2), (*found)[0].Version, "Версия вопроса в базе должна увеличиться")
+	assert.Equal(t, uint64(2), qIn.Version, "Версия вопроса должна совпадать с версией в базе")
+	assert.False(t, qIn.DeletedAt.Valid, "У восстановленного вопроса не должно быть времени удаления")
+}
+
+func Test_dao_restore_when_version_is_stale_result_error_is_version_conflict(t *testing.T) {
+	db := getTestDb(t)
+	qIn := &questions.Question{UserId: 1, Version: 2}
+	require.Nil(t, db.Create(qIn).Error)
+	require.Nil(t, db.Delete(qIn).Error)
+	dao := &dao{db: db}
+
+	qIn.Version = 1
+	errResult := dao.Restore(context.Background(), qIn)
+
+	require.ErrorIs(t, errResult, questions.ErrVersionConflict, "Возвращаемая ошибка должна быть ErrVersionConflict")
+}
+
+func Test_dao_restore_when_question_not_in_trash_result_error_is_version_conflict(t *testing.T) {
+	db := getTestDb(t)
+	qIn := &questions.Question{UserId: 1, Version: 1}
+	require.Nil(t, db.Create(qIn).Error)
+	dao := &dao{db: db}
+
+	errResult := dao.Restore(context.Background(), qIn)
+
+	require.ErrorIs(t, errResult, questions.ErrVersionConflict, "Вопрос не из корзины не должен восстанавливаться")
+	assert.Equal(t, uint64(1), qIn.Version, "Версия вопроса не должна меняться")
+}
+
+func Test_dao_purge_delete_only_expired_questions_with_reviews_and_tags(t *testing.T) {
+	now := time.Now().UTC()
+	db := getTestDb(t)
+	expired := &questions.Question{UserId: 1, Tags: []questions.Tag{{UserId: 1, Name: "go"}}}
+	recent := &questions.Question{UserId: 1}
+	active := &questions.Question{UserId: 1}
+	require.Nil(t, db.Create(expired).Error)
+	require.Nil(t, db.Create(recent).Error)
+	require.Nil(t, db.Create(active).Error)
+	require.Nil(t, db.Create(&questions.Review{QuestionId: expired.ID}).Error)
+	require.Nil(t, db.Create(&questions.Review{QuestionId: active.ID}).Error)
+	db.Model(expired).Update("deletedAt", gorm_db.DeletedAt{Time: now.Add(-time.Hour * 48), Valid: true})
+	db.Model(recent).Update("deletedAt", gorm_db.DeletedAt{Time: now.Add(-time.Hour), Valid: true})
+	dao := &dao{db: db}
+
+	count, errResult := dao.Purge(context.Background(), now.Add(-time.Hour * 24))
+
+	var questionCount, reviewCount, tagLinkCount int64
+	db.Unscoped().Model(&questions.Question{}).Count(&questionCount)
+	db.Model(&questions.Review{}).Count(&reviewCount)
+	db.Table("question_tags").Count(&tagLinkCount)
+	require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+	assert.Equal(t, int64(1), count, "Должен удалиться только просроченный вопрос")
+	assert.Equal(t, int64(2), questionCount, "Недавно удаленный и активный вопросы должны остаться")
+	assert.Equal(t, int64(1), reviewCount, "История повторений удаленного вопроса должна удалиться")
+	assert.Equal(t, int64(0), tagLinkCount, "Метки удаленного вопроса должны отвязаться")
+}
+
+func Test_dao_purge_when_db_work_wrong_result_error_not_empty(t *testing.T) {
+	db := getTestDb(t)
+	require.Nil(t, db.Migrator().DropTable(&questions.Review{}))
+	dao := &dao{db: db}
+
+	_, errResult := dao.Purge(context.Background(), time.Now())
+
+	assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
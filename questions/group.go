@@ -0,0 +1,42 @@
+package questions
+
+import (
+    "context"
+
+    "github.com/pkg/errors"
+)
+
+var ErrGroupNotFound = errors.New("Group not found")
+
+// Проверка групп, в которые добавляются вопросы.
+// Реализация регистрируется в контейнере пакетом groups
+type Groups interface {
+    // Метод проверяет, что группа существует и принадлежит пользователю
+    IsOwned(ctx context.Context, groupId, userId uint64) (bool, error)
+    // Метод возвращает переданные группы вместе со всеми вложенными группами пользователя
+    Descendants(ctx context.Context, groupIds []uint64, userId uint64) ([]uint64, error)
+    // Метод возвращает полные имена групп пользователя: имена родительских групп
+    // и самой группы через "::", как у вложенных колод Anki
+    Names(ctx context.Context, groupIds []uint64, userId uint64) (map[uint64]string, error)
+    // Метод находит группы пользователя по полным именам через "::" и создает
+    // недостающие группы вместе с родительскими. Возвращает id группы по имени
+    // и id созданных групп
+    Ensure(ctx context.Context, names []string, userId uint64) (ids map[string]uint64, created []uint64, err error)
+    // Метод окончательно удаляет группы пользователя, например созданные для неудавшегося импорта
+    Remove(ctx context.Context, groupIds []uint64, userId uint64) error
+    // Метод возвращает имя алгоритма повторений группы пользователя.
+    // Пустое имя означает алгоритм по умолчанию
+    Scheduler(ctx context.Context, groupId, userId uint64) (string, error)
+    // Метод проверяет, что группа пользователя есть среди действующих или в корзине
+    Exists(ctx context.Context, groupId, userId uint64) (bool, error)
+    // Метод возвращает из корзины удаленную группу пользователя вместе с родительскими группами.
+    // Возвращает false, если группы нет ни среди действующих, ни в корзине
+    Restore(ctx context.Context, groupId, userId uint64) (bool, error)
+}
+
+// Количество вопросов в группе
+type GroupCount struct {
+    GroupId uint64 `json:"groupId"`
+    Total   int64  `json:"total"`
+    Due     int64  `json:"due"`
+}
@@ -5,6 +5,10 @@ import (
 )
 
 func init() {
+    container.Singleton(func() SchedulerResolver {
+        return &schedulerResolver{def: &ladderScheduler{}}
+    })
+
     container.Transient(func() Usecase {
         return &usecase{}
     })
@@ -0,0 +1,54 @@
+package questions
+
+import "time"
+
+// Последний шаг в лестнице интервалов повторения
+const maxStep = 4
+
+// Алгоритм с фиксированной лестницей интервалов: 30 минут, 14, 60 и 90 дней
+type ladderScheduler struct{}
+
+func (s *ladderScheduler) Init(q *Question, now time.Time) {
+    q.Step = 1
+    q.RepeatTime = s.getRepeatTime(q.Step, now)
+    q.IsFailed = false
+}
+
+// Метод сбрасывает карточку на первый шаг с пометкой IsFailed при оценке again,
+// оставляет на текущем шаге при hard, продвигает на шаг при good и на два при easy
+func (s *ladderScheduler) Answer(q *Question, grade Grade, now time.Time) {
+    switch grade {
+    case GradeAgain:
+        q.Step = 1
+        q.IsFailed = true
+    case GradeHard:
+        q.IsFailed = false
+    case GradeEasy:
+        q.Step = s.promote(q.Step, 2)
+        q.IsFailed = false
+    default:
+        q.Step = s.promote(q.Step, 1)
+        q.IsFailed = false
+    }
+    q.RepeatTime = s.getRepeatTime(q.Step, now)
+}
+
+func (s *ladderScheduler) promote(step uint8, steps uint8) uint8 {
+    if step+steps > maxStep {
+        return maxStep
+    }
+    return step + steps
+}
+
+func (s *ladderScheduler) getRepeatTime(step uint8, now time.Time) time.Time {
+    switch step {
+    case 1:
+        return now.Add(time.Minute * 30)
+    case 2:
+        return now.Add(time.Hour * 24 * 14)
+    case 3:
+        return now.Add(time.Hour * 24 * 60)
+    default:
+        return now.Add(time.Hour * 24 * 90)
+    }
+}
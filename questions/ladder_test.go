@@ -0,0 +1,69 @@
+package questions
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_ladder_init_set_first_step(t *testing.T) {
+    now := time.Now()
+    q := &Question{Step: 3, IsFailed: true}
+
+    (&ladderScheduler{}).Init(q, now)
+
+    assert.Equal(t, uint8(1), q.Step, "Step должен быть 1")
+    assert.Equal(t, false, q.IsFailed, "Флаг IsFailed должен быть false")
+    assert.Equal(t, now.Add(time.Minute*30), q.RepeatTime, "RepeatTime должно быть +30 минут от текущего времени")
+}
+
+func Test_ladder_answer_repeat_time_follows_ladder(t *testing.T) {
+    now := time.Now()
+    expected := map[uint8]time.Duration{
+        1: time.Hour * 24 * 14,
+        2: time.Hour * 24 * 60,
+        3: time.Hour * 24 * 90,
+        4: time.Hour * 24 * 90,
+    }
+
+    for step, interval := range expected {
+        q := &Question{Step: step}
+        (&ladderScheduler{}).Answer(q, GradeGood, now)
+        assert.Equal(t, now.Add(interval), q.RepeatTime, "RepeatTime после шага %d неверное", step)
+    }
+}
+
+func Test_ladder_answer_when_again_reset_to_first_step(t *testing.T) {
+    now := time.Now()
+    q := &Question{Step: 4}
+
+    (&ladderScheduler{}).Answer(q, GradeAgain, now)
+
+    assert.Equal(t, uint8(1), q.Step, "Step должен быть 1")
+    assert.Equal(t, true, q.IsFailed, "Флаг IsFailed должен быть true")
+    assert.Equal(t, now.Add(time.Minute*30), q.RepeatTime, "RepeatTime должно быть +30 минут от текущего времени")
+}
+
+func Test_ladder_answer_when_hard_question_stays_on_current_step(t *testing.T) {
+    now := time.Now()
+    q := &Question{Step: 2, IsFailed: true}
+
+    (&ladderScheduler{}).Answer(q, GradeHard, now)
+
+    assert.Equal(t, uint8(2), q.Step, "Step не должен меняться")
+    assert.Equal(t, false, q.IsFailed, "Флаг IsFailed должен быть false")
+    assert.Equal(t, now.Add(time.Hour*24*14), q.RepeatTime, "RepeatTime должно быть +14 дней от текущего времени")
+}
+
+func Test_ladder_answer_when_easy_question_skips_step(t *testing.T) {
+    now := time.Now()
+    q := &Question{Step: 1}
+    qLast := &Question{Step: 3}
+
+    (&ladderScheduler{}).Answer(q, GradeEasy, now)
+    (&ladderScheduler{}).Answer(qLast, GradeEasy, now)
+
+    assert.Equal(t, uint8(3), q.Step, "Step должен увеличиться на 2")
+    assert.Equal(t, uint8(maxStep), qLast.Step, "Step не должен превышать последний шаг")
+}
@@ -0,0 +1,258 @@
+package memory
+
+import (
+    "context"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/pkg/errors"
+    "gorm.io/gorm"
+
+    "github.com/chudoyoudo/remember-cards/memory"
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+type dao struct {
+    s *store
+    // Dao транзакции работает под блокировкой, которую уже держит WithTx
+    tx bool
+}
+
+func (dao *dao) Create(ctx context.Context, q *questions.Question) error {
+    defer dao.lock()()
+
+    dao.s.lastQuestion++
+    q.ID = dao.s.lastQuestion
+    q.Version = 1
+    stored := *q
+    stored.Tags = dao.s.ensureTags(q.UserId, q.Tags)
+    dao.s.questions[q.ID] = stored
+    return nil
+}
+
+// Вопрос обновляется, только если он не в корзине и его версия совпадает с q.Version,
+// иначе возвращается ErrVersionConflict. Без полей обновляются все поля ToMap
+func (dao *dao) Update(ctx context.Context, q *questions.Question, fields []string) error {
+    defer dao.lock()()
+
+    stored, found := dao.s.questions[q.ID]
+    if !found || stored.DeletedAt.Valid || stored.Version != q.Version {
+        return errors.Wrapf(questions.ErrVersionConflict, "Question with id %d has no version %d", q.ID, q.Version)
+    }
+
+    err := memory.Assign(&stored, *q.ToMap(fields))
+    if err != nil {
+        return errors.Wrapf(err, "Can't update question with id %d", q.ID)
+    }
+
+    for _, field := range fields {
+        if field == questions.QuestionTags {
+            stored.Tags = dao.s.ensureTags(q.UserId, q.Tags)
+        }
+    }
+
+    stored.Version++
+    dao.s.questions[q.ID] = stored
+    q.Version = stored.Version
+    return nil
+}
+
+func (dao *dao) Delete(ctx context.Context, query *questions.Query) error {
+    defer dao.lock()()
+
+    ql, err := filter(dao.active(), query)
+    if err != nil {
+        return errors.Wrapf(err, "Can't filter questions by query %v", *query)
+    }
+    if len(ql) == 0 && query.Has(questions.FieldVersion) {
+        return errors.Wrapf(questions.ErrVersionConflict, "No question with version by query %v", *query)
+    }
+
+    deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+    for _, q := range ql {
+        q.DeletedAt = deletedAt
+        dao.s.questions[q.ID] = q
+    }
+    return nil
+}
+
+func (dao *dao) Find(ctx context.Context, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    defer dao.lock()()
+
+    return dao.find(dao.active(), query)
+}
+
+func (dao *dao) FindDeleted(ctx context.Context, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    defer dao.lock()()
+
+    deleted := []questions.Question{}
+    for _, q := range dao.s.sortedQuestions() {
+        if q.DeletedAt.Valid {
+            deleted = append(deleted, q)
+        }
+    }
+    return dao.find(deleted, query)
+}
+
+func (dao *dao) Restore(ctx context.Context, q *questions.Question) error {
+    defer dao.lock()()
+
+    stored, found := dao.s.questions[q.ID]
+    if !found || !stored.DeletedAt.Valid || stored.Version != q.Version {
+        return errors.Wrapf(questions.ErrVersionConflict, "Deleted question with id %d has no version %d", q.ID, q.Version)
+    }
+
+    stored.DeletedAt = gorm.DeletedAt{}
+    stored.Version++
+    dao.s.questions[q.ID] = stored
+
+    q.Version = stored.Version
+    q.DeletedAt = gorm.DeletedAt{}
+    return nil
+}
+
+func (dao *dao) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+    defer dao.lock()()
+
+    expired := map[uint64]bool{}
+    for id, q := range dao.s.questions {
+        if q.DeletedAt.Valid && q.DeletedAt.Time.Before(deletedBefore) {
+            expired[id] = true
+            delete(dao.s.questions, id)
+        }
+    }
+
+    reviews := []questions.Review{}
+    for _, r := range dao.s.reviews {
+        if !expired[r.QuestionId] {
+            reviews = append(reviews, r)
+        }
+    }
+    dao.s.reviews = reviews
+    return int64(len(expired)), nil
+}
+
+// Каждое слово text ищется как подстрока вопроса или ответа без учета регистра,
+// как в хранилище gorm без Postgres. Найденные вопросы сортируются по убыванию id
+func (dao *dao) Search(ctx context.Context, text string, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    defer dao.lock()()
+
+    words := strings.Fields(strings.ToLower(text))
+    found := []questions.Question{}
+    for _, q := range dao.active() {
+        title, body := strings.ToLower(q.Title), strings.ToLower(q.Body)
+        matched := true
+        for _, word := range words {
+            if !strings.Contains(title, word) && !strings.Contains(body, word) {
+                matched = false
+                break
+            }
+        }
+        if matched {
+            found = append(found, q)
+        }
+    }
+
+    search := query.Clone()
+    search.Sort = []questions.Sort{{Field: questions.FieldId, Desc: true}}
+    return dao.find(found, search)
+}
+
+func (dao *dao) CountByGroup(ctx context.Context, query *questions.Query, dueBefore time.Time) (*[]questions.GroupCount, error) {
+    defer dao.lock()()
+
+    counts := []questions.GroupCount{}
+    ql, err := filter(dao.active(), query)
+    if err != nil {
+        return &counts, errors.Wrapf(err, "Can't filter questions by query %v", *query)
+    }
+
+    byGroup := map[uint64]*questions.GroupCount{}
+    for _, q := range ql {
+        c, found := byGroup[q.GroupId]
+        if !found {
+            c = &questions.GroupCount{GroupId: q.GroupId}
+            byGroup[q.GroupId] = c
+        }
+        c.Total++
+        if !q.RepeatTime.After(dueBefore) {
+            c.Due++
+        }
+    }
+
+    for _, c := range byGroup {
+        counts = append(counts, *c)
+    }
+    sort.Slice(counts, func(i, j int) bool {
+        return counts[i].GroupId < counts[j].GroupId
+    })
+    return &counts, nil
+}
+
+// Транзакции выполняются по одной: WithTx держит блокировку хранилища, пока работает fc,
+// и при ошибке fc восстанавливает данные, которые были до ее вызова
+func (dao *dao) WithTx(ctx context.Context, fc func(dao questions.Dao, reviewDao questions.ReviewDao) error) error {
+    if dao.tx {
+        return fc(dao, newTxReviewDao(dao.s))
+    }
+
+    dao.s.mu.Lock()
+    defer dao.s.mu.Unlock()
+
+    saved := dao.s.snapshot()
+    err := fc(newTxDao(dao.s), newTxReviewDao(dao.s))
+    if err != nil {
+        dao.s.restore(saved)
+    }
+    return err
+}
+
+func (dao *dao) find(ql []questions.Question, query *questions.Query) (list *[]questions.Question, more bool, err error) {
+    found, err := filter(ql, query)
+    if err != nil {
+        empty := []questions.Question{}
+        return &empty, false, errors.Wrapf(err, "Can't filter questions by query %v", *query)
+    }
+
+    page, more := paginate(found, query.Page)
+    result := make([]questions.Question, len(page))
+    for i, q := range page {
+        q.Tags = copyTags(q.Tags)
+        result[i] = q
+    }
+    return &result, more, nil
+}
+
+// Dao, который работает в транзакции, уже держащей блокировку хранилища s
+func newTxDao(s *store) *dao {
+    return &dao{s: s, tx: true}
+}
+
+// Вопросы не из корзины
+func (dao *dao) active() []questions.Question {
+    ql := []questions.Question{}
+    for _, q := range dao.s.sortedQuestions() {
+        if !q.DeletedAt.Valid {
+            ql = append(ql, q)
+        }
+    }
+    return ql
+}
+
+// Метод блокирует хранилище и возвращает функцию снятия блокировки.
+// В транзакции блокировку уже держит WithTx
+func (dao *dao) lock() func() {
+    if dao.tx {
+        return func() {}
+    }
+    dao.s.mu.Lock()
+    return dao.s.mu.Unlock
+}
+
+func copyTags(tags []questions.Tag) []questions.Tag {
+    if len(tags) == 0 {
+        return nil
+    }
+    return append([]questions.Tag{}, tags...)
+}
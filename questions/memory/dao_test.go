@@ -0,0 +1,313 @@
+package memory
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+func getTestDao(t *testing.T, ql ...*questions.Question) *dao {
+    dao := &dao{s: newStore()}
+    for _, q := range ql {
+        require.Nil(t, dao.Create(context.Background(), q), "Не удалось создать тестовый вопрос")
+    }
+    return dao
+}
+
+// ----------------
+// ---- Create ----
+// ----------------
+
+func Test_dao_create_set_id_version_and_tag_ids(t *testing.T) {
+    q1 := &questions.Question{UserId: 1, Tags: questions.NewTags([]string{"weak", "exam"})}
+    q2 := &questions.Question{UserId: 1, Tags: questions.NewTags([]string{"exam"})}
+
+    dao := getTestDao(t, q1, q2)
+
+    assert.Equal(t, []uint64{1, 2}, []uint64{q1.ID, q2.ID}, "Вопросы должны получить id по порядку")
+    assert.Equal(t, uint64(1), q1.Version, "Новый вопрос должен получить первую версию")
+    assert.Equal(t, q1.Tags[1].ID, q2.Tags[0].ID, "Метка с тем же именем должна получить тот же id")
+    assert.Len(t, dao.s.tags[1], 2, "Метки пользователя не должны дублироваться")
+}
+
+func Test_dao_create_save_copy_of_question(t *testing.T) {
+    q := &questions.Question{UserId: 1, Title: "Old"}
+    dao := getTestDao(t, q)
+
+    q.Title = "New"
+    list, _, _ := dao.Find(context.Background(), questions.NewQuery())
+
+    assert.Equal(t, "Old", (*list)[0].Title, "Изменение объекта после создания не должно менять сохраненный вопрос")
+}
+
+// ----------------
+// ---- Update ----
+// ----------------
+
+func Test_dao_update_change_fields_and_version(t *testing.T) {
+    q := &questions.Question{UserId: 1, Title: "Old", Body: "Old"}
+    dao := getTestDao(t, q)
+
+    qIn := &questions.Question{ID: q.ID, UserId: 1, Title: "New", Body: "New", Version: 1, Tags: questions.NewTags([]string{"weak"})}
+    errResult := dao.Update(context.Background(), qIn, []string{questions.QuestionTitle, questions.QuestionTags})
+
+    list, _, _ := dao.Find(context.Background(), questions.NewQuery())
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, uint64(2), qIn.Version, "Версия вопроса должна увеличиться")
+    assert.Equal(t, "New", (*list)[0].Title, "Переданное поле должно измениться")
+    assert.Equal(t, "Old", (*list)[0].Body, "Не переданное поле не должно измениться")
+    assert.Equal(t, []string{"weak"}, questions.TagNames((*list)[0].Tags), "Метки вопроса должны замениться")
+}
+
+func Test_dao_update_when_version_differs_or_question_in_trash_result_error_is_version_conflict(t *testing.T) {
+    q1, q2 := &questions.Question{UserId: 1}, &questions.Question{UserId: 1}
+    dao := getTestDao(t, q1, q2)
+    require.Nil(t, dao.Delete(context.Background(), questions.NewQuery().Eq(questions.FieldId, q2.ID)))
+
+    errVersion := dao.Update(context.Background(), &questions.Question{ID: q1.ID, Version: 2}, []string{questions.QuestionTitle})
+    errDeleted := dao.Update(context.Background(), &questions.Question{ID: q2.ID, Version: 1}, []string{questions.QuestionTitle})
+    errNotFound := dao.Update(context.Background(), &questions.Question{ID: 5, Version: 1}, []string{questions.QuestionTitle})
+
+    assert.ErrorIs(t, errVersion, questions.ErrVersionConflict, "Для другой версии ошибка должна быть ErrVersionConflict")
+    assert.ErrorIs(t, errDeleted, questions.ErrVersionConflict, "Для вопроса в корзине ошибка должна быть ErrVersionConflict")
+    assert.ErrorIs(t, errNotFound, questions.ErrVersionConflict, "Для несуществующего вопроса ошибка должна быть ErrVersionConflict")
+}
+
+// --------------
+// ---- Find ----
+// --------------
+
+func Test_dao_find_filter_sort_and_paginate_questions(t *testing.T) {
+    now := time.Now()
+    dao := getTestDao(t,
+        &questions.Question{UserId: 1, GroupId: 1, RepeatTime: now.Add(time.Hour)},
+        &questions.Question{UserId: 1, GroupId: 2, RepeatTime: now.Add(-time.Hour)},
+        &questions.Question{UserId: 2, GroupId: 1, RepeatTime: now},
+        &questions.Question{UserId: 1, GroupId: 1, RepeatTime: now},
+    )
+
+    query := questions.NewQuery().
+        Eq(questions.FieldUserId, uint64(1)).
+        In(questions.FieldGroupId, []uint64{1, 2}).
+        Lte(questions.FieldRepeatTime, now.Add(time.Hour)).
+        OrderBy(questions.FieldRepeatTime, false).
+        Paginate(2, 0)
+    list, more, errResult := dao.Find(context.Background(), query)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *list, 2, "Количество вопросов на странице неверное")
+    assert.Equal(t, []uint64{2, 4}, []uint64{(*list)[0].ID, (*list)[1].ID}, "Вопросы отобраны или отсортированы неверно")
+    assert.True(t, more, "После страницы есть еще вопросы")
+}
+
+func Test_dao_find_when_limit_covers_all_questions_more_is_false(t *testing.T) {
+    dao := getTestDao(t, &questions.Question{UserId: 1}, &questions.Question{UserId: 1})
+
+    list, more, _ := dao.Find(context.Background(), questions.NewQuery().Paginate(2, 0))
+
+    assert.Len(t, *list, 2, "Количество вопросов на странице неверное")
+    assert.False(t, more, "После страницы нет вопросов")
+}
+
+func Test_dao_find_when_field_not_supported_result_error_is_field_not_supported(t *testing.T) {
+    dao := getTestDao(t, &questions.Question{UserId: 1})
+
+    _, _, errWhere := dao.Find(context.Background(), questions.NewQuery().Eq(questions.Field("body"), "x"))
+    _, _, errSort := dao.Find(context.Background(), questions.NewQuery().OrderBy(questions.Field("body"), false))
+
+    assert.ErrorIs(t, errWhere, questions.ErrFieldNotSupported, "Для условия ошибка должна быть ErrFieldNotSupported")
+    assert.ErrorIs(t, errSort, questions.ErrFieldNotSupported, "Для сортировки ошибка должна быть ErrFieldNotSupported")
+}
+
+// ---------------
+// ---- Trash ----
+// ---------------
+
+func Test_dao_delete_move_questions_to_trash_and_restore_return_them(t *testing.T) {
+    q1, q2 := &questions.Question{UserId: 1}, &questions.Question{UserId: 1}
+    dao := getTestDao(t, q1, q2)
+
+    errDelete := dao.Delete(context.Background(), questions.NewQuery().Eq(questions.FieldId, q1.ID))
+    found, _, _ := dao.Find(context.Background(), questions.NewQuery())
+    deleted, _, _ := dao.FindDeleted(context.Background(), questions.NewQuery())
+
+    require.Nil(t, errDelete, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *found, 1, "Вопрос в корзине не должен находиться")
+    require.Len(t, *deleted, 1, "Вопрос должен попасть в корзину")
+    assert.True(t, (*deleted)[0].DeletedAt.Valid, "У вопроса в корзине должно быть время удаления")
+
+    qDeleted := (*deleted)[0]
+    errRestore := dao.Restore(context.Background(), &qDeleted)
+    found, _, _ = dao.Find(context.Background(), questions.NewQuery())
+
+    require.Nil(t, errRestore, "Возвращаемая ошибка должна быть пустой")
+    assert.Len(t, *found, 2, "Восстановленный вопрос должен находиться")
+    assert.Equal(t, uint64(2), qDeleted.Version, "Версия восстановленного вопроса должна увеличиться")
+}
+
+func Test_dao_delete_when_version_differs_result_error_is_version_conflict(t *testing.T) {
+    q := &questions.Question{UserId: 1}
+    dao := getTestDao(t, q)
+
+    errVersion := dao.Delete(context.Background(), questions.NewQuery().Eq(questions.FieldId, q.ID).Eq(questions.FieldVersion, q.Version+1))
+    errNoVersion := dao.Delete(context.Background(), questions.NewQuery().Eq(questions.FieldId, q.ID+1))
+
+    found, _, _ := dao.Find(context.Background(), questions.NewQuery())
+    assert.ErrorIs(t, errVersion, questions.ErrVersionConflict, "Возвращаемая ошибка должна быть ErrVersionConflict")
+    assert.Nil(t, errNoVersion, "Без проверки версии отсутствие вопросов не должно быть ошибкой")
+    assert.Len(t, *found, 1, "Вопрос другой версии не должен удаляться")
+}
+
+func Test_dao_restore_when_question_not_in_trash_result_error_is_version_conflict(t *testing.T) {
+    q := &questions.Question{UserId: 1}
+    dao := getTestDao(t, q)
+
+    errResult := dao.Restore(context.Background(), q)
+
+    assert.ErrorIs(t, errResult, questions.ErrVersionConflict, "Возвращаемая ошибка должна быть ErrVersionConflict")
+}
+
+func Test_dao_purge_remove_expired_questions_with_reviews(t *testing.T) {
+    q1, q2, q3 := &questions.Question{UserId: 1}, &questions.Question{UserId: 1}, &questions.Question{UserId: 1}
+    dao := getTestDao(t, q1, q2, q3)
+    reviewDao := &reviewDao{s: dao.s}
+    require.Nil(t, reviewDao.Create(context.Background(), &questions.Review{QuestionId: q1.ID}))
+    require.Nil(t, reviewDao.Create(context.Background(), &questions.Review{QuestionId: q3.ID}))
+    require.Nil(t, dao.Delete(context.Background(), questions.NewQuery().In(questions.FieldId, []uint64{q1.ID, q2.ID})))
+
+    count, errResult := dao.Purge(context.Background(), time.Now().Add(time.Second))
+
+    deleted, _, _ := dao.FindDeleted(context.Background(), questions.NewQuery())
+    found, _, _ := dao.Find(context.Background(), questions.NewQuery())
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, int64(2), count, "Количество удаленных вопросов неверное")
+    assert.Len(t, *deleted, 0, "Корзина должна быть пустой")
+    assert.Len(t, *found, 1, "Вопрос не из корзины не должен удаляться")
+    assert.Equal(t, []questions.Review{{ID: 2, QuestionId: q3.ID}}, dao.s.reviews, "Должна остаться только история вопроса не из корзины")
+}
+
+// ----------------
+// ---- Search ----
+// ----------------
+
+func Test_dao_search_find_all_words_in_title_or_body_ignoring_case(t *testing.T) {
+    dao := getTestDao(t,
+        &questions.Question{UserId: 1, Title: "Present Perfect", Body: "have done"},
+        &questions.Question{UserId: 1, Title: "Past simple", Body: "did"},
+        &questions.Question{UserId: 1, Title: "present", Body: "perfect tense"},
+    )
+
+    list, more, errResult := dao.Search(context.Background(), "PRESENT perfect", questions.NewQuery().Paginate(1, 0))
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *list, 1, "Количество вопросов на странице неверное")
+    assert.Equal(t, uint64(3), (*list)[0].ID, "Найденные вопросы должны сортироваться по убыванию id")
+    assert.True(t, more, "После страницы есть еще найденные вопросы")
+}
+
+// ------------------------
+// ---- Count by group ----
+// ------------------------
+
+func Test_dao_count_by_group_count_total_and_due_questions(t *testing.T) {
+    now := time.Now()
+    dao := getTestDao(t,
+        &questions.Question{UserId: 1, GroupId: 2, RepeatTime: now.Add(-time.Hour)},
+        &questions.Question{UserId: 1, GroupId: 2, RepeatTime: now.Add(time.Hour)},
+        &questions.Question{UserId: 1, GroupId: 1, RepeatTime: now},
+        &questions.Question{UserId: 2, GroupId: 1, RepeatTime: now},
+    )
+
+    counts, errResult := dao.CountByGroup(context.Background(), questions.NewQuery().Eq(questions.FieldUserId, uint64(1)), now)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, &[]questions.GroupCount{
+        {GroupId: 1, Total: 1, Due: 1},
+        {GroupId: 2, Total: 2, Due: 1},
+    }, counts, "Количество вопросов в группах неверное")
+}
+
+// ---------------------
+// ---- Find tagged ----
+// ---------------------
+
+func Test_dao_find_with_tags_return_questions_of_user_with_any_or_all_tags(t *testing.T) {
+    dao := getTestDao(t,
+        &questions.Question{UserId: 1, Tags: questions.NewTags([]string{"weak", "exam"})},
+        &questions.Question{UserId: 1, Tags: questions.NewTags([]string{"weak"})},
+        &questions.Question{UserId: 2, Tags: questions.NewTags([]string{"weak", "exam"})},
+    )
+
+    userQuery := func() *questions.Query {
+        return questions.NewQuery().Eq(questions.FieldUserId, uint64(1))
+    }
+    anyResult, _, errAny := dao.Find(context.Background(), userQuery().Tagged([]string{"weak", "exam"}, false))
+    allResult, _, errAll := dao.Find(context.Background(), userQuery().Tagged([]string{"weak", "exam"}, true))
+
+    require.Nil(t, errAny, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errAll, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *anyResult, 2, "Должны найтись вопросы пользователя с любой из меток")
+    assert.Equal(t, uint64(1), (*anyResult)[0].ID, "Должны найтись вопросы пользователя с любой из меток")
+    assert.Equal(t, uint64(2), (*anyResult)[1].ID, "Должны найтись вопросы пользователя с любой из меток")
+    require.Len(t, *allResult, 1, "Должны найтись вопросы пользователя со всеми метками")
+    assert.Equal(t, uint64(1), (*allResult)[0].ID, "Должны найтись вопросы пользователя со всеми метками")
+}
+
+// ----------------
+// ---- WithTx ----
+// ----------------
+
+func Test_dao_with_tx_when_fc_work_success_changes_are_committed(t *testing.T) {
+    dao := getTestDao(t)
+
+    errResult := dao.WithTx(context.Background(), func(txDao questions.Dao, txReviewDao questions.ReviewDao) error {
+        q := &questions.Question{UserId: 1}
+        if err := txDao.Create(context.Background(), q); err != nil {
+            return err
+        }
+        return txReviewDao.Create(context.Background(), &questions.Review{QuestionId: q.ID})
+    })
+
+    list, _, _ := dao.Find(context.Background(), questions.NewQuery())
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Len(t, *list, 1, "Вопрос, созданный в транзакции, должен сохраниться")
+    assert.Len(t, dao.s.reviews, 1, "История повторений, записанная в транзакции, должна сохраниться")
+}
+
+func Test_dao_with_tx_when_fc_work_wrong_changes_are_rolled_back_and_result_error_have_info_from_fc(t *testing.T) {
+    q := &questions.Question{UserId: 1, Title: "Old"}
+    dao := getTestDao(t, q)
+    fcErr := errors.New("Fc error")
+
+    errResult := dao.WithTx(context.Background(), func(txDao questions.Dao, txReviewDao questions.ReviewDao) error {
+        qNew := &questions.Question{ID: q.ID, Title: "New", Version: q.Version}
+        if err := txDao.Update(context.Background(), qNew, []string{questions.QuestionTitle}); err != nil {
+            return err
+        }
+        if err := txDao.Create(context.Background(), &questions.Question{UserId: 1, Tags: questions.NewTags([]string{"weak"})}); err != nil {
+            return err
+        }
+        if err := txReviewDao.Create(context.Background(), &questions.Review{QuestionId: q.ID}); err != nil {
+            return err
+        }
+        return fcErr
+    })
+
+    list, _, _ := dao.Find(context.Background(), questions.NewQuery())
+    require.ErrorIs(t, errResult, fcErr, "Возвращаемая ошибка должна содержать информацию из fc")
+    require.Len(t, *list, 1, "Вопрос, созданный в откаченной транзакции, не должен сохраниться")
+    assert.Equal(t, "Old", (*list)[0].Title, "Изменения откаченной транзакции не должны сохраниться")
+    assert.Equal(t, uint64(1), (*list)[0].Version, "Версия вопроса должна остаться прежней")
+    assert.Len(t, dao.s.reviews, 0, "История повторений откаченной транзакции не должна сохраниться")
+    assert.Len(t, dao.s.tags, 0, "Метки откаченной транзакции не должны сохраниться")
+
+    qNext := &questions.Question{UserId: 1}
+    require.Nil(t, dao.Create(context.Background(), qNext))
+    assert.Equal(t, uint64(2), qNext.ID, "Id вопросов откаченной транзакции должны освободиться")
+}
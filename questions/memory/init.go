@@ -0,0 +1,19 @@
+package memory
+
+import (
+    "github.com/golobby/container"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+// Метод регистрирует в контейнере dao вопросов и истории повторений, которые хранят
+// данные в памяти процесса. Данные не переживают перезапуск, зато не нужна база
+func Register() {
+    s := newStore()
+    container.Singleton(func() questions.Dao {
+        return &dao{s: s}
+    })
+    container.Singleton(func() questions.ReviewDao {
+        return &reviewDao{s: s}
+    })
+}
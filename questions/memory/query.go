@@ -0,0 +1,210 @@
+package memory
+
+import (
+    "sort"
+
+    "github.com/pkg/errors"
+
+    "github.com/chudoyoudo/remember-cards/memory"
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+// Колонки, по которым разрешено фильтровать и сортировать вопросы, как в хранилище gorm
+var queryColumns = map[questions.Field]string{
+    questions.FieldId:         "id",
+    questions.FieldUserId:     questions.QuestionUserId,
+    questions.FieldGroupId:    questions.QuestionGroupId,
+    questions.FieldRepeatTime: questions.QuestionRepeatTime,
+    questions.FieldTitle:      "title",
+    questions.FieldStep:       "step",
+    questions.FieldIsFailed:   "isFailed",
+    questions.FieldVersion:    "version",
+    questions.FieldDeletedAt:  "deletedAt",
+}
+
+// Колонки, по которым разрешено фильтровать и сортировать историю повторений
+var reviewColumns = map[questions.Field]string{
+    questions.FieldId:         "id",
+    questions.FieldUserId:     questions.ReviewUserId,
+    questions.FieldQuestionId: questions.ReviewQuestionId,
+    questions.FieldAnsweredAt: "answeredAt",
+}
+
+// Метод проверяет, что вопрос подходит под все условия запроса
+func match(q *questions.Question, query *questions.Query) (bool, error) {
+    for _, p := range query.Where {
+        if p.Field == questions.FieldTags {
+            found, err := matchTags(q, p)
+            if err != nil {
+                return false, err
+            }
+            if !found {
+                return false, nil
+            }
+            continue
+        }
+
+        found, err := matchPredicate(q, queryColumns, p)
+        if err != nil {
+            return false, err
+        }
+        if !found {
+            return false, nil
+        }
+    }
+    return true, nil
+}
+
+// Метод проверяет, что запись истории повторений подходит под все условия запроса
+func matchReview(r *questions.Review, query *questions.Query) (bool, error) {
+    for _, p := range query.Where {
+        found, err := matchPredicate(r, reviewColumns, p)
+        if err != nil {
+            return false, err
+        }
+        if !found {
+            return false, nil
+        }
+    }
+    return true, nil
+}
+
+// Метод проверяет, что поле записи v подходит под условие p
+func matchPredicate(v interface{}, columns map[questions.Field]string, p questions.Predicate) (bool, error) {
+    column, err := tableColumn(columns, p.Field)
+    if err != nil {
+        return false, err
+    }
+
+    if p.Op == questions.OpIn {
+        return memory.Match(v, map[string]interface{}{column: p.Value})
+    }
+
+    value, err := memory.Value(v, column)
+    if err != nil {
+        return false, err
+    }
+    result, err := memory.Compare(value, p.Value)
+    if err != nil {
+        return false, errors.Wrapf(err, "Can't compare field %q", p.Field)
+    }
+
+    switch p.Op {
+    case questions.OpEq:
+        return result == 0, nil
+    case questions.OpLt:
+        return result < 0, nil
+    case questions.OpLte:
+        return result <= 0, nil
+    case questions.OpGt:
+        return result > 0, nil
+    case questions.OpGte:
+        return result >= 0, nil
+    }
+    return false, errors.Errorf("Operation %q is not supported in query", p.Op)
+}
+
+// Метод проверяет, что у вопроса есть любая из меток условия, а при OpAll — все метки
+func matchTags(q *questions.Question, p questions.Predicate) (bool, error) {
+    names, ok := p.Value.([]string)
+    if !ok {
+        return false, errors.Errorf("Tags value %v is not a list of names", p.Value)
+    }
+
+    tagged := map[string]bool{}
+    for _, t := range q.Tags {
+        tagged[t.Name] = true
+    }
+    wanted := questions.TagNames(questions.NewTags(names))
+    count := 0
+    for _, name := range wanted {
+        if tagged[name] {
+            count++
+        }
+    }
+
+    switch p.Op {
+    case questions.OpAny:
+        return count > 0, nil
+    case questions.OpAll:
+        return count == len(wanted), nil
+    }
+    return false, errors.Errorf("Operation %q is not supported for tags", p.Op)
+}
+
+// Метод отбирает вопросы по условиям запроса и сортирует их по порядку запроса.
+// Вопросы с равными значениями сохраняют исходный порядок
+func filter(ql []questions.Question, query *questions.Query) ([]questions.Question, error) {
+    result := []questions.Question{}
+    for i := range ql {
+        found, err := match(&ql[i], query)
+        if err != nil {
+            return nil, err
+        }
+        if found {
+            result = append(result, ql[i])
+        }
+    }
+
+    err := sortRecords(result, func(i int) interface{} { return &result[i] }, queryColumns, query.Sort)
+    return result, err
+}
+
+// Метод отбирает записи истории повторений по условиям запроса и сортирует их по порядку запроса
+func filterReviews(rl []questions.Review, query *questions.Query) ([]questions.Review, error) {
+    result := []questions.Review{}
+    for i := range rl {
+        found, err := matchReview(&rl[i], query)
+        if err != nil {
+            return nil, err
+        }
+        if found {
+            result = append(result, rl[i])
+        }
+    }
+
+    err := sortRecords(result, func(i int) interface{} { return &result[i] }, reviewColumns, query.Sort)
+    return result, err
+}
+
+// Метод сортирует срез list по порядку sorts, получая i-ю запись среза через record.
+// Записи с равными значениями сохраняют исходный порядок
+func sortRecords(list interface{}, record func(i int) interface{}, columns map[questions.Field]string, sorts []questions.Sort) error {
+    for _, s := range sorts {
+        if _, err := tableColumn(columns, s.Field); err != nil {
+            return err
+        }
+    }
+
+    var err error
+    sort.SliceStable(list, func(i, j int) bool {
+        for _, s := range sorts {
+            a, _ := memory.Value(record(i), columns[s.Field])
+            b, _ := memory.Value(record(j), columns[s.Field])
+            compared, errCompare := memory.Compare(a, b)
+            if errCompare != nil {
+                err = errors.Wrapf(errCompare, "Can't sort by field %q", s.Field)
+                return false
+            }
+            if compared != 0 {
+                return (compared < 0) != s.Desc
+            }
+        }
+        return false
+    })
+    return err
+}
+
+// Метод возвращает страницу вопросов запроса и признак того, что после нее есть еще вопросы
+func paginate(ql []questions.Question, page questions.Page) ([]questions.Question, bool) {
+    from, to, more := memory.Page(len(ql), page.Limit, page.Offset)
+    return ql[from:to], more
+}
+
+func tableColumn(columns map[questions.Field]string, f questions.Field) (string, error) {
+    column, ok := columns[f]
+    if !ok {
+        return "", errors.Wrapf(questions.ErrFieldNotSupported, "Field %q", f)
+    }
+    return column, nil
+}
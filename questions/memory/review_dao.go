@@ -0,0 +1,51 @@
+package memory
+
+import (
+    "context"
+
+    "github.com/pkg/errors"
+
+    "github.com/chudoyoudo/remember-cards/memory"
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+type reviewDao struct {
+    s *store
+    // История повторений транзакции работает под блокировкой, которую уже держит WithTx
+    tx bool
+}
+
+func (dao *reviewDao) Create(ctx context.Context, r *questions.Review) error {
+    defer dao.lock()()
+
+    dao.s.lastReview++
+    r.ID = dao.s.lastReview
+    dao.s.reviews = append(dao.s.reviews, *r)
+    return nil
+}
+
+func (dao *reviewDao) Find(ctx context.Context, query *questions.Query) (list *[]questions.Review, more bool, err error) {
+    defer dao.lock()()
+
+    rl, err := filterReviews(dao.s.reviews, query)
+    if err != nil {
+        return &[]questions.Review{}, false, errors.Wrapf(err, "Can't find reviews by query %v", *query)
+    }
+
+    from, to, more := memory.Page(len(rl), query.Page.Limit, query.Page.Offset)
+    rl = rl[from:to]
+    return &rl, more, nil
+}
+
+// История повторений, которая работает в транзакции, уже держащей блокировку хранилища s
+func newTxReviewDao(s *store) *reviewDao {
+    return &reviewDao{s: s, tx: true}
+}
+
+func (dao *reviewDao) lock() func() {
+    if dao.tx {
+        return func() {}
+    }
+    dao.s.mu.Lock()
+    return dao.s.mu.Unlock
+}
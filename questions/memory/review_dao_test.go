@@ -0,0 +1,62 @@
+package memory
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+// ----------------
+// ---- Create ----
+// ----------------
+
+func Test_review_dao_create_set_id_and_save_review(t *testing.T) {
+    dao := &reviewDao{s: newStore()}
+    r := &questions.Review{QuestionId: 1, Grade: questions.GradeGood}
+
+    errResult := dao.Create(context.Background(), r)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, uint64(1), r.ID, "Запись должна получить id")
+    assert.Equal(t, []questions.Review{*r}, dao.s.reviews, "Запись должна сохраниться")
+}
+
+// --------------
+// ---- Find ----
+// --------------
+
+func Test_review_dao_find_filter_by_conds_sort_and_paginate(t *testing.T) {
+    now := time.Now()
+    dao := &reviewDao{s: newStore()}
+    for _, r := range []questions.Review{
+        {QuestionId: 1, UserId: 1, AnsweredAt: now.Add(-time.Hour)},
+        {QuestionId: 2, UserId: 1, AnsweredAt: now},
+        {QuestionId: 1, UserId: 2, AnsweredAt: now},
+        {QuestionId: 1, UserId: 1, AnsweredAt: now.Add(time.Hour)},
+    } {
+        r := r
+        require.Nil(t, dao.Create(context.Background(), &r))
+    }
+
+    query := questions.NewQuery().Eq(questions.FieldUserId, uint64(1)).OrderBy(questions.FieldAnsweredAt, true).Paginate(2, 0)
+    list, more, errResult := dao.Find(context.Background(), query)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *list, 2, "Количество записей на странице неверное")
+    assert.Equal(t, []uint64{4, 2}, []uint64{(*list)[0].ID, (*list)[1].ID}, "Записи отобраны или отсортированы неверно")
+    assert.True(t, more, "После страницы есть еще записи")
+}
+
+func Test_review_dao_find_when_field_is_not_review_field_result_error_is_field_not_supported(t *testing.T) {
+    dao := &reviewDao{s: newStore()}
+    require.Nil(t, dao.Create(context.Background(), &questions.Review{}))
+
+    _, _, errResult := dao.Find(context.Background(), questions.NewQuery().Eq(questions.FieldGroupId, uint64(1)))
+
+    assert.ErrorIs(t, errResult, questions.ErrFieldNotSupported, "Возвращаемая ошибка должна быть ErrFieldNotSupported")
+}
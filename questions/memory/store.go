@@ -0,0 +1,102 @@
+package memory
+
+import (
+    "sort"
+    "sync"
+
+    "github.com/chudoyoudo/remember-cards/questions"
+)
+
+// Данные вопросов, меток и истории повторений, общие для dao вопросов и истории повторений.
+// Вопросы хранятся копиями, поэтому изменения объекта после вызова dao не меняют сохраненные данные
+type store struct {
+    mu        sync.Mutex
+    questions map[uint64]questions.Question
+    reviews   []questions.Review
+    // id метки пользователя по ее имени
+    tags         map[uint64]map[string]uint64
+    lastQuestion uint64
+    lastReview   uint64
+    lastTag      uint64
+}
+
+func newStore() *store {
+    return &store{
+        questions: map[uint64]questions.Question{},
+        reviews:   []questions.Review{},
+        tags:      map[uint64]map[string]uint64{},
+    }
+}
+
+// Копия данных, из которой WithTx восстанавливает хранилище при откате
+func (s *store) snapshot() *store {
+    result := &store{
+        questions:    make(map[uint64]questions.Question, len(s.questions)),
+        reviews:      append([]questions.Review{}, s.reviews...),
+        tags:         make(map[uint64]map[string]uint64, len(s.tags)),
+        lastQuestion: s.lastQuestion,
+        lastReview:   s.lastReview,
+        lastTag:      s.lastTag,
+    }
+    for id, q := range s.questions {
+        result.questions[id] = q
+    }
+    for userId, byName := range s.tags {
+        result.tags[userId] = make(map[string]uint64, len(byName))
+        for name, id := range byName {
+            result.tags[userId][name] = id
+        }
+    }
+    return result
+}
+
+func (s *store) restore(from *store) {
+    s.questions = from.questions
+    s.reviews = from.reviews
+    s.tags = from.tags
+    s.lastQuestion = from.lastQuestion
+    s.lastReview = from.lastReview
+    s.lastTag = from.lastTag
+}
+
+// Метод возвращает вопросы по возрастанию id, как их без сортировки вернула бы база
+func (s *store) sortedQuestions() []questions.Question {
+    ql := make([]questions.Question, 0, len(s.questions))
+    for _, q := range s.questions {
+        ql = append(ql, q)
+    }
+    sort.Slice(ql, func(i, j int) bool {
+        return ql[i].ID < ql[j].ID
+    })
+    return ql
+}
+
+// Метод заполняет id меток пользователя по именам, создает недостающие метки
+// и возвращает копию меток, отсортированную по имени
+func (s *store) ensureTags(userId uint64, tags []questions.Tag) []questions.Tag {
+    if len(tags) == 0 {
+        return nil
+    }
+
+    byName, found := s.tags[userId]
+    if !found {
+        byName = map[string]uint64{}
+        s.tags[userId] = byName
+    }
+
+    result := make([]questions.Tag, 0, len(tags))
+    for i := range tags {
+        id, found := byName[tags[i].Name]
+        if !found {
+            s.lastTag++
+            id = s.lastTag
+            byName[tags[i].Name] = id
+        }
+        tags[i] = questions.Tag{ID: id, UserId: userId, Name: tags[i].Name}
+        result = append(result, tags[i])
+    }
+    sort.Slice(result, func(i, j int) bool {
+        return result[i].Name < result[j].Name
+    })
+    return result
+}
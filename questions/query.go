@@ -0,0 +1,138 @@
+package questions
+
+import "github.com/pkg/errors"
+
+var ErrFieldNotSupported = errors.New("Field is not supported in query")
+
+// Поле вопроса или записи истории повторений, по которому можно фильтровать и сортировать выборку.
+// Хранилище принимает только перечисленные поля, поэтому в запрос
+// нельзя подставить произвольное выражение
+type Field string
+
+const (
+    FieldId         Field = "id"
+    FieldUserId     Field = QuestionUserId
+    FieldGroupId    Field = QuestionGroupId
+    FieldRepeatTime Field = QuestionRepeatTime
+    FieldTitle      Field = QuestionTitle
+    FieldStep       Field = questionStep
+    FieldIsFailed   Field = questionIsFailed
+    FieldVersion    Field = questionVersion
+    FieldDeletedAt  Field = questionDeletedAt
+    // Метки хранятся в отдельной таблице, поэтому поле поддерживает только OpAny и OpAll
+    FieldTags Field = QuestionTags
+    // Поля истории повторений. FieldId и FieldUserId есть и у записей истории
+    FieldQuestionId Field = ReviewQuestionId
+    FieldAnsweredAt Field = reviewAnsweredAt
+)
+
+// Операция сравнения поля со значением. Для OpIn, OpAny и OpAll значение — срез
+type Op string
+
+const (
+    OpEq  Op = "="
+    OpIn  Op = "in"
+    OpLt  Op = "<"
+    OpLte Op = "<="
+    OpGt  Op = ">"
+    OpGte Op = ">="
+    // У вопроса есть хотя бы одна из меток
+    OpAny Op = "any"
+    // У вопроса есть все метки сразу
+    OpAll Op = "all"
+)
+
+type Predicate struct {
+    Field Field
+    Op    Op
+    Value interface{}
+}
+
+type Sort struct {
+    Field Field
+    Desc  bool
+}
+
+// Страница выборки. Нулевой Limit означает выборку без ограничения
+type Page struct {
+    Limit  int
+    Offset int
+}
+
+// Запрос вопросов: все условия Where должны выполняться одновременно,
+// сортировка идет по порядку Sort. Методы дополняют запрос и возвращают его же,
+// поэтому запрос можно собирать цепочкой
+type Query struct {
+    Where []Predicate
+    Sort  []Sort
+    Page  Page
+}
+
+func NewQuery() *Query {
+    return &Query{}
+}
+
+func (q *Query) Eq(f Field, value interface{}) *Query {
+    return q.add(f, OpEq, value)
+}
+
+func (q *Query) In(f Field, values interface{}) *Query {
+    return q.add(f, OpIn, values)
+}
+
+func (q *Query) Lt(f Field, value interface{}) *Query {
+    return q.add(f, OpLt, value)
+}
+
+func (q *Query) Lte(f Field, value interface{}) *Query {
+    return q.add(f, OpLte, value)
+}
+
+func (q *Query) Gt(f Field, value interface{}) *Query {
+    return q.add(f, OpGt, value)
+}
+
+func (q *Query) Gte(f Field, value interface{}) *Query {
+    return q.add(f, OpGte, value)
+}
+
+func (q *Query) OrderBy(f Field, desc bool) *Query {
+    q.Sort = append(q.Sort, Sort{Field: f, Desc: desc})
+    return q
+}
+
+func (q *Query) Paginate(limit, offset int) *Query {
+    q.Page = Page{Limit: limit, Offset: offset}
+    return q
+}
+
+// Метод возвращает копию запроса, которую можно дополнять, не меняя исходный
+func (q *Query) Clone() *Query {
+    result := *q
+    result.Where = append([]Predicate(nil), q.Where...)
+    result.Sort = append([]Sort(nil), q.Sort...)
+    return &result
+}
+
+// Метод оставляет вопросы с любой из меток names, а при all — со всеми метками сразу
+func (q *Query) Tagged(names []string, all bool) *Query {
+    if all {
+        return q.add(FieldTags, OpAll, names)
+    }
+    return q.add(FieldTags, OpAny, names)
+}
+
+// Метод проверяет, есть ли в запросе условие на поле f
+func (q *Query) Has(f Field) bool {
+    for _, p := range q.Where {
+        if p.Field == f {
+            return true
+        }
+    }
+    return false
+}
+
+func (q *Query) add(f Field, op Op, value interface{}) *Query {
+    q.Where = append(q.Where, Predicate{Field: f, Op: op, Value: value})
+    return q
+}
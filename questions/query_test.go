@@ -0,0 +1,50 @@
+package questions
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_query_builder_collect_predicates_sort_and_page(t *testing.T) {
+    now := time.Now()
+
+    query := NewQuery().
+        Eq(FieldUserId, uint64(1)).
+        In(FieldGroupId, []uint64{2, 3}).
+        Lt(FieldId, uint64(10)).
+        Gte(FieldRepeatTime, now).
+        OrderBy(FieldRepeatTime, false).
+        OrderBy(FieldId, true).
+        Paginate(20, 40)
+
+    assert.Equal(t, &Query{
+        Where: []Predicate{
+            {Field: FieldUserId, Op: OpEq, Value: uint64(1)},
+            {Field: FieldGroupId, Op: OpIn, Value: []uint64{2, 3}},
+            {Field: FieldId, Op: OpLt, Value: uint64(10)},
+            {Field: FieldRepeatTime, Op: OpGte, Value: now},
+        },
+        Sort: []Sort{{Field: FieldRepeatTime}, {Field: FieldId, Desc: true}},
+        Page: Page{Limit: 20, Offset: 40},
+    }, query, "Запрос собран неверно")
+}
+
+func Test_query_clone_does_not_change_original(t *testing.T) {
+    query := NewQuery().Eq(FieldUserId, uint64(1)).OrderBy(FieldId, true)
+
+    clone := query.Clone().Lte(FieldRepeatTime, time.Now()).OrderBy(FieldRepeatTime, false).Paginate(1, 0)
+
+    assert.Len(t, query.Where, 1, "Условия исходного запроса не должны меняться")
+    assert.Len(t, query.Sort, 1, "Сортировка исходного запроса не должна меняться")
+    assert.Equal(t, Page{}, query.Page, "Страница исходного запроса не должна меняться")
+    assert.Len(t, clone.Where, 2, "Копия должна содержать новые условия")
+}
+
+func Test_query_has_check_only_predicates(t *testing.T) {
+    query := NewQuery().Eq(FieldId, uint64(1)).OrderBy(FieldVersion, false)
+
+    assert.True(t, query.Has(FieldId), "Условие на поле должно находиться")
+    assert.False(t, query.Has(FieldVersion), "Сортировка не должна считаться условием")
+}
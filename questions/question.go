@@ -1,17 +1,48 @@
 package questions
 
-import "time"
+import (
+    "time"
+
+    "github.com/pkg/errors"
+    "gorm.io/gorm"
+)
 
 const (
     QuestionUserId     = "userId"
     QuestionGroupId    = "groupId"
-    questionTitle      = "title"
-    questionBody       = "body"
+    QuestionRepeatTime = "repeatTime"
+    QuestionTags       = "tags"
+    QuestionTitle      = "title"
+    QuestionBody       = "body"
     questionStep       = "step"
-    questionRepeatTime = "repeatTime"
     questionIsFailed   = "isFailed"
+    questionEase       = "ease"
+    questionInterval   = "interval"
+    questionStability  = "stability"
+    questionDifficulty = "difficulty"
+    questionLastReview = "lastReview"
+    questionVersion    = "version"
+    questionDeletedAt  = "deletedAt"
 )
 
+var (
+    ErrFieldNotEditable = errors.New("Field is not editable")
+    // Вопрос изменили после того, как его прочитали
+    ErrVersionConflict = errors.New("Question version conflict")
+)
+
+// Поля, которые пользователь меняет сам. Остальные поля заполняет алгоритм повторений
+var EditableFields = []string{QuestionGroupId, QuestionTitle, QuestionBody, QuestionTags}
+
+func IsEditable(field string) bool {
+    for _, f := range EditableFields {
+        if f == field {
+            return true
+        }
+    }
+    return false
+}
+
 type Question struct {
     ID         uint64    `json:"id" gorm:"primaryKey"`
     UserId     uint64    `json:"userId" gorm:"column:userId"`
@@ -19,8 +50,21 @@ type Question struct {
     Title      string    `json:"title"`
     Body       string    `json:"body"`
     Step       uint8     `json:"-"`
-    RepeatTime time.Time `json:"repeatTime"`
-    IsFailed   bool      `json:"isFailed"`
+    RepeatTime time.Time `json:"repeatTime" gorm:"column:repeatTime"`
+    IsFailed   bool      `json:"isFailed" gorm:"column:isFailed"`
+    Ease       float64   `json:"-" gorm:"column:ease"`
+    Interval   uint32    `json:"-" gorm:"column:interval"`
+    Stability  float64   `json:"-" gorm:"column:stability"`
+    Difficulty float64   `json:"-" gorm:"column:difficulty"`
+    LastReview time.Time `json:"-" gorm:"column:lastReview"`
+    // Версия растет при каждом изменении вопроса. Хранилище обновляет вопрос,
+    // только если его версия совпадает с версией изменяемого объекта
+    Version uint64 `json:"version" gorm:"column:version;not null;default:1"`
+    // Удаленный вопрос попадает в корзину: запросы его не видят, пока он не восстановлен
+    // или не удален из корзины окончательно
+    DeletedAt gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deletedAt;index"`
+    // Метки хранятся в отдельной таблице, и ToMap их не возвращает
+    Tags []Tag `json:"tags,omitempty" gorm:"many2many:question_tags;constraint:OnDelete:CASCADE"`
 }
 
 func (q *Question) ToMap(fields []string) *map[string]interface{} {
@@ -32,16 +76,26 @@ func (q *Question) ToMap(fields []string) *map[string]interface{} {
                 result[field] = q.UserId
             case QuestionGroupId:
                 result[field] = q.GroupId
-            case questionTitle:
+            case QuestionTitle:
                 result[field] = q.Title
-            case questionBody:
+            case QuestionBody:
                 result[field] = q.Body
             case questionStep:
                 result[field] = q.Step
-            case questionRepeatTime:
+            case QuestionRepeatTime:
                 result[field] = q.RepeatTime
             case questionIsFailed:
                 result[field] = q.IsFailed
+            case questionEase:
+                result[field] = q.Ease
+            case questionInterval:
+                result[field] = q.Interval
+            case questionStability:
+                result[field] = q.Stability
+            case questionDifficulty:
+                result[field] = q.Difficulty
+            case questionLastReview:
+                result[field] = q.LastReview
             }
         }
         return &result
@@ -50,10 +104,15 @@ func (q *Question) ToMap(fields []string) *map[string]interface{} {
     return &map[string]interface{}{
         QuestionUserId:     q.UserId,
         QuestionGroupId:    q.GroupId,
-        questionTitle:      q.Title,
-        questionBody:       q.Body,
+        QuestionTitle:      q.Title,
+        QuestionBody:       q.Body,
         questionStep:       q.Step,
-        questionRepeatTime: q.RepeatTime,
+        QuestionRepeatTime: q.RepeatTime,
         questionIsFailed:   q.IsFailed,
+        questionEase:       q.Ease,
+        questionInterval:   q.Interval,
+        questionStability:  q.Stability,
+        questionDifficulty: q.Difficulty,
+        questionLastReview: q.LastReview,
     }
 }
@@ -18,16 +18,26 @@ func Test_question_to_map_return_all_fields_if_fieldst_list_in_params_is_empty(t
 		Step:       4,
 		RepeatTime: rt,
 		IsFailed:   true,
+		Ease:       2.5,
+		Interval:   6,
+		Stability:  2.4,
+		Difficulty: 4.93,
+		LastReview: rt,
 	}
 
 	expectedMap := map[string]interface{}{
 		QuestionUserId:     uint64(2),
 		QuestionGroupId:    uint64(3),
-		questionTitle:      "Title",
-		questionBody:       "Body",
+		QuestionTitle:      "Title",
+		QuestionBody:       "Body",
 		questionStep:       uint8(4),
-		questionRepeatTime: rt,
+		QuestionRepeatTime: rt,
 		questionIsFailed:   true,
+		questionEase:       2.5,
+		questionInterval:   uint32(6),
+		questionStability:  2.4,
+		questionDifficulty: 4.93,
+		questionLastReview: rt,
 	}
 	resultMap := q.ToMap([]string{})
 
@@ -45,18 +55,28 @@ func Test_question_to_map_return_only_requested_fields_data(t *testing.T) {
 		Step:       4,
 		RepeatTime: rt,
 		IsFailed:   true,
+		Ease:       2.5,
+		Interval:   6,
+		Stability:  2.4,
+		Difficulty: 4.93,
+		LastReview: rt,
 	}
 
 	expectedMap := map[string]interface{}{
 		QuestionUserId:     uint64(2),
 		QuestionGroupId:    uint64(3),
-		questionTitle:      "Title",
-		questionBody:       "Body",
+		QuestionTitle:      "Title",
+		QuestionBody:       "Body",
 		questionStep:       uint8(4),
-		questionRepeatTime: rt,
+		QuestionRepeatTime: rt,
 		questionIsFailed:   true,
+		questionEase:       2.5,
+		questionInterval:   uint32(6),
+		questionStability:  2.4,
+		questionDifficulty: 4.93,
+		questionLastReview: rt,
 	}
-	resultMap := q.ToMap([]string{QuestionUserId, QuestionGroupId, questionTitle, questionBody, questionStep, questionRepeatTime, questionIsFailed})
+	resultMap := q.ToMap([]string{QuestionUserId, QuestionGroupId, QuestionTitle, QuestionBody, questionStep, QuestionRepeatTime, questionIsFailed, questionEase, questionInterval, questionStability, questionDifficulty, questionLastReview})
 
 	assert.Equal(t, expectedMap, *resultMap, "Возвращаемая мапа не содержит все необходимые дданные")
 }
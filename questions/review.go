@@ -0,0 +1,24 @@
+package questions
+
+import "time"
+
+const (
+    ReviewQuestionId = "questionId"
+    ReviewUserId     = "userId"
+    reviewAnsweredAt = "answeredAt"
+)
+
+// Запись об ответе на вопрос
+type Review struct {
+    ID             uint64    `json:"id" gorm:"primaryKey"`
+    QuestionId     uint64    `json:"questionId" gorm:"column:questionId;index"`
+    UserId         uint64    `json:"userId" gorm:"column:userId;index"`
+    Grade          Grade     `json:"grade"`
+    PrevStep       uint8     `json:"prevStep" gorm:"column:prevStep"`
+    NewStep        uint8     `json:"newStep" gorm:"column:newStep"`
+    PrevRepeatTime time.Time `json:"prevRepeatTime" gorm:"column:prevRepeatTime"`
+    NewRepeatTime  time.Time `json:"newRepeatTime" gorm:"column:newRepeatTime"`
+    AnsweredAt     time.Time `json:"answeredAt" gorm:"column:answeredAt"`
+    // Время ответа в миллисекундах
+    ResponseTime int64 `json:"responseTime" gorm:"column:responseTime"`
+}
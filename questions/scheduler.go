@@ -0,0 +1,91 @@
+package questions
+
+import (
+    "time"
+
+    "github.com/pkg/errors"
+)
+
+const (
+    SchedulerLadder = "ladder"
+    SchedulerSm2    = "sm2"
+    SchedulerFsrs   = "fsrs"
+)
+
+// Оценка ответа на вопрос
+type Grade uint8
+
+const (
+    GradeAgain Grade = iota + 1
+    GradeHard
+    GradeGood
+    GradeEasy
+)
+
+var gradeNames = map[string]Grade{
+    "again": GradeAgain,
+    "hard":  GradeHard,
+    "good":  GradeGood,
+    "easy":  GradeEasy,
+}
+
+func ParseGrade(name string) (Grade, error) {
+    g, found := gradeNames[name]
+    if !found {
+        return 0, errors.Errorf("Unknown grade %q", name)
+    }
+    return g, nil
+}
+
+// Алгоритм интервальных повторений
+type Scheduler interface {
+    // Метод выставляет расписание новой карточки
+    Init(q *Question, now time.Time)
+    // Метод пересчитывает расписание карточки после ответа
+    Answer(q *Question, grade Grade, now time.Time)
+}
+
+// Выбор алгоритма повторений по имени, которое хранится в группе карточек
+type SchedulerResolver interface {
+    // Метод возвращает алгоритм по имени. Для пустого имени возвращается алгоритм по умолчанию
+    Resolve(name string) Scheduler
+}
+
+func NewScheduler(name string) (Scheduler, error) {
+    switch name {
+    case SchedulerLadder:
+        return &ladderScheduler{}, nil
+    case SchedulerSm2:
+        return &sm2Scheduler{}, nil
+    case SchedulerFsrs:
+        return newFsrsScheduler(), nil
+    default:
+        return nil, errors.Errorf("Unknown scheduler %q", name)
+    }
+}
+
+// Метод создает резолвер с алгоритмом деплоя по умолчанию
+func NewSchedulerResolver(defaultName string) (SchedulerResolver, error) {
+    def, err := NewScheduler(defaultName)
+    if err != nil {
+        return nil, errors.Wrap(err, "Can't create default scheduler")
+    }
+    return &schedulerResolver{def: def}, nil
+}
+
+type schedulerResolver struct {
+    def Scheduler
+}
+
+// Неизвестное имя тоже дает алгоритм по умолчанию: имя в группе проверяется при сохранении,
+// а карточки не должны перестать повторяться из-за устаревшего значения
+func (r *schedulerResolver) Resolve(name string) Scheduler {
+    if name == "" {
+        return r.def
+    }
+    s, err := NewScheduler(name)
+    if err != nil {
+        return r.def
+    }
+    return s
+}
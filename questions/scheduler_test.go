@@ -0,0 +1,60 @@
+package questions
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func Test_new_scheduler_return_implementation_by_name(t *testing.T) {
+    ladder, errLadder := NewScheduler(SchedulerLadder)
+    sm2, errSm2 := NewScheduler(SchedulerSm2)
+    fsrs, errFsrs := NewScheduler(SchedulerFsrs)
+
+    require.Nil(t, errLadder, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errSm2, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errFsrs, "Возвращаемая ошибка должна быть пустой")
+    assert.IsType(t, &ladderScheduler{}, ladder, "Для ladder должен вернуться алгоритм с лестницей интервалов")
+    assert.IsType(t, &sm2Scheduler{}, sm2, "Для sm2 должен вернуться алгоритм SM-2")
+    assert.IsType(t, &fsrsScheduler{}, fsrs, "Для fsrs должен вернуться алгоритм FSRS")
+}
+
+func Test_new_scheduler_when_name_is_unknown_result_error_not_empty(t *testing.T) {
+    _, errResult := NewScheduler("unknown")
+
+    assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
+
+func Test_scheduler_resolver_return_scheduler_by_name_or_default(t *testing.T) {
+    r, err := NewSchedulerResolver(SchedulerLadder)
+
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    assert.IsType(t, &ladderScheduler{}, r.Resolve(""), "Для пустого имени должен вернуться алгоритм по умолчанию")
+    assert.IsType(t, &sm2Scheduler{}, r.Resolve(SchedulerSm2), "Для имени должен вернуться алгоритм с этим именем")
+    assert.IsType(t, &ladderScheduler{}, r.Resolve("unknown"), "Для неизвестного имени должен вернуться алгоритм по умолчанию")
+}
+
+func Test_new_scheduler_resolver_when_default_scheduler_is_unknown_result_error_not_empty(t *testing.T) {
+    _, errResult := NewSchedulerResolver("unknown")
+
+    assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
+
+func Test_parse_grade_return_grade_by_name(t *testing.T) {
+    expected := map[string]Grade{
+        "again": GradeAgain,
+        "hard":  GradeHard,
+        "good":  GradeGood,
+        "easy":  GradeEasy,
+    }
+
+    for name, grade := range expected {
+        g, err := ParseGrade(name)
+        assert.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+        assert.Equal(t, grade, g, "Оценка %s неверная", name)
+    }
+
+    _, errResult := ParseGrade("unknown")
+    assert.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+}
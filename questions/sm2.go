@@ -0,0 +1,75 @@
+package questions
+
+import (
+    "math"
+    "time"
+)
+
+const (
+    sm2DefaultEase = 2.5
+    sm2MinEase     = 1.3
+
+    // Минимальная оценка по шкале SM-2 (0-5), при которой вопрос считается вспомненным
+    sm2PassQuality = 3
+)
+
+// Соответствие оценок шкале качества SM-2
+var sm2Quality = map[Grade]float64{
+    GradeAgain: 1,
+    GradeHard:  3,
+    GradeGood:  4,
+    GradeEasy:  5,
+}
+
+// Алгоритм SuperMemo 2: интервал растет с коэффициентом легкости,
+// который подстраивается под каждую карточку.
+// Step хранит номер повторения, начиная с 1 для новой карточки
+type sm2Scheduler struct{}
+
+func (s *sm2Scheduler) Init(q *Question, now time.Time) {
+    q.Step = 1
+    q.Ease = sm2DefaultEase
+    q.Interval = 0
+    q.RepeatTime = now
+    q.IsFailed = false
+}
+
+func (s *sm2Scheduler) Answer(q *Question, grade Grade, now time.Time) {
+    ease := q.Ease
+    if ease == 0 {
+        ease = sm2DefaultEase
+    }
+
+    quality, found := sm2Quality[grade]
+    if !found {
+        quality = sm2Quality[GradeAgain]
+    }
+
+    if quality >= sm2PassQuality {
+        switch {
+        case q.Step <= 1:
+            q.Interval = 1
+        // Карточки, которые раньше вел другой алгоритм, приходят без интервала,
+        // и умножение на легкость оставило бы их к повторению навсегда
+        case q.Step == 2 || q.Interval == 0:
+            q.Interval = 6
+        default:
+            q.Interval = uint32(math.Round(float64(q.Interval) * ease))
+        }
+        if q.Step < math.MaxUint8 {
+            q.Step++
+        }
+        q.IsFailed = false
+    } else {
+        q.Step = 1
+        q.Interval = 1
+        q.IsFailed = true
+    }
+
+    ease += 0.1 - (5-quality)*(0.08+(5-quality)*0.02)
+    if ease < sm2MinEase {
+        ease = sm2MinEase
+    }
+    q.Ease = ease
+    q.RepeatTime = now.Add(time.Hour * 24 * time.Duration(q.Interval))
+}
@@ -0,0 +1,94 @@
+package questions
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_sm2_init_set_default_ease_and_due_now(t *testing.T) {
+    now := time.Now()
+    q := &Question{Step: 5, Ease: 1.5, Interval: 10, IsFailed: true}
+
+    (&sm2Scheduler{}).Init(q, now)
+
+    assert.Equal(t, uint8(1), q.Step, "Step должен быть 1")
+    assert.Equal(t, sm2DefaultEase, q.Ease, "Ease должен быть значением по умолчанию")
+    assert.Equal(t, uint32(0), q.Interval, "Interval должен быть 0")
+    assert.Equal(t, false, q.IsFailed, "Флаг IsFailed должен быть false")
+    assert.Equal(t, now, q.RepeatTime, "Новая карточка должна быть доступна к повторению сразу")
+}
+
+func Test_sm2_answer_when_good_intervals_are_1_6_and_then_multiplied_by_ease(t *testing.T) {
+    now := time.Now()
+    q := &Question{}
+    s := &sm2Scheduler{}
+    s.Init(q, now)
+
+    s.Answer(q, GradeGood, now)
+    assert.Equal(t, uint32(1), q.Interval, "Первый интервал должен быть 1 день")
+    assert.Equal(t, now.Add(time.Hour*24), q.RepeatTime, "RepeatTime должно быть +1 день от текущего времени")
+
+    s.Answer(q, GradeGood, now)
+    assert.Equal(t, uint32(6), q.Interval, "Второй интервал должен быть 6 дней")
+
+    s.Answer(q, GradeGood, now)
+    assert.Equal(t, uint32(15), q.Interval, "Третий интервал должен быть 6 * 2.5 дней")
+    assert.Equal(t, uint8(4), q.Step, "Step должен расти с каждым успешным повторением")
+}
+
+func Test_sm2_answer_when_card_has_no_interval_after_other_scheduler_interval_grows(t *testing.T) {
+    now := time.Now()
+    q := &Question{Step: 4}
+    s := &sm2Scheduler{}
+
+    s.Answer(q, GradeGood, now)
+    assert.Equal(t, uint32(6), q.Interval, "Карточка без интервала должна получить второй интервал SM-2")
+
+    s.Answer(q, GradeGood, now)
+    assert.Equal(t, uint32(15), q.Interval, "Следующий интервал должен умножаться на легкость")
+}
+
+func Test_sm2_answer_when_again_restart_repetitions_and_decrease_ease(t *testing.T) {
+    now := time.Now()
+    q := &Question{Step: 4, Ease: sm2DefaultEase, Interval: 15}
+
+    (&sm2Scheduler{}).Answer(q, GradeAgain, now)
+
+    assert.Equal(t, uint8(1), q.Step, "Step должен быть 1")
+    assert.Equal(t, uint32(1), q.Interval, "Interval должен быть 1 день")
+    assert.Equal(t, true, q.IsFailed, "Флаг IsFailed должен быть true")
+    assert.InDelta(t, 1.96, q.Ease, 0.0001, "Ease должен уменьшиться")
+}
+
+func Test_sm2_answer_ease_is_not_less_then_minimum(t *testing.T) {
+    q := &Question{Step: 2, Ease: sm2MinEase}
+
+    (&sm2Scheduler{}).Answer(q, GradeAgain, time.Now())
+
+    assert.Equal(t, sm2MinEase, q.Ease, "Ease не должен быть меньше минимального")
+}
+
+func Test_sm2_answer_when_ease_is_empty_use_default(t *testing.T) {
+    q := &Question{Step: 3, Interval: 6}
+
+    (&sm2Scheduler{}).Answer(q, GradeGood, time.Now())
+
+    assert.Equal(t, uint32(15), q.Interval, "Для пустого Ease должен использоваться коэффициент по умолчанию")
+}
+
+func Test_sm2_answer_ease_changes_by_grade(t *testing.T) {
+    expected := map[Grade]float64{
+        GradeHard: 2.36,
+        GradeGood: 2.5,
+        GradeEasy: 2.6,
+    }
+
+    for grade, ease := range expected {
+        q := &Question{Step: 3, Ease: sm2DefaultEase, Interval: 6}
+        (&sm2Scheduler{}).Answer(q, grade, time.Now())
+        assert.InDelta(t, ease, q.Ease, 0.0001, "Ease после оценки %d неверный", grade)
+        assert.Equal(t, false, q.IsFailed, "Флаг IsFailed должен быть false после оценки %d", grade)
+    }
+}
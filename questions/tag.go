@@ -0,0 +1,43 @@
+package questions
+
+import (
+    "encoding/json"
+    "strings"
+)
+
+// Метка вопроса. В отличие от групп метки не образуют дерево,
+// и у вопроса их может быть несколько, например "exam-2026" и "weak"
+type Tag struct {
+    ID     uint64 `gorm:"primaryKey"`
+    UserId uint64 `gorm:"column:userId;uniqueIndex:idx_tags_user_name"`
+    Name   string `gorm:"uniqueIndex:idx_tags_user_name"`
+}
+
+// В API метка передается своим именем
+func (t Tag) MarshalJSON() ([]byte, error) {
+    return json.Marshal(t.Name)
+}
+
+// Метод создает метки по именам. Пробелы внутри имени заменяются на "_",
+// как в Anki, где пробел разделяет метки. Пустые имена и повторы пропускаются
+func NewTags(names []string) []Tag {
+    tags := []Tag{}
+    seen := map[string]bool{}
+    for _, name := range names {
+        name = strings.Join(strings.Fields(name), "_")
+        if name == "" || seen[name] {
+            continue
+        }
+        seen[name] = true
+        tags = append(tags, Tag{Name: name})
+    }
+    return tags
+}
+
+func TagNames(tags []Tag) []string {
+    names := make([]string, 0, len(tags))
+    for _, t := range tags {
+        names = append(names, t.Name)
+    }
+    return names
+}
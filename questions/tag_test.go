@@ -0,0 +1,28 @@
+package questions
+
+import (
+    "encoding/json"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func Test_new_tags_normalize_names_and_skip_empty_and_duplicates(t *testing.T) {
+    tags := NewTags([]string{"weak", " exam  2026 ", "", "weak", "exam_2026"})
+
+    assert.Equal(t, []Tag{{Name: "weak"}, {Name: "exam_2026"}}, tags, "Метки должны быть без пробелов, пустых имен и повторов")
+}
+
+func Test_tag_names_return_names_in_same_order(t *testing.T) {
+    names := TagNames([]Tag{{ID: 2, Name: "weak"}, {ID: 1, Name: "exam"}})
+
+    assert.Equal(t, []string{"weak", "exam"}, names, "Имена меток неверные")
+}
+
+func Test_question_tags_marshal_as_names(t *testing.T) {
+    data, err := json.Marshal(Question{ID: 1, Tags: []Tag{{ID: 2, UserId: 3, Name: "weak"}}})
+
+    require.Nil(t, err, "Возвращаемая ошибка должна быть пустой")
+    assert.Contains(t, string(data), `"tags":["weak"]`, "Метки вопроса должны передаваться именами")
+}
@@ -1,6 +1,7 @@
 package questions
 
 import (
+    "context"
     "time"
 
     "github.com/golobby/container"
@@ -8,66 +9,469 @@ import (
 )
 
 type Usecase interface {
-    Add(q *Question) error
-    Correct(q *Question) error
-    Delete(conds []interface{}) error
-    Find(conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]Question, more bool, err error)
+    Add(ctx context.Context, q *Question) error
+    Import(ctx context.Context, ql []*Question) ([]error, error)
+    Batch(ctx context.Context, userId uint64, ops []BatchOperation) ([]BatchResult, error)
+    Correct(ctx context.Context, q *Question) error
+    Patch(ctx context.Context, q *Question, fields []string) error
+    Delete(ctx context.Context, query *Query) error
+    Trash(ctx context.Context, query *Query) (list *[]Question, more bool, err error)
+    Restore(ctx context.Context, q *Question) error
+    Purge(ctx context.Context, retention time.Duration) (int64, error)
+    Answer(ctx context.Context, id, version uint64, grade Grade, responseTime time.Duration) (*Question, error)
+    Find(ctx context.Context, query *Query) (list *[]Question, more bool, err error)
+    Due(ctx context.Context, query *Query) (list *[]Question, more bool, err error)
+    Search(ctx context.Context, text string, query *Query) (list *[]Question, more bool, err error)
+    CountByGroup(ctx context.Context, query *Query) (*[]GroupCount, error)
+    DescendantGroups(ctx context.Context, groupIds []uint64, userId uint64) ([]uint64, error)
+    GroupNames(ctx context.Context, groupIds []uint64, userId uint64) (map[uint64]string, error)
+    EnsureGroups(ctx context.Context, names []string, userId uint64) (ids map[string]uint64, created []uint64, err error)
+    RemoveGroups(ctx context.Context, groupIds []uint64, userId uint64) error
+}
+
+// Поля расписания, которые меняет алгоритм повторений
+var scheduleFields = []string{
+    questionStep,
+    QuestionRepeatTime,
+    questionIsFailed,
+    questionEase,
+    questionInterval,
+    questionStability,
+    questionDifficulty,
+    questionLastReview,
 }
 
 type usecase struct {
-    dao Dao
-    now time.Time
+    dao        Dao
+    reviewDao  ReviewDao
+    groups     Groups
+    schedulers SchedulerResolver
+    now        time.Time
 }
 
-func (u *usecase) Add(q *Question) error {
-    originalStep := q.Step
-    originalRepeatTime := q.RepeatTime
-    originalIsFailed := q.IsFailed
+// Метод добавляет вопрос в группу пользователя.
+// Если группа не найдена или принадлежит другому пользователю, возвращается ErrGroupNotFound
+func (u *usecase) Add(ctx context.Context, q *Question) error {
+    return u.add(ctx, q, true)
+}
+
+// Метод добавляет вопрос, при initSchedule заполняя расписание алгоритмом группы
+func (u *usecase) add(ctx context.Context, q *Question, initSchedule bool) error {
+    err := u.checkGroup(ctx, q)
+    if err != nil {
+        return err
+    }
 
-    q.Step = 1
-    q.RepeatTime = u.getRepeatTime(q.Step)
-    q.IsFailed = false
+    original := *q
+    if initSchedule {
+        scheduler, err := u.getScheduler(ctx, q)
+        if err != nil {
+            return err
+        }
+        scheduler.Init(q, u.getNow())
+    }
 
     dao := u.getDao()
-    err := dao.Create(q)
+    err = dao.Create(ctx, q)
     if err != nil {
-        q.Step = originalStep
-        q.RepeatTime = originalRepeatTime
-        q.IsFailed = originalIsFailed
+        *q = original
         return errors.Wrap(err, "Can't create question via dao")
     }
 
     return nil
 }
 
-func (u *usecase) Correct(q *Question) error {
+// Метод добавляет вопросы в одной транзакции.
+// Вопросы с чужой или несуществующей группой пропускаются, и для них в результате
+// возвращается ErrGroupNotFound под тем же индексом. Ошибка хранилища откатывает весь импорт.
+// Расписание вопросов с заполненным временем повторения, например карточек из Anki, сохраняется
+func (u *usecase) Import(ctx context.Context, ql []*Question) ([]error, error) {
+    rowErrs := make([]error, len(ql))
+    err := u.getDao().WithTx(ctx, func(dao Dao, reviewDao ReviewDao) error {
+        txUsecase := u.withTx(dao, reviewDao)
+        for i, q := range ql {
+            err := txUsecase.add(ctx, q, q.RepeatTime.IsZero())
+            if errors.Is(err, ErrGroupNotFound) {
+                rowErrs[i] = err
+                continue
+            }
+            if err != nil {
+                return errors.Wrapf(err, "Can't add question %d", i)
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, errors.Wrap(err, "Can't import questions in transaction via dao")
+    }
+    return rowErrs, nil
+}
+
+// Метод выполняет операции пользователя в одной транзакции и возвращает результат каждой
+// операции под тем же индексом. Операция над чужим или несуществующим вопросом, с чужой группой
+// или с другой версией вопроса пропускается, а ошибка хранилища откатывает весь пакет
+func (u *usecase) Batch(ctx context.Context, userId uint64, ops []BatchOperation) ([]BatchResult, error) {
+    results := make([]BatchResult, len(ops))
+    err := u.getDao().WithTx(ctx, func(dao Dao, reviewDao ReviewDao) error {
+        txUsecase := u.withTx(dao, reviewDao)
+        for i, op := range ops {
+            q, err := txUsecase.batch(ctx, userId, op)
+            if isBatchItemError(err) {
+                results[i].Err = err
+                continue
+            }
+            if err != nil {
+                return errors.Wrapf(err, "Can't %s question in operation %d", op.Action, i)
+            }
+            results[i].Question = q
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, errors.Wrap(err, "Can't execute batch in transaction via dao")
+    }
+    return results, nil
+}
+
+func (u *usecase) batch(ctx context.Context, userId uint64, op BatchOperation) (*Question, error) {
+    if op.Action == BatchAdd {
+        q := op.Question
+        q.UserId = userId
+        err := u.add(ctx, q, true)
+        if err != nil {
+            return nil, err
+        }
+        return q, nil
+    }
+
+    q, err := u.findOwned(ctx, op.ID, userId)
+    if err != nil {
+        return nil, err
+    }
+    if op.Version != 0 && q.Version != op.Version {
+        return nil, errors.Wrapf(ErrVersionConflict, "Question with id %d has version %d instead of %d", q.ID, q.Version, op.Version)
+    }
+
+    switch op.Action {
+    case BatchCorrect:
+        q.Title = op.Question.Title
+        q.Body = op.Question.Body
+        q.GroupId = op.Question.GroupId
+        if op.Question.Tags != nil {
+            q.Tags = op.Question.Tags
+        }
+        err = u.Correct(ctx, q)
+        if err != nil {
+            return nil, err
+        }
+        return q, nil
+    case BatchDelete:
+        return nil, u.Delete(ctx, NewQuery().Eq(FieldId, q.ID).Eq(FieldUserId, userId).Eq(FieldVersion, q.Version))
+    case BatchAnswer:
+        return u.answer(ctx, q.ID, q.Version, op.Grade, op.ResponseTime)
+    }
+    return nil, errors.Errorf("Unknown batch action %q", op.Action)
+}
+
+// Метод изменяет вопрос. Метки заменяются, только если они переданы:
+// nil оставляет метки вопроса, пустой список удаляет их
+func (u *usecase) Correct(ctx context.Context, q *Question) error {
+    err := u.checkGroup(ctx, q)
+    if err != nil {
+        return err
+    }
+
     dao := u.getDao()
-    fields := []string{QuestionGroupId, questionTitle, questionBody}
-    err := dao.Update(q, fields)
+    fields := []string{QuestionGroupId, QuestionTitle, QuestionBody}
+    if q.Tags != nil {
+        fields = append(fields, QuestionTags)
+    }
+    err = dao.Update(ctx, q, fields)
     if err != nil {
         return errors.Wrap(err, "Can't update question via dao")
     }
     return nil
 }
 
-func (u *usecase) Delete(conds []interface{}) error {
+// Метод изменяет только поля fields вопроса из EditableFields, остальные поля в хранилище
+// не меняются. Группа проверяется, только если она среди изменяемых полей
+func (u *usecase) Patch(ctx context.Context, q *Question, fields []string) error {
+    checkGroup := false
+    for _, field := range fields {
+        if !IsEditable(field) {
+            return errors.Wrapf(ErrFieldNotEditable, "Field %q", field)
+        }
+        if field == QuestionGroupId {
+            checkGroup = true
+        }
+    }
+
+    if checkGroup {
+        err := u.checkGroup(ctx, q)
+        if err != nil {
+            return err
+        }
+    }
+
+    if len(fields) == 0 {
+        return nil
+    }
+
+    err := u.getDao().Update(ctx, q, fields)
+    if err != nil {
+        return errors.Wrapf(err, "Can't update fields %v of question via dao", fields)
+    }
+    return nil
+}
+
+func (u *usecase) Delete(ctx context.Context, query *Query) error {
     dao := u.getDao()
-    err := dao.Delete(conds...)
+    err := dao.Delete(ctx, query)
     if err != nil {
-        return errors.Wrapf(err, "Can't delete question via dao by conds %v", conds)
+        return errors.Wrapf(err, "Can't delete question via dao by query %v", *query)
     }
     return nil
 }
 
-func (u *usecase) Find(conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]Question, more bool, err error) {
+// Метод возвращает вопросы запроса из корзины, начиная с последних удаленных.
+// Сортировка запроса заменяется
+func (u *usecase) Trash(ctx context.Context, query *Query) (list *[]Question, more bool, err error) {
     dao := u.getDao()
-    list, more, err = dao.Find(conds, order, limit, offset)
+    trash := query.Clone()
+    trash.Sort = nil
+    trash.OrderBy(FieldDeletedAt, true).OrderBy(FieldId, true)
+
+    list, more, err = dao.FindDeleted(ctx, trash)
     if err != nil {
-        return list, more, errors.Wrapf(err, "Can't delete question via dao by conds %v", conds)
+        return list, more, errors.Wrapf(err, "Can't find deleted questions via dao by query %v", *query)
     }
     return list, more, err
 }
 
+// Метод возвращает вопрос из корзины вместе с его группой, если ее удалили вместе с вопросом.
+// Если группы нет и в корзине, возвращается ErrGroupNotFound, и вопрос остается в корзине.
+// Вопросы и группы хранятся раздельно, поэтому вопрос восстанавливается первым,
+// а если группу вернуть не удалось, вопрос снова переносится в корзину
+func (u *usecase) Restore(ctx context.Context, q *Question) error {
+    groups := u.getGroups()
+    exists, err := groups.Exists(ctx, q.GroupId, q.UserId)
+    if err != nil {
+        return errors.Wrapf(err, "Can't check group %d of question", q.GroupId)
+    }
+    if !exists {
+        return errors.Wrapf(ErrGroupNotFound, "Group %d is not owned by user %d", q.GroupId, q.UserId)
+    }
+
+    dao := u.getDao()
+    err = dao.Restore(ctx, q)
+    if err != nil {
+        return errors.Wrapf(err, "Can't restore question with id %d via dao", q.ID)
+    }
+
+    found, err := groups.Restore(ctx, q.GroupId, q.UserId)
+    if err == nil && !found {
+        err = errors.Wrapf(ErrGroupNotFound, "Group %d is not owned by user %d", q.GroupId, q.UserId)
+    }
+    if err != nil {
+        err = errors.Wrapf(err, "Can't restore group %d of question", q.GroupId)
+        query := NewQuery().Eq(FieldId, q.ID).Eq(FieldUserId, q.UserId).Eq(FieldVersion, q.Version)
+        if deleteErr := dao.Delete(ctx, query); deleteErr != nil {
+            return errors.Wrapf(err, "Can't move question with id %d back to trash via dao: %v", q.ID, deleteErr)
+        }
+        return err
+    }
+    return nil
+}
+
+// Метод окончательно удаляет вопросы, которые пролежали в корзине дольше retention
+func (u *usecase) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+    deletedBefore := u.getNow().Add(-retention)
+    count, err := u.getDao().Purge(ctx, deletedBefore)
+    if err != nil {
+        return 0, errors.Wrapf(err, "Can't purge questions deleted before %v via dao", deletedBefore)
+    }
+    return count, nil
+}
+
+// Метод пересчитывает расписание карточки алгоритмом, выбранным для ее группы,
+// и записывает ответ в историю повторений в одной транзакции. Если version не 0,
+// а версия вопроса другая, возвращается ErrVersionConflict
+func (u *usecase) Answer(ctx context.Context, id, version uint64, grade Grade, responseTime time.Duration) (*Question, error) {
+    var q *Question
+    err := u.getDao().WithTx(ctx, func(dao Dao, reviewDao ReviewDao) error {
+        var err error
+        q, err = u.withTx(dao, reviewDao).answer(ctx, id, version, grade, responseTime)
+        return err
+    })
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't answer question with id %d in transaction via dao", id)
+    }
+    return q, nil
+}
+
+func (u *usecase) answer(ctx context.Context, id, version uint64, grade Grade, responseTime time.Duration) (*Question, error) {
+    dao := u.getDao()
+    ql, _, err := dao.Find(ctx, NewQuery().Eq(FieldId, id).Paginate(1, 0))
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't find question by id %d via dao", id)
+    }
+
+    if len(*ql) == 0 {
+        return nil, nil
+    }
+
+    q := &(*ql)[0]
+    if version != 0 && q.Version != version {
+        return nil, errors.Wrapf(ErrVersionConflict, "Question with id %d has version %d instead of %d", id, q.Version, version)
+    }
+
+    now := u.getNow()
+    r := &Review{
+        QuestionId:     q.ID,
+        UserId:         q.UserId,
+        Grade:          grade,
+        PrevStep:       q.Step,
+        PrevRepeatTime: q.RepeatTime,
+        AnsweredAt:     now,
+        ResponseTime:   responseTime.Milliseconds(),
+    }
+
+    scheduler, err := u.getScheduler(ctx, q)
+    if err != nil {
+        return nil, err
+    }
+    scheduler.Answer(q, grade, now)
+
+    err = dao.Update(ctx, q, scheduleFields)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't update question schedule with id %d via dao", id)
+    }
+
+    r.NewStep = q.Step
+    r.NewRepeatTime = q.RepeatTime
+    err = u.getReviewDao().Create(ctx, r)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't create review for question with id %d via dao", id)
+    }
+
+    return q, nil
+}
+
+func (u *usecase) Find(ctx context.Context, query *Query) (list *[]Question, more bool, err error) {
+    dao := u.getDao()
+    list, more, err = dao.Find(ctx, query)
+    if err != nil {
+        return list, more, errors.Wrapf(err, "Can't find questions via dao by query %v", *query)
+    }
+    return list, more, err
+}
+
+// Метод возвращает вопросы запроса, время повторения которых уже наступило,
+// начиная с самых просроченных. Сортировка запроса заменяется
+func (u *usecase) Due(ctx context.Context, query *Query) (list *[]Question, more bool, err error) {
+    dao := u.getDao()
+    now := u.getNow()
+    due := query.Clone().Lte(FieldRepeatTime, now)
+    due.Sort = nil
+    due.OrderBy(FieldRepeatTime, false).OrderBy(FieldId, false)
+
+    list, more, err = dao.Find(ctx, due)
+    if err != nil {
+        return list, more, errors.Wrapf(err, "Can't find due questions via dao by query %v before %v", *query, now)
+    }
+    return list, more, err
+}
+
+// Метод ищет вопросы по тексту вопроса и ответа, например чтобы найти уже
+// записанную карточку перед добавлением дубликата
+func (u *usecase) Search(ctx context.Context, text string, query *Query) (list *[]Question, more bool, err error) {
+    dao := u.getDao()
+    list, more, err = dao.Search(ctx, text, query)
+    if err != nil {
+        return list, more, errors.Wrapf(err, "Can't search questions via dao by text %q and query %v", text, *query)
+    }
+    return list, more, err
+}
+
+// Метод возвращает количество всех вопросов и вопросов к повторению по группам
+func (u *usecase) CountByGroup(ctx context.Context, query *Query) (*[]GroupCount, error) {
+    dao := u.getDao()
+    counts, err := dao.CountByGroup(ctx, query, u.getNow())
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't count questions via dao by query %v", *query)
+    }
+    return counts, nil
+}
+
+// Метод дополняет список групп всеми вложенными в них группами,
+// чтобы выбирать вопросы по всему поддереву
+func (u *usecase) DescendantGroups(ctx context.Context, groupIds []uint64, userId uint64) ([]uint64, error) {
+    ids, err := u.getGroups().Descendants(ctx, groupIds, userId)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't get descendants of groups %v", groupIds)
+    }
+    return ids, nil
+}
+
+func (u *usecase) GroupNames(ctx context.Context, groupIds []uint64, userId uint64) (map[uint64]string, error) {
+    names, err := u.getGroups().Names(ctx, groupIds, userId)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't get names of groups %v", groupIds)
+    }
+    return names, nil
+}
+
+// Метод возвращает id групп пользователя по полным именам, создавая недостающие группы,
+// и id созданных групп
+func (u *usecase) EnsureGroups(ctx context.Context, names []string, userId uint64) (map[string]uint64, []uint64, error) {
+    ids, created, err := u.getGroups().Ensure(ctx, names, userId)
+    if err != nil {
+        return nil, nil, errors.Wrapf(err, "Can't ensure groups %v", names)
+    }
+    return ids, created, nil
+}
+
+// Метод окончательно удаляет группы пользователя, созданные через EnsureGroups,
+// если вопросы в них так и не добавились
+func (u *usecase) RemoveGroups(ctx context.Context, groupIds []uint64, userId uint64) error {
+    err := u.getGroups().Remove(ctx, groupIds, userId)
+    if err != nil {
+        return errors.Wrapf(err, "Can't remove groups %v", groupIds)
+    }
+    return nil
+}
+
+// Метод возвращает вопрос пользователя по id или ErrQuestionNotFound
+func (u *usecase) findOwned(ctx context.Context, id, userId uint64) (*Question, error) {
+    ql, _, err := u.getDao().Find(ctx, NewQuery().Eq(FieldId, id).Eq(FieldUserId, userId).Paginate(1, 0))
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't find question by id %d via dao", id)
+    }
+    if len(*ql) == 0 {
+        return nil, errors.Wrapf(ErrQuestionNotFound, "Question with id %d is not owned by user %d", id, userId)
+    }
+    return &(*ql)[0], nil
+}
+
+func (u *usecase) checkGroup(ctx context.Context, q *Question) error {
+    owned, err := u.getGroups().IsOwned(ctx, q.GroupId, q.UserId)
+    if err != nil {
+        return errors.Wrapf(err, "Can't check group %d of question", q.GroupId)
+    }
+    if !owned {
+        return errors.Wrapf(ErrGroupNotFound, "Group %d is not owned by user %d", q.GroupId, q.UserId)
+    }
+    return nil
+}
+
+// Метод возвращает копию usecase, работающую через dao и историю повторений транзакции
+func (u *usecase) withTx(dao Dao, reviewDao ReviewDao) *usecase {
+    result := *u
+    result.dao = dao
+    result.reviewDao = reviewDao
+    return &result
+}
+
 func (u *usecase) getDao() Dao {
     if u.dao == nil {
         container.Make(&u.dao)
@@ -75,6 +479,33 @@ func (u *usecase) getDao() Dao {
     return u.dao
 }
 
+func (u *usecase) getReviewDao() ReviewDao {
+    if u.reviewDao == nil {
+        container.Make(&u.reviewDao)
+    }
+    return u.reviewDao
+}
+
+func (u *usecase) getGroups() Groups {
+    if u.groups == nil {
+        container.Make(&u.groups)
+    }
+    return u.groups
+}
+
+// Метод возвращает алгоритм повторений, выбранный в группе вопроса
+func (u *usecase) getScheduler(ctx context.Context, q *Question) (Scheduler, error) {
+    name, err := u.getGroups().Scheduler(ctx, q.GroupId, q.UserId)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't get scheduler of group %d", q.GroupId)
+    }
+
+    if u.schedulers == nil {
+        container.Make(&u.schedulers)
+    }
+    return u.schedulers.Resolve(name), nil
+}
+
 func (u *usecase) getNow() time.Time {
     var emptyTime time.Time
     if u.now == emptyTime {
@@ -82,17 +513,3 @@ func (u *usecase) getNow() time.Time {
     }
     return u.now
 }
-
-func (u *usecase) getRepeatTime(step uint8) time.Time {
-    now := u.getNow()
-    switch step {
-    case 1:
-        return now.Add(time.Minute * 30)
-    case 2:
-        return now.Add(time.Hour * 24 * 14)
-    case 3:
-        return now.Add(time.Hour * 24 * 60)
-    default:
-        return now.Add(time.Hour * 24 * 90)
-    }
-}
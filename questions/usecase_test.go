@@ -1,6 +1,7 @@
 package questions
 
 import (
+    "context"
     "testing"
     "time"
 
@@ -12,28 +13,126 @@ import (
 
 type daoMock struct {
     mock.Mock
+    // История повторений, которую dao передает в транзакцию
+    reviewDao ReviewDao
 }
 
-func (m *daoMock) Create(q *Question) error {
-    args := m.Called(q)
+func (m *daoMock) Create(ctx context.Context, q *Question) error {
+    args := m.Called(ctx, q)
     return args.Error(0)
 }
 
-func (m *daoMock) Update(q *Question, fields []string) error {
-    args := m.Called(q, fields)
+func (m *daoMock) Update(ctx context.Context, q *Question, fields []string) error {
+    args := m.Called(ctx, q, fields)
     return args.Error(0)
 }
 
-func (m *daoMock) Delete(conds ...interface{}) error {
-    args := m.Called(conds...)
+func (m *daoMock) Delete(ctx context.Context, query *Query) error {
+    args := m.Called(ctx, query)
     return args.Error(0)
 }
 
-func (m *daoMock) Find(conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]Question, more bool, err error) {
-    args := m.Called(conds, order, limit, offset)
+func (m *daoMock) Find(ctx context.Context, query *Query) (list *[]Question, more bool, err error) {
+    args := m.Called(ctx, query)
     return args.Get(0).(*[]Question), args.Bool(1), args.Error(2)
 }
 
+func (m *daoMock) FindDeleted(ctx context.Context, query *Query) (list *[]Question, more bool, err error) {
+    args := m.Called(ctx, query)
+    return args.Get(0).(*[]Question), args.Bool(1), args.Error(2)
+}
+
+func (m *daoMock) Restore(ctx context.Context, q *Question) error {
+    args := m.Called(ctx, q)
+    return args.Error(0)
+}
+
+func (m *daoMock) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+    args := m.Called(ctx, deletedBefore)
+    return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *daoMock) Search(ctx context.Context, text string, query *Query) (list *[]Question, more bool, err error) {
+    args := m.Called(ctx, text, query)
+    return args.Get(0).(*[]Question), args.Bool(1), args.Error(2)
+}
+
+func (m *daoMock) CountByGroup(ctx context.Context, query *Query, dueBefore time.Time) (*[]GroupCount, error) {
+    args := m.Called(ctx, query, dueBefore)
+    return args.Get(0).(*[]GroupCount), args.Error(1)
+}
+
+// Транзакция в моке выполняется на этом же dao
+func (m *daoMock) WithTx(ctx context.Context, fc func(dao Dao, reviewDao ReviewDao) error) error {
+    m.Called(ctx)
+    return fc(m, m.reviewDao)
+}
+
+type groupsMock struct {
+    mock.Mock
+}
+
+func (m *groupsMock) IsOwned(ctx context.Context, groupId, userId uint64) (bool, error) {
+    args := m.Called(ctx, groupId, userId)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *groupsMock) Descendants(ctx context.Context, groupIds []uint64, userId uint64) ([]uint64, error) {
+    args := m.Called(ctx, groupIds, userId)
+    return args.Get(0).([]uint64), args.Error(1)
+}
+
+func (m *groupsMock) Names(ctx context.Context, groupIds []uint64, userId uint64) (map[uint64]string, error) {
+    args := m.Called(ctx, groupIds, userId)
+    return args.Get(0).(map[uint64]string), args.Error(1)
+}
+
+func (m *groupsMock) Ensure(ctx context.Context, names []string, userId uint64) (map[string]uint64, []uint64, error) {
+    args := m.Called(ctx, names, userId)
+    return args.Get(0).(map[string]uint64), args.Get(1).([]uint64), args.Error(2)
+}
+
+func (m *groupsMock) Remove(ctx context.Context, groupIds []uint64, userId uint64) error {
+    args := m.Called(ctx, groupIds, userId)
+    return args.Error(0)
+}
+
+func (m *groupsMock) Scheduler(ctx context.Context, groupId, userId uint64) (string, error) {
+    args := m.Called(ctx, groupId, userId)
+    return args.String(0), args.Error(1)
+}
+
+func (m *groupsMock) Exists(ctx context.Context, groupId, userId uint64) (bool, error) {
+    args := m.Called(ctx, groupId, userId)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *groupsMock) Restore(ctx context.Context, groupId, userId uint64) (bool, error) {
+    args := m.Called(ctx, groupId, userId)
+    return args.Bool(0), args.Error(1)
+}
+
+func ownedGroups() *groupsMock {
+    groups := &groupsMock{}
+    groups.On("IsOwned", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+    groups.On("Scheduler", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+    return groups
+}
+
+type reviewDaoMock struct {
+    mock.Mock
+}
+
+func (m *reviewDaoMock) Create(ctx context.Context, r *Review) error {
+    args := m.Called(ctx, r)
+    return args.Error(0)
+}
+
+func (m *reviewDaoMock) Find(ctx context.Context, query *Query) (list *[]Review, more bool, err error) {
+    args := m.Called(ctx, query)
+    return args.Get(0).(*[]Review), args.Bool(1), args.Error(2)
+}
+
 // -------------
 // ---- Add ----
 // -------------
@@ -42,10 +141,10 @@ func Test_usecase_add_dao_calls_is_correct(t *testing.T) {
     qIn := &Question{}
 
     dao := &daoMock{}
-    dao.On("Create", qIn).Return(nil)
-    u := usecase{dao: dao}
+    dao.On("Create", mock.Anything, qIn).Return(nil)
+    u := usecase{dao: dao, groups: ownedGroups()}
 
-    _ = u.Add(qIn)
+    _ = u.Add(context.Background(), qIn)
 
     createCalls := 1
     if !dao.AssertNumberOfCalls(t, "Create", createCalls) {
@@ -58,10 +157,10 @@ func Test_usecase_add_when_dao_work_success_result_error_is_empty(t *testing.T)
     qIn := &Question{}
 
     dao := &daoMock{}
-    dao.On("Create", qIn).Return(nil)
-    u := usecase{dao: dao}
+    dao.On("Create", mock.Anything, qIn).Return(nil)
+    u := usecase{dao: dao, groups: ownedGroups()}
 
-    errResult := u.Add(qIn)
+    errResult := u.Add(context.Background(), qIn)
 
     assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
 }
@@ -71,10 +170,10 @@ func Test_usecase_add_dao_work_wrong_result_error_not_empty_and_have_info_from_d
     daoErr := errors.New("Dao mock error")
 
     dao := &daoMock{}
-    dao.On("Create", qIn).Return(daoErr)
-    u := usecase{dao: dao}
+    dao.On("Create", mock.Anything, qIn).Return(daoErr)
+    u := usecase{dao: dao, groups: ownedGroups()}
 
-    errResult := u.Add(qIn)
+    errResult := u.Add(context.Background(), qIn)
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
     require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
@@ -89,13 +188,14 @@ func Test_usecase_add_dao_set_correct_default_values_for_question(t *testing.T)
     }
 
     dao := &daoMock{}
-    dao.On("Create", qIn).Return(nil)
+    dao.On("Create", mock.Anything, qIn).Return(nil)
     u := usecase{
-        dao: dao,
-        now: now,
+        dao:    dao,
+        groups: ownedGroups(),
+        now:    now,
     }
 
-    _ = u.Add(qIn)
+    _ = u.Add(context.Background(), qIn)
 
     assert.Equal(t, uint8(1), qIn.Step, "Step должен быть 1")
     assert.Equal(t, false, qIn.IsFailed, "Флаг IsFailed должен быть false")
@@ -112,13 +212,14 @@ func Test_usecase_add_when_dao_work_wrong_set_original_values_for_question(t *te
     }
 
     dao := &daoMock{}
-    dao.On("Create", qIn).Return(daoErr)
+    dao.On("Create", mock.Anything, qIn).Return(daoErr)
     u := usecase{
-        dao: dao,
-        now: now,
+        dao:    dao,
+        groups: ownedGroups(),
+        now:    now,
     }
 
-    _ = u.Add(qIn)
+    _ = u.Add(context.Background(), qIn)
 
     assert.Equal(t, uint8(255), qIn.Step, "Step должен быть 1")
     assert.Equal(t, true, qIn.IsFailed, "Флаг IsFailed должен быть false")
@@ -129,31 +230,257 @@ func Test_usecase_add_when_dao_work_success_result_question_contains_data_from_d
     qIn := &Question{ID: 0}
 
     dao := &daoMock{}
-    dao.On("Create", qIn).Return(nil).Run(func(args mock.Arguments) {
-        qOut := args.Get(0).(*Question)
+    dao.On("Create", mock.Anything, qIn).Return(nil).Run(func(args mock.Arguments) {
+        qOut := args.Get(1).(*Question)
         qOut.ID = 1
     })
-    u := usecase{dao: dao}
+    u := usecase{dao: dao, groups: ownedGroups()}
 
-    _ = u.Add(qIn)
+    _ = u.Add(context.Background(), qIn)
 
     assert.Equal(t, uint64(1), qIn.ID, "Результирующий объект question должен иметь изменения, внесенные в него в dao")
 }
 
+func Test_usecase_add_when_group_not_owned_result_error_is_group_not_found(t *testing.T) {
+    qIn := &Question{UserId: 1, GroupId: 2}
+
+    groups := &groupsMock{}
+    groups.On("IsOwned", mock.Anything, uint64(2), uint64(1)).Return(false, nil)
+    dao := &daoMock{}
+    u := usecase{dao: dao, groups: groups}
+
+    errResult := u.Add(context.Background(), qIn)
+
+    require.ErrorIs(t, errResult, ErrGroupNotFound, "Возвращаемая ошибка должна быть ErrGroupNotFound")
+    dao.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_usecase_add_groups_work_wrong_result_error_not_empty_and_have_info_from_groups(t *testing.T) {
+    qIn := &Question{UserId: 1, GroupId: 2}
+    groupsErr := errors.New("Groups mock error")
+
+    groups := &groupsMock{}
+    groups.On("IsOwned", mock.Anything, uint64(2), uint64(1)).Return(false, groupsErr)
+    u := usecase{dao: &daoMock{}, groups: groups}
+
+    errResult := u.Add(context.Background(), qIn)
+
+    require.ErrorIs(t, errResult, groupsErr, "Возвращаемая ошибка должна содержать информацию из groups")
+}
+
+// ----------------
+// ---- Import ----
+// ----------------
+
+func Test_usecase_import_create_questions_in_transaction(t *testing.T) {
+    ql := []*Question{{UserId: 1, GroupId: 2}, {UserId: 1, GroupId: 2}}
+
+    dao := &daoMock{}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    u := usecase{dao: dao, groups: ownedGroups()}
+
+    rowErrs, errResult := u.Import(context.Background(), ql)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []error{nil, nil}, rowErrs, "Ошибки строк должны быть пустыми")
+    dao.AssertNumberOfCalls(t, "WithTx", 1)
+    dao.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func Test_usecase_import_when_question_has_repeat_time_schedule_is_kept(t *testing.T) {
+    now := time.Now()
+    repeatTime := now.Add(time.Hour * 24 * 10)
+    qScheduled := &Question{UserId: 1, GroupId: 2, Step: 3, Interval: 10, RepeatTime: repeatTime}
+    qNew := &Question{UserId: 1, GroupId: 2}
+
+    dao := &daoMock{}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    u := usecase{dao: dao, groups: ownedGroups(), now: now}
+
+    _, errResult := u.Import(context.Background(), []*Question{qScheduled, qNew})
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, uint8(3), qScheduled.Step, "Step импортированного расписания должен сохраниться")
+    assert.Equal(t, repeatTime, qScheduled.RepeatTime, "RepeatTime импортированного расписания должно сохраниться")
+    assert.Equal(t, uint8(1), qNew.Step, "Для вопроса без расписания Step должен быть 1")
+    assert.Equal(t, now.Add(time.Minute*30), qNew.RepeatTime, "Для вопроса без расписания RepeatTime должно быть +30 минут от текущего времени")
+}
+
+func Test_usecase_import_when_group_not_owned_question_is_skipped_with_error(t *testing.T) {
+    qOwned := &Question{UserId: 1, GroupId: 2}
+    qForeign := &Question{UserId: 1, GroupId: 3}
+
+    groups := &groupsMock{}
+    groups.On("IsOwned", mock.Anything, uint64(2), uint64(1)).Return(true, nil)
+    groups.On("IsOwned", mock.Anything, uint64(3), uint64(1)).Return(false, nil)
+    groups.On("Scheduler", mock.Anything, uint64(2), uint64(1)).Return("", nil)
+    dao := &daoMock{}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Create", mock.Anything, qOwned).Return(nil)
+    u := usecase{dao: dao, groups: groups}
+
+    rowErrs, errResult := u.Import(context.Background(), []*Question{qForeign, qOwned})
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, rowErrs, 2, "Ошибки должны возвращаться для каждой строки")
+    assert.ErrorIs(t, rowErrs[0], ErrGroupNotFound, "Для вопроса с чужой группой должна вернуться ErrGroupNotFound")
+    assert.Nil(t, rowErrs[1], "Для вопроса со своей группой ошибка должна быть пустой")
+    dao.AssertNotCalled(t, "Create", mock.Anything, qForeign)
+}
+
+func Test_usecase_import_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Create", mock.Anything, mock.Anything).Return(daoErr)
+    u := usecase{dao: dao, groups: ownedGroups()}
+
+    _, errResult := u.Import(context.Background(), []*Question{{UserId: 1, GroupId: 2}, {UserId: 1, GroupId: 2}})
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+    dao.AssertNumberOfCalls(t, "Create", 1)
+}
+
+// ---------------
+// ---- Batch ----
+// ---------------
+
+func ownedQuery(id uint64) *Query {
+    return NewQuery().Eq(FieldId, id).Eq(FieldUserId, uint64(1)).Paginate(1, 0)
+}
+
+func Test_usecase_batch_execute_operations_in_transaction(t *testing.T) {
+    qAdd := &Question{GroupId: 2, Title: "New"}
+    qCorrect := &[]Question{{ID: 3, UserId: 1, GroupId: 2, Title: "Old", Version: 1}}
+    qDelete := &[]Question{{ID: 4, UserId: 1, GroupId: 2, Version: 2}}
+    ops := []BatchOperation{
+        {Action: BatchAdd, Question: qAdd},
+        {Action: BatchCorrect, ID: 3, Version: 1, Question: &Question{GroupId: 2, Title: "Corrected"}},
+        {Action: BatchDelete, ID: 4},
+    }
+
+    dao := &daoMock{}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Create", mock.Anything, qAdd).Return(nil)
+    dao.On("Find", mock.Anything, ownedQuery(3)).Return(qCorrect, false, nil)
+    dao.On("Find", mock.Anything, ownedQuery(4)).Return(qDelete, false, nil)
+    dao.On("Update", mock.Anything, &(*qCorrect)[0], mock.Anything).Return(nil)
+    dao.On("Delete", mock.Anything, NewQuery().Eq(FieldId, uint64(4)).Eq(FieldUserId, uint64(1)).Eq(FieldVersion, uint64(2))).Return(nil)
+    u := usecase{dao: dao, groups: ownedGroups()}
+
+    results, errResult := u.Batch(context.Background(), 1, ops)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, results, 3, "Результат должен возвращаться для каждой операции")
+    assert.Equal(t, uint64(1), results[0].Question.UserId, "Новый вопрос должен принадлежать пользователю пакета")
+    assert.Equal(t, "Corrected", results[1].Question.Title, "Исправленный вопрос должен содержать новые данные")
+    assert.Nil(t, results[2].Question, "Для удаления вопрос должен быть пустым")
+    dao.AssertNumberOfCalls(t, "WithTx", 1)
+    dao.AssertNumberOfCalls(t, "Delete", 1)
+}
+
+func Test_usecase_batch_answer_use_found_question_version(t *testing.T) {
+    ql := &[]Question{{ID: 3, UserId: 1, GroupId: 2, Step: 1, Version: 5}}
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, ownedQuery(3)).Return(ql, false, nil)
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, uint64(3)).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(nil)
+    u := usecase{dao: dao, reviewDao: reviewDao, groups: ownedGroups()}
+
+    results, errResult := u.Batch(context.Background(), 1, []BatchOperation{{Action: BatchAnswer, ID: 3, Grade: GradeGood}})
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, results[0].Err, "Ошибка операции должна быть пустой")
+    assert.Equal(t, uint8(2), results[0].Question.Step, "Вопрос должен перейти на следующую ступень")
+}
+
+func Test_usecase_batch_when_question_not_owned_operation_is_skipped_with_error(t *testing.T) {
+    qAdd := &Question{GroupId: 2}
+
+    dao := &daoMock{}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, ownedQuery(3)).Return(&[]Question{}, false, nil)
+    dao.On("Create", mock.Anything, qAdd).Return(nil)
+    u := usecase{dao: dao, groups: ownedGroups()}
+
+    results, errResult := u.Batch(context.Background(), 1, []BatchOperation{
+        {Action: BatchDelete, ID: 3},
+        {Action: BatchAdd, Question: qAdd},
+    })
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.ErrorIs(t, results[0].Err, ErrQuestionNotFound, "Для чужого вопроса должна вернуться ErrQuestionNotFound")
+    assert.Nil(t, results[1].Err, "Остальные операции должны выполниться")
+    dao.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func Test_usecase_batch_when_version_differs_operation_is_skipped_with_version_conflict(t *testing.T) {
+    ql := &[]Question{{ID: 3, UserId: 1, GroupId: 2, Version: 2}}
+
+    dao := &daoMock{}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, ownedQuery(3)).Return(ql, false, nil)
+    u := usecase{dao: dao}
+
+    results, errResult := u.Batch(context.Background(), 1, []BatchOperation{{Action: BatchDelete, ID: 3, Version: 1}})
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.ErrorIs(t, results[0].Err, ErrVersionConflict, "Для другой версии должна вернуться ErrVersionConflict")
+    dao.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func Test_usecase_batch_when_question_changed_before_delete_operation_is_skipped_with_version_conflict(t *testing.T) {
+    ql := &[]Question{{ID: 3, UserId: 1, GroupId: 2, Version: 2}}
+
+    dao := &daoMock{}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, ownedQuery(3)).Return(ql, false, nil)
+    dao.On("Delete", mock.Anything, NewQuery().Eq(FieldId, uint64(3)).Eq(FieldUserId, uint64(1)).Eq(FieldVersion, uint64(2))).Return(ErrVersionConflict)
+    u := usecase{dao: dao}
+
+    results, errResult := u.Batch(context.Background(), 1, []BatchOperation{{Action: BatchDelete, ID: 3, Version: 2}})
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.ErrorIs(t, results[0].Err, ErrVersionConflict, "Если вопрос изменился до удаления, должна вернуться ErrVersionConflict")
+}
+
+func Test_usecase_batch_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, mock.Anything).Return(&[]Question{}, false, daoErr)
+    u := usecase{dao: dao}
+
+    results, errResult := u.Batch(context.Background(), 1, []BatchOperation{{Action: BatchDelete, ID: 3}, {Action: BatchDelete, ID: 4}})
+
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+    assert.Nil(t, results, "При ошибке хранилища результаты должны быть пустыми")
+    dao.AssertNumberOfCalls(t, "Find", 1)
+}
+
 // -----------------
 // ---- Correct ----
 // -----------------
 
-var correctFields = []string{QuestionGroupId, questionTitle, questionBody}
+var correctFields = []string{QuestionGroupId, QuestionTitle, QuestionBody}
 
 func Test_usecase_correct_dao_calls_is_correct(t *testing.T) {
     qIn := &Question{}
 
     dao := &daoMock{}
-    dao.On("Update", qIn, correctFields).Return(nil)
-    u := usecase{dao: dao}
+    dao.On("Update", mock.Anything, qIn, correctFields).Return(nil)
+    u := usecase{dao: dao, groups: ownedGroups()}
 
-    _ = u.Correct(qIn)
+    _ = u.Correct(context.Background(), qIn)
 
     updateCalls := 1
     if !dao.AssertNumberOfCalls(t, "Update", updateCalls) {
@@ -162,14 +489,27 @@ func Test_usecase_correct_dao_calls_is_correct(t *testing.T) {
     }
 }
 
+func Test_usecase_correct_when_tags_are_set_tags_are_updated(t *testing.T) {
+    qIn := &Question{Tags: []Tag{}}
+
+    dao := &daoMock{}
+    dao.On("Update", mock.Anything, qIn, append(correctFields, QuestionTags)).Return(nil)
+    u := usecase{dao: dao, groups: ownedGroups()}
+
+    errResult := u.Correct(context.Background(), qIn)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    dao.AssertExpectations(t)
+}
+
 func Test_usecase_correct_when_dao_work_success_result_error_is_empty(t *testing.T) {
     qIn := &Question{}
 
     dao := &daoMock{}
-    dao.On("Update", qIn, correctFields).Return(nil)
-    u := usecase{dao: dao}
+    dao.On("Update", mock.Anything, qIn, correctFields).Return(nil)
+    u := usecase{dao: dao, groups: ownedGroups()}
 
-    errResult := u.Correct(qIn)
+    errResult := u.Correct(context.Background(), qIn)
 
     assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
 }
@@ -179,10 +519,10 @@ func Test_usecase_correct_dao_work_wrong_result_error_not_empty_and_have_info_fr
     daoErr := errors.New("Dao mock error")
 
     dao := &daoMock{}
-    dao.On("Update", qIn, correctFields).Return(daoErr)
-    u := usecase{dao: dao}
+    dao.On("Update", mock.Anything, qIn, correctFields).Return(daoErr)
+    u := usecase{dao: dao, groups: ownedGroups()}
 
-    errResult := u.Correct(qIn)
+    errResult := u.Correct(context.Background(), qIn)
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
     require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
@@ -192,29 +532,95 @@ func Test_usecase_correct_when_dao_work_success_result_question_contains_data_fr
     qIn := &Question{Title: "Title 1"}
 
     dao := &daoMock{}
-    dao.On("Update", qIn, correctFields).Return(nil).Run(func(args mock.Arguments) {
-        qOut := args.Get(0).(*Question)
+    dao.On("Update", mock.Anything, qIn, correctFields).Return(nil).Run(func(args mock.Arguments) {
+        qOut := args.Get(1).(*Question)
         qOut.Title = "Title 2"
     })
-    u := usecase{dao: dao}
+    u := usecase{dao: dao, groups: ownedGroups()}
 
-    _ = u.Correct(qIn)
+    _ = u.Correct(context.Background(), qIn)
 
     assert.Equal(t, "Title 2", qIn.Title, "Результирующий объект question должен иметь изменения, внесенные в него в dao")
 }
 
+// ---------------
+// ---- Patch ----
+// ---------------
+
+func Test_usecase_patch_update_only_passed_fields_without_group_check(t *testing.T) {
+    qIn := &Question{Body: ""}
+    fields := []string{QuestionBody}
+
+    groups := &groupsMock{}
+    dao := &daoMock{}
+    dao.On("Update", mock.Anything, qIn, fields).Return(nil)
+    u := usecase{dao: dao, groups: groups}
+
+    errResult := u.Patch(context.Background(), qIn, fields)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    dao.AssertExpectations(t)
+    groups.AssertNotCalled(t, "IsOwned", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_usecase_patch_when_group_not_owned_result_error_is_group_not_found(t *testing.T) {
+    qIn := &Question{UserId: 1, GroupId: 2}
+
+    groups := &groupsMock{}
+    groups.On("IsOwned", mock.Anything, uint64(2), uint64(1)).Return(false, nil)
+    dao := &daoMock{}
+    u := usecase{dao: dao, groups: groups}
+
+    errResult := u.Patch(context.Background(), qIn, []string{QuestionTitle, QuestionGroupId})
+
+    require.ErrorIs(t, errResult, ErrGroupNotFound, "Возвращаемая ошибка должна быть ErrGroupNotFound")
+    dao.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_usecase_patch_when_field_not_editable_result_error_is_field_not_editable(t *testing.T) {
+    dao := &daoMock{}
+    u := usecase{dao: dao}
+
+    errResult := u.Patch(context.Background(), &Question{}, []string{QuestionTitle, QuestionRepeatTime})
+
+    require.ErrorIs(t, errResult, ErrFieldNotEditable, "Возвращаемая ошибка должна быть ErrFieldNotEditable")
+    dao.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_usecase_patch_without_fields_dao_is_not_called(t *testing.T) {
+    dao := &daoMock{}
+    u := usecase{dao: dao}
+
+    errResult := u.Patch(context.Background(), &Question{}, []string{})
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    dao.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_usecase_patch_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(daoErr)
+    u := usecase{dao: dao}
+
+    errResult := u.Patch(context.Background(), &Question{}, []string{QuestionTitle})
+
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
 // ----------------
 // ---- Delete ----
 // ----------------
 
 func Test_usecase_delete_dao_calls_is_correct(t *testing.T) {
-    conds := []interface{}{"id=?", 1}
+    query := NewQuery().Eq(FieldId, uint64(1))
 
     dao := &daoMock{}
-    dao.On("Delete", conds...).Return(nil)
+    dao.On("Delete", mock.Anything, query).Return(nil)
     u := usecase{dao: dao}
 
-    _ = u.Delete(conds)
+    _ = u.Delete(context.Background(), query)
 
     deleteCalls := 1
     if !dao.AssertNumberOfCalls(t, "Delete", deleteCalls) {
@@ -224,26 +630,467 @@ func Test_usecase_delete_dao_calls_is_correct(t *testing.T) {
 }
 
 func Test_usecase_delete_when_dao_work_success_result_error_is_empty(t *testing.T) {
-    conds := []interface{}{"id=?", 1}
+    query := NewQuery().Eq(FieldId, uint64(1))
 
     dao := &daoMock{}
-    dao.On("Delete", conds...).Return(nil)
+    dao.On("Delete", mock.Anything, query).Return(nil)
     u := usecase{dao: dao}
 
-    errResult := u.Delete(conds)
+    errResult := u.Delete(context.Background(), query)
 
     assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
 }
 
 func Test_usecase_delete_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
-    conds := []interface{}{"id=?", 1}
+    query := NewQuery().Eq(FieldId, uint64(1))
     daoErr := errors.New("Dao mock error")
 
     dao := &daoMock{}
-    dao.On("Delete", conds...).Return(daoErr)
+    dao.On("Delete", mock.Anything, query).Return(daoErr)
     u := usecase{dao: dao}
 
-    errResult := u.Delete(conds)
+    errResult := u.Delete(context.Background(), query)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+// ---------------
+// ---- Trash ----
+// ---------------
+
+func Test_usecase_trash_dao_calls_is_correct(t *testing.T) {
+    query := NewQuery().Eq(FieldUserId, uint64(1)).OrderBy(FieldTitle, false).Paginate(2, 0)
+    trashQuery := NewQuery().Eq(FieldUserId, uint64(1)).
+        OrderBy(FieldDeletedAt, true).OrderBy(FieldId, true).Paginate(2, 0)
+
+    dao := &daoMock{}
+    dao.On("FindDeleted", mock.Anything, trashQuery).Return(&[]Question{}, false, nil)
+    u := usecase{dao: dao}
+
+    _, _, errResult := u.Trash(context.Background(), query)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    dao.AssertNumberOfCalls(t, "FindDeleted", 1)
+    assert.Equal(t, []Sort{{Field: FieldTitle}}, query.Sort, "Исходный запрос не должен меняться")
+}
+
+func Test_usecase_trash_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("FindDeleted", mock.Anything, mock.Anything).Return(&[]Question{}, false, daoErr)
+    u := usecase{dao: dao}
+
+    _, _, errResult := u.Trash(context.Background(), NewQuery())
+
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_usecase_restore_dao_calls_is_correct(t *testing.T) {
+    qIn := &Question{ID: 3, UserId: 1, GroupId: 2}
+
+    groups := &groupsMock{}
+    groups.On("Exists", mock.Anything, uint64(2), uint64(1)).Return(true, nil)
+    groups.On("Restore", mock.Anything, uint64(2), uint64(1)).Return(true, nil)
+    dao := &daoMock{}
+    dao.On("Restore", mock.Anything, qIn).Return(nil)
+    u := usecase{dao: dao, groups: groups}
+
+    errResult := u.Restore(context.Background(), qIn)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    groups.AssertNumberOfCalls(t, "Restore", 1)
+    dao.AssertNumberOfCalls(t, "Restore", 1)
+}
+
+func Test_usecase_restore_when_group_not_found_result_error_is_group_not_found(t *testing.T) {
+    qIn := &Question{ID: 3, UserId: 1, GroupId: 2}
+
+    groups := &groupsMock{}
+    groups.On("Exists", mock.Anything, uint64(2), uint64(1)).Return(false, nil)
+    dao := &daoMock{}
+    u := usecase{dao: dao, groups: groups}
+
+    errResult := u.Restore(context.Background(), qIn)
+
+    require.ErrorIs(t, errResult, ErrGroupNotFound, "Возвращаемая ошибка должна быть ErrGroupNotFound")
+    dao.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+    groups.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_usecase_restore_dao_work_wrong_result_error_not_empty_and_group_is_not_restored(t *testing.T) {
+    qIn := &Question{ID: 3, UserId: 1, GroupId: 2}
+
+    groups := &groupsMock{}
+    groups.On("Exists", mock.Anything, uint64(2), uint64(1)).Return(true, nil)
+    dao := &daoMock{}
+    dao.On("Restore", mock.Anything, qIn).Return(ErrVersionConflict)
+    u := usecase{dao: dao, groups: groups}
+
+    errResult := u.Restore(context.Background(), qIn)
+
+    require.ErrorIs(t, errResult, ErrVersionConflict, "Возвращаемая ошибка должна содержать информацию из dao")
+    groups.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_usecase_restore_when_group_not_restored_question_is_moved_back_to_trash(t *testing.T) {
+    qIn := &Question{ID: 3, UserId: 1, GroupId: 2, Version: 4}
+    groupsErr := errors.New("Groups mock error")
+
+    groups := &groupsMock{}
+    groups.On("Exists", mock.Anything, uint64(2), uint64(1)).Return(true, nil)
+    groups.On("Restore", mock.Anything, uint64(2), uint64(1)).Return(false, groupsErr)
+    dao := &daoMock{}
+    dao.On("Restore", mock.Anything, qIn).Return(nil).Run(func(args mock.Arguments) {
+        args.Get(1).(*Question).Version++
+    })
+    trashQuery := NewQuery().Eq(FieldId, uint64(3)).Eq(FieldUserId, uint64(1)).Eq(FieldVersion, uint64(5))
+    dao.On("Delete", mock.Anything, trashQuery).Return(nil)
+    u := usecase{dao: dao, groups: groups}
+
+    errResult := u.Restore(context.Background(), qIn)
+
+    require.ErrorIs(t, errResult, groupsErr, "Возвращаемая ошибка должна содержать информацию из groups")
+    dao.AssertCalled(t, "Delete", mock.Anything, trashQuery)
+}
+
+func Test_usecase_purge_delete_questions_older_than_retention(t *testing.T) {
+    now := time.Now()
+
+    dao := &daoMock{}
+    dao.On("Purge", mock.Anything, now.Add(-time.Hour)).Return(int64(2), nil)
+    u := usecase{dao: dao, now: now}
+
+    count, errResult := u.Purge(context.Background(), time.Hour)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, int64(2), count, "Количество удаленных вопросов должно приходить из dao")
+}
+
+func Test_usecase_purge_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Purge", mock.Anything, mock.Anything).Return(int64(0), daoErr)
+    u := usecase{dao: dao}
+
+    _, errResult := u.Purge(context.Background(), time.Hour)
+
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+// ----------------
+// ---- Answer ----
+// ----------------
+
+var answerFields = scheduleFields
+
+func Test_usecase_answer_dao_calls_is_correct(t *testing.T) {
+    id := uint64(1)
+    ql := &[]Question{{ID: id, Step: 1}}
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(nil)
+    u := usecase{dao: dao, reviewDao: reviewDao, groups: ownedGroups()}
+
+    _, _ = u.Answer(context.Background(), id, 0, GradeGood, time.Second)
+
+    findCalls := 1
+    if !dao.AssertNumberOfCalls(t, "Find", findCalls) {
+        t.Errorf("Метод Find у dao должен вызваться %d раз", findCalls)
+        t.Fail()
+    }
+    updateCalls := 1
+    if !dao.AssertNumberOfCalls(t, "Update", updateCalls) {
+        t.Errorf("Метод Update у dao должен вызваться %d раз", updateCalls)
+        t.Fail()
+    }
+}
+
+func Test_usecase_answer_when_dao_work_success_result_error_is_empty(t *testing.T) {
+    id := uint64(1)
+    ql := &[]Question{{ID: id, Step: 1}}
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(nil)
+    u := usecase{dao: dao, reviewDao: reviewDao, groups: ownedGroups()}
+
+    _, errResult := u.Answer(context.Background(), id, 0, GradeGood, time.Second)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+}
+
+func Test_usecase_answer_when_question_not_found_result_question_is_empty(t *testing.T) {
+    id := uint64(1)
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(&[]Question{}, false, nil)
+    u := usecase{dao: dao, reviewDao: reviewDao}
+
+    qResult, errResult := u.Answer(context.Background(), id, 0, GradeGood, time.Second)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Nil(t, qResult, "Результирующий объект question должен быть пустым")
+    dao.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_usecase_answer_dao_find_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    id := uint64(1)
+    daoErr := errors.New("Dao mock error")
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(&[]Question{}, false, daoErr)
+    u := usecase{dao: dao, reviewDao: reviewDao}
+
+    _, errResult := u.Answer(context.Background(), id, 0, GradeGood, time.Second)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_usecase_answer_dao_update_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    id := uint64(1)
+    ql := &[]Question{{ID: id, Step: 1}}
+    daoErr := errors.New("Dao mock error")
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(daoErr)
+    u := usecase{dao: dao, reviewDao: reviewDao, groups: ownedGroups()}
+
+    _, errResult := u.Answer(context.Background(), id, 0, GradeGood, time.Second)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_usecase_answer_when_version_differs_result_error_is_version_conflict_and_question_not_updated(t *testing.T) {
+    id := uint64(1)
+    ql := &[]Question{{ID: id, Step: 1, Version: 3}}
+
+    reviewDao := &reviewDaoMock{}
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    u := usecase{dao: dao, reviewDao: reviewDao}
+
+    _, errResult := u.Answer(context.Background(), id, 2, GradeGood, time.Second)
+
+    require.ErrorIs(t, errResult, ErrVersionConflict, "Возвращаемая ошибка должна быть ErrVersionConflict")
+    dao.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+    reviewDao.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_usecase_answer_when_version_match_question_is_updated(t *testing.T) {
+    id := uint64(1)
+    ql := &[]Question{{ID: id, Step: 1, Version: 3}}
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(nil)
+    u := usecase{dao: dao, reviewDao: reviewDao, groups: ownedGroups()}
+
+    _, errResult := u.Answer(context.Background(), id, 3, GradeGood, time.Second)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+}
+
+func Test_usecase_answer_when_good_question_moves_to_next_step(t *testing.T) {
+    now := time.Now()
+    id := uint64(1)
+    ql := &[]Question{{ID: id, Step: 1, IsFailed: true}}
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(nil)
+    u := usecase{
+        dao:       dao,
+        reviewDao: reviewDao,
+        groups:    ownedGroups(),
+        now:       now,
+    }
+
+    qResult, _ := u.Answer(context.Background(), id, 0, GradeGood, time.Second)
+
+    assert.Equal(t, uint8(2), qResult.Step, "Step должен быть 2")
+    assert.Equal(t, false, qResult.IsFailed, "Флаг IsFailed должен быть false")
+    assert.Equal(t, now.Add(time.Hour*24*14), qResult.RepeatTime, "RepeatTime должно быть +14 дней от текущего времени")
+}
+
+func Test_usecase_answer_when_good_on_last_step_question_stays_on_last_step(t *testing.T) {
+    now := time.Now()
+    id := uint64(1)
+    ql := &[]Question{{ID: id, Step: maxStep}}
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(nil)
+    u := usecase{
+        dao:       dao,
+        reviewDao: reviewDao,
+        groups:    ownedGroups(),
+        now:       now,
+    }
+
+    qResult, _ := u.Answer(context.Background(), id, 0, GradeGood, time.Second)
+
+    assert.Equal(t, uint8(maxStep), qResult.Step, "Step не должен превышать последний шаг")
+    assert.Equal(t, now.Add(time.Hour*24*90), qResult.RepeatTime, "RepeatTime должно быть +90 дней от текущего времени")
+}
+
+func Test_usecase_answer_when_again_question_resets_to_first_step_and_marks_failed(t *testing.T) {
+    now := time.Now()
+    id := uint64(1)
+    ql := &[]Question{{ID: id, Step: 3}}
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(nil)
+    u := usecase{
+        dao:       dao,
+        reviewDao: reviewDao,
+        groups:    ownedGroups(),
+        now:       now,
+    }
+
+    qResult, _ := u.Answer(context.Background(), id, 0, GradeAgain, time.Second)
+
+    assert.Equal(t, uint8(1), qResult.Step, "Step должен быть 1")
+    assert.Equal(t, true, qResult.IsFailed, "Флаг IsFailed должен быть true")
+    assert.Equal(t, now.Add(time.Minute*30), qResult.RepeatTime, "RepeatTime должно быть +30 минут от текущего времени")
+}
+
+func Test_usecase_answer_use_scheduler_of_question_group(t *testing.T) {
+    now := time.Now()
+    id := uint64(1)
+    ql := &[]Question{{ID: id, UserId: 3, GroupId: 2, Step: 1}}
+    schedulers, _ := NewSchedulerResolver(SchedulerLadder)
+    groups := &groupsMock{}
+    groups.On("Scheduler", mock.Anything, uint64(2), uint64(3)).Return(SchedulerSm2, nil)
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(nil)
+    u := usecase{
+        dao:        dao,
+        reviewDao:  reviewDao,
+        groups:     groups,
+        schedulers: schedulers,
+        now:        now,
+    }
+
+    qResult, _ := u.Answer(context.Background(), id, 0, GradeGood, time.Second)
+
+    assert.Equal(t, now.Add(time.Hour*24), qResult.RepeatTime, "RepeatTime должно быть рассчитано алгоритмом SM-2 группы вопроса")
+}
+
+func Test_usecase_answer_update_question_and_create_review_in_one_transaction(t *testing.T) {
+    id := uint64(1)
+    ql := &[]Question{{ID: id, Step: 1}}
+
+    txReviewDao := &reviewDaoMock{}
+    txReviewDao.On("Create", mock.Anything, mock.Anything).Return(nil)
+    reviewDao := &reviewDaoMock{}
+    dao := &daoMock{reviewDao: txReviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(nil)
+    u := usecase{dao: dao, reviewDao: reviewDao, groups: ownedGroups()}
+
+    _, errResult := u.Answer(context.Background(), id, 0, GradeGood, time.Second)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    dao.AssertNumberOfCalls(t, "WithTx", 1)
+    txReviewDao.AssertNumberOfCalls(t, "Create", 1)
+    reviewDao.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_usecase_answer_review_dao_calls_is_correct(t *testing.T) {
+    now := time.Now()
+    id := uint64(1)
+    repeatTime := now.Add(-time.Hour)
+    ql := &[]Question{{ID: id, UserId: 2, Step: 1, RepeatTime: repeatTime}}
+    rExpected := &Review{
+        QuestionId:     id,
+        UserId:         2,
+        Grade:          GradeGood,
+        PrevStep:       1,
+        NewStep:        2,
+        PrevRepeatTime: repeatTime,
+        NewRepeatTime:  now.Add(time.Hour * 24 * 14),
+        AnsweredAt:     now,
+        ResponseTime:   1500,
+    }
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, rExpected).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(nil)
+    u := usecase{
+        dao:       dao,
+        reviewDao: reviewDao,
+        groups:    ownedGroups(),
+        now:       now,
+    }
+
+    _, _ = u.Answer(context.Background(), id, 0, GradeGood, time.Millisecond*1500)
+
+    createCalls := 1
+    if !reviewDao.AssertNumberOfCalls(t, "Create", createCalls) {
+        t.Errorf("Метод Create у review dao должен вызваться %d раз", createCalls)
+        t.Fail()
+    }
+}
+
+func Test_usecase_answer_review_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    id := uint64(1)
+    ql := &[]Question{{ID: id, Step: 1}}
+    daoErr := errors.New("Dao mock error")
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", mock.Anything, mock.Anything).Return(daoErr)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", mock.Anything).Return()
+    dao.On("Find", mock.Anything, NewQuery().Eq(FieldId, id).Paginate(1, 0)).Return(ql, false, nil)
+    dao.On("Update", mock.Anything, &(*ql)[0], answerFields).Return(nil)
+    u := usecase{dao: dao, reviewDao: reviewDao, groups: ownedGroups()}
+
+    _, errResult := u.Answer(context.Background(), id, 0, GradeGood, time.Second)
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
     require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
@@ -255,16 +1102,13 @@ func Test_usecase_delete_dao_work_wrong_result_error_not_empty_and_have_info_fro
 
 func Test_usecase_find_dao_calls_is_correct(t *testing.T) {
     var ql *[]Question
-    conds := &map[string]interface{}{"id": 1}
-    order := &[]interface{}{"id desc"}
-    limit := 1
-    offset := 1
+    query := NewQuery().Eq(FieldId, uint64(1)).OrderBy(FieldId, true).Paginate(1, 1)
 
     dao := &daoMock{}
-    dao.On("Find", conds, order, limit, offset).Return(ql, false, nil)
+    dao.On("Find", mock.Anything, query).Return(ql, false, nil)
     u := usecase{dao: dao}
 
-    _, _, _ = u.Find(conds, order, limit, offset)
+    _, _, _ = u.Find(context.Background(), query)
 
     findCalls := 1
     if !dao.AssertNumberOfCalls(t, "Find", findCalls) {
@@ -275,16 +1119,13 @@ func Test_usecase_find_dao_calls_is_correct(t *testing.T) {
 
 func Test_usecase_find_when_dao_work_success_result_error_is_empty(t *testing.T) {
     var ql *[]Question
-    conds := &map[string]interface{}{"id": 1}
-    order := &[]interface{}{"id desc"}
-    limit := 1
-    offset := 1
+    query := NewQuery().Eq(FieldId, uint64(1)).OrderBy(FieldId, true).Paginate(1, 1)
 
     dao := &daoMock{}
-    dao.On("Find", conds, order, limit, offset).Return(ql, false, nil)
+    dao.On("Find", mock.Anything, query).Return(ql, false, nil)
     u := usecase{dao: dao}
 
-    _, _, errResult := u.Find(conds, order, limit, offset)
+    _, _, errResult := u.Find(context.Background(), query)
 
     assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
 }
@@ -292,16 +1133,13 @@ func Test_usecase_find_when_dao_work_success_result_error_is_empty(t *testing.T)
 func Test_usecase_find_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
     daoErr := errors.New("Dao mock error")
     var ql *[]Question
-    conds := &map[string]interface{}{"id": 1}
-    order := &[]interface{}{"id desc"}
-    limit := 1
-    offset := 1
+    query := NewQuery().Eq(FieldId, uint64(1)).OrderBy(FieldId, true).Paginate(1, 1)
 
     dao := &daoMock{}
-    dao.On("Find", conds, order, limit, offset).Return(ql, false, daoErr)
+    dao.On("Find", mock.Anything, query).Return(ql, false, daoErr)
     u := usecase{dao: dao}
 
-    _, _, errResult := u.Find(conds, order, limit, offset)
+    _, _, errResult := u.Find(context.Background(), query)
 
     require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
     require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
@@ -310,16 +1148,292 @@ func Test_usecase_find_dao_work_wrong_result_error_not_empty_and_have_info_from_
 func Test_usecase_find_when_dao_work_success_result_is_data_from_dao(t *testing.T) {
     qlExpected := &[]Question{{ID: 1}, {ID: 2}, {ID: 3}}
     moreExpected := true
-    conds := &map[string]interface{}{}
-    order := &[]interface{}{}
-    limit := 1
-    offset := 1
+    query := NewQuery().Paginate(1, 1)
 
     dao := &daoMock{}
-    dao.On("Find", conds, order, limit, offset).Return(qlExpected, moreExpected, nil)
+    dao.On("Find", mock.Anything, query).Return(qlExpected, moreExpected, nil)
     u := usecase{dao: dao}
 
-    qlResult, moreResult, _ := u.Find(conds, order, limit, offset)
+    qlResult, moreResult, _ := u.Find(context.Background(), query)
+    assert.Equal(t, qlExpected, qlResult, "Возвращаемый список объектов question отличается от того, который вернул dao")
+    assert.Equal(t, moreExpected, moreResult, "Возвращаемый флаг more отличается от того, который вернул dao")
+}
+
+// -------------
+// ---- Due ----
+// -------------
+
+func Test_usecase_due_dao_calls_is_correct(t *testing.T) {
+    now := time.Now()
+    query := NewQuery().In(FieldGroupId, []uint64{1}).OrderBy(FieldId, true).Paginate(1, 1)
+    dueQuery := NewQuery().In(FieldGroupId, []uint64{1}).Lte(FieldRepeatTime, now).
+        OrderBy(FieldRepeatTime, false).OrderBy(FieldId, false).Paginate(1, 1)
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, dueQuery).Return(&[]Question{}, false, nil)
+    u := usecase{
+        dao: dao,
+        now: now,
+    }
+
+    _, _, _ = u.Due(context.Background(), query)
+
+    findCalls := 1
+    if !dao.AssertNumberOfCalls(t, "Find", findCalls) {
+        t.Errorf("Метод Find у dao должен вызваться %d раз", findCalls)
+        t.Fail()
+    }
+}
+
+func Test_usecase_due_query_is_not_changed(t *testing.T) {
+    query := NewQuery().Eq(FieldUserId, uint64(1)).Paginate(2, 0)
+    expected := query.Clone()
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, mock.Anything).Return(&[]Question{}, false, nil)
+    u := usecase{dao: dao, now: time.Now()}
+
+    _, _, _ = u.Due(context.Background(), query)
+
+    assert.Equal(t, expected, query, "Запрос вызывающего кода не должен меняться")
+}
+
+func Test_usecase_due_when_dao_work_success_result_error_is_empty(t *testing.T) {
+    now := time.Now()
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, mock.Anything).Return(&[]Question{}, false, nil)
+    u := usecase{
+        dao: dao,
+        now: now,
+    }
+
+    _, _, errResult := u.Due(context.Background(), NewQuery())
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+}
+
+func Test_usecase_due_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    now := time.Now()
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, mock.Anything).Return(&[]Question{}, false, daoErr)
+    u := usecase{
+        dao: dao,
+        now: now,
+    }
+
+    _, _, errResult := u.Due(context.Background(), NewQuery())
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+func Test_usecase_due_when_dao_work_success_result_is_data_from_dao(t *testing.T) {
+    now := time.Now()
+    qlExpected := &[]Question{{ID: 1}, {ID: 2}}
+    moreExpected := true
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, mock.Anything).Return(qlExpected, moreExpected, nil)
+    u := usecase{
+        dao: dao,
+        now: now,
+    }
+
+    qlResult, moreResult, _ := u.Due(context.Background(), NewQuery().Paginate(2, 0))
+
     assert.Equal(t, qlExpected, qlResult, "Возвращаемый список объектов question отличается от того, который вернул dao")
     assert.Equal(t, moreExpected, moreResult, "Возвращаемый флаг more отличается от того, который вернул dao")
 }
+
+// ----------------------
+// ---- CountByGroup ----
+// ----------------------
+
+func Test_usecase_count_by_group_when_dao_work_success_result_is_data_from_dao(t *testing.T) {
+    now := time.Now()
+    query := NewQuery().Eq(FieldUserId, uint64(1))
+    countsExpected := &[]GroupCount{{GroupId: 1, Total: 3, Due: 1}}
+
+    dao := &daoMock{}
+    dao.On("CountByGroup", mock.Anything, query, now).Return(countsExpected, nil)
+    u := usecase{dao: dao, now: now}
+
+    countsResult, errResult := u.CountByGroup(context.Background(), query)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, countsExpected, countsResult, "Возвращаемое количество отличается от того, которое вернул dao")
+}
+
+func Test_usecase_count_by_group_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    now := time.Now()
+    daoErr := errors.New("Dao mock error")
+    query := NewQuery()
+
+    dao := &daoMock{}
+    dao.On("CountByGroup", mock.Anything, query, now).Return(&[]GroupCount{}, daoErr)
+    u := usecase{dao: dao, now: now}
+
+    _, errResult := u.CountByGroup(context.Background(), query)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+// --------------------------
+// ---- DescendantGroups ----
+// --------------------------
+
+func Test_usecase_descendant_groups_when_groups_work_success_result_is_data_from_groups(t *testing.T) {
+    groups := &groupsMock{}
+    groups.On("Descendants", mock.Anything, []uint64{1}, uint64(2)).Return([]uint64{1, 3, 4}, nil)
+    u := usecase{groups: groups}
+
+    idsResult, errResult := u.DescendantGroups(context.Background(), []uint64{1}, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []uint64{1, 3, 4}, idsResult, "Возвращаемый список групп отличается от того, который вернули groups")
+}
+
+func Test_usecase_descendant_groups_groups_work_wrong_result_error_not_empty_and_have_info_from_groups(t *testing.T) {
+    groupsErr := errors.New("Groups mock error")
+
+    groups := &groupsMock{}
+    groups.On("Descendants", mock.Anything, []uint64{1}, uint64(2)).Return([]uint64{}, groupsErr)
+    u := usecase{groups: groups}
+
+    _, errResult := u.DescendantGroups(context.Background(), []uint64{1}, 2)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, groupsErr, "Возвращаемая ошибка должна содержать информацию из groups")
+}
+
+// --------------------
+// ---- GroupNames ----
+// --------------------
+
+func Test_usecase_group_names_when_groups_work_success_result_is_data_from_groups(t *testing.T) {
+    groups := &groupsMock{}
+    groups.On("Names", mock.Anything, []uint64{1}, uint64(2)).Return(map[uint64]string{1: "Subject"}, nil)
+    u := usecase{groups: groups}
+
+    namesResult, errResult := u.GroupNames(context.Background(), []uint64{1}, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, map[uint64]string{1: "Subject"}, namesResult, "Возвращаемые имена отличаются от тех, которые вернули groups")
+}
+
+func Test_usecase_group_names_groups_work_wrong_result_error_not_empty_and_have_info_from_groups(t *testing.T) {
+    groupsErr := errors.New("Groups mock error")
+
+    groups := &groupsMock{}
+    groups.On("Names", mock.Anything, []uint64{1}, uint64(2)).Return(map[uint64]string{}, groupsErr)
+    u := usecase{groups: groups}
+
+    _, errResult := u.GroupNames(context.Background(), []uint64{1}, 2)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, groupsErr, "Возвращаемая ошибка должна содержать информацию из groups")
+}
+
+// ----------------
+// ---- Search ----
+// ----------------
+
+func Test_usecase_search_when_dao_work_success_result_is_data_from_dao(t *testing.T) {
+    query := NewQuery().Eq(FieldUserId, uint64(1)).Paginate(1, 2)
+    ql := &[]Question{{ID: 1}}
+
+    dao := &daoMock{}
+    dao.On("Search", mock.Anything, "word", query).Return(ql, true, nil)
+    u := usecase{dao: dao}
+
+    listResult, moreResult, errResult := u.Search(context.Background(), "word", query)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, ql, listResult, "Возвращаемый список отличается от того, который вернул dao")
+    assert.True(t, moreResult, "Флаг more должен браться из dao")
+}
+
+func Test_usecase_search_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+    query := NewQuery()
+
+    dao := &daoMock{}
+    dao.On("Search", mock.Anything, "word", query).Return(&[]Question{}, false, daoErr)
+    u := usecase{dao: dao}
+
+    _, _, errResult := u.Search(context.Background(), "word", query)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+// ----------------------
+// ---- EnsureGroups ----
+// ----------------------
+
+func Test_usecase_ensure_groups_when_groups_work_success_result_is_data_from_groups(t *testing.T) {
+    groups := &groupsMock{}
+    groups.On("Ensure", mock.Anything, []string{"Subject::Topic"}, uint64(2)).Return(map[string]uint64{"Subject::Topic": 3}, []uint64{3}, nil)
+    u := usecase{groups: groups}
+
+    idsResult, createdResult, errResult := u.EnsureGroups(context.Background(), []string{"Subject::Topic"}, 2)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, map[string]uint64{"Subject::Topic": 3}, idsResult, "Возвращаемые id отличаются от тех, которые вернули groups")
+    assert.Equal(t, []uint64{3}, createdResult, "Id созданных групп отличаются от тех, которые вернули groups")
+}
+
+func Test_usecase_ensure_groups_groups_work_wrong_result_error_not_empty_and_have_info_from_groups(t *testing.T) {
+    groupsErr := errors.New("Groups mock error")
+
+    groups := &groupsMock{}
+    groups.On("Ensure", mock.Anything, []string{"Subject"}, uint64(2)).Return(map[string]uint64{}, []uint64{}, groupsErr)
+    u := usecase{groups: groups}
+
+    _, _, errResult := u.EnsureGroups(context.Background(), []string{"Subject"}, 2)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, groupsErr, "Возвращаемая ошибка должна содержать информацию из groups")
+}
+
+func Test_usecase_remove_groups_groups_work_wrong_result_error_not_empty_and_have_info_from_groups(t *testing.T) {
+    groupsErr := errors.New("Groups mock error")
+
+    groups := &groupsMock{}
+    groups.On("Remove", mock.Anything, []uint64{3}, uint64(2)).Return(groupsErr)
+    u := usecase{groups: groups}
+
+    errResult := u.RemoveGroups(context.Background(), []uint64{3}, 2)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, groupsErr, "Возвращаемая ошибка должна содержать информацию из groups")
+}
+
+// -----------------
+// ---- Context ----
+// -----------------
+
+type ctxKey string
+
+func Test_usecase_answer_pass_context_to_dao_and_review_dao(t *testing.T) {
+    ctx := context.WithValue(context.Background(), ctxKey("request"), "answer")
+    qIn := Question{ID: 1, Version: 1}
+
+    reviewDao := &reviewDaoMock{}
+    reviewDao.On("Create", ctx, mock.Anything).Return(nil)
+    dao := &daoMock{reviewDao: reviewDao}
+    dao.On("WithTx", ctx).Return()
+    dao.On("Find", ctx, mock.Anything).Return(&[]Question{qIn}, false, nil)
+    dao.On("Update", ctx, mock.Anything, mock.Anything).Return(nil)
+    u := usecase{dao: dao, reviewDao: reviewDao, groups: ownedGroups()}
+
+    _, errResult := u.Answer(ctx, qIn.ID, 0, GradeGood, time.Second)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    dao.AssertCalled(t, "Update", ctx, mock.Anything, mock.Anything)
+    reviewDao.AssertCalled(t, "Create", ctx, mock.Anything)
+}
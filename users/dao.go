@@ -0,0 +1,9 @@
+package users
+
+import "context"
+
+type Dao interface {
+    // Метод возвращает ErrEmailTaken, если пользователь с таким email уже есть
+    Create(ctx context.Context, u *User) error
+    Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]User, more bool, err error)
+}
@@ -0,0 +1,168 @@
+package gin
+
+import (
+    "context"
+    "net/http"
+
+    rest_api_response_formatter "github.com/chudoyoudo/rest-api-response-formatter"
+    "github.com/gin-gonic/gin"
+    "github.com/golobby/container"
+    "github.com/pkg/errors"
+
+    auth_gin "github.com/chudoyoudo/remember-cards/auth/gin"
+    "github.com/chudoyoudo/remember-cards/users"
+
+    errors_formatter "github.com/chudoyoudo/errors-formatter"
+    log "github.com/sirupsen/logrus"
+)
+
+// Регистрация и вход доступны без токена,
+// middleware применяется только к маршрутам, которым нужен пользователь
+func RegisterHandlers(r *gin.Engine, middleware ...gin.HandlerFunc) {
+    v1 := r.Group("/v1")
+    v1.POST("/user/register", registerHandler)
+    v1.POST("/user/login", loginHandler)
+    v1.Group("", middleware...).GET("/user/me", profileHandler)
+}
+
+type registerData struct {
+    Email    string `json:"email" binding:"required,email"`
+    Password string `json:"password" binding:"required,min=8"`
+    Name     string `json:"name"`
+}
+
+func (d *registerData) Bind(u *users.User) {
+    u.Email = d.Email
+    u.Name = d.Name
+}
+
+type loginData struct {
+    Email    string `json:"email" binding:"required"`
+    Password string `json:"password" binding:"required"`
+}
+
+func registerHandler(c *gin.Context) {
+    d := &registerData{}
+    if err := c.Bind(d); err != nil {
+        errData := errors_formatter.FormatErrors(err)
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    u := &users.User{}
+    d.Bind(u)
+    uc := getUsecase()
+    err := registerUser(c.Request.Context(), uc, u, d.Password)
+    if errors.Is(err, users.ErrEmailTaken) {
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &map[string][]string{
+            "email": {users.ErrEmailTaken.Error()},
+        })
+        c.Negotiate(http.StatusConflict, *getNegotiate(response))
+        return
+    }
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't register user"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    response := rest_api_response_formatter.GetResponseData(*u, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+func loginHandler(c *gin.Context) {
+    d := &loginData{}
+    if err := c.Bind(d); err != nil {
+        errData := errors_formatter.FormatErrors(err)
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &errData)
+        c.Negotiate(http.StatusBadRequest, *getNegotiate(response))
+        return
+    }
+
+    uc := getUsecase()
+    token, err := loginUser(c.Request.Context(), uc, d.Email, d.Password)
+    if errors.Is(err, users.ErrInvalidCredentials) {
+        response := rest_api_response_formatter.GetResponseData(&struct{}{}, &map[string][]string{
+            "credentials": {users.ErrInvalidCredentials.Error()},
+        })
+        c.Negotiate(http.StatusUnauthorized, *getNegotiate(response))
+        return
+    }
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't login user"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    response := rest_api_response_formatter.GetResponseData(gin.H{
+        "token": token,
+    }, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+func profileHandler(c *gin.Context) {
+    userId, found := auth_gin.UserId(c)
+    if !found {
+        c.AbortWithStatus(http.StatusUnauthorized)
+        return
+    }
+
+    uc := getUsecase()
+    u, err := getUser(c.Request.Context(), uc, userId)
+    if err != nil {
+        log.Error(errors.Wrap(err, "Can't get user"))
+        c.AbortWithStatus(http.StatusInternalServerError)
+        return
+    }
+
+    if nil == u {
+        c.AbortWithStatus(http.StatusNotFound)
+        return
+    }
+
+    response := rest_api_response_formatter.GetResponseData(*u, &map[string][]string{})
+    c.Negotiate(http.StatusOK, *getNegotiate(response))
+}
+
+func registerUser(ctx context.Context, uc users.Usecase, u *users.User, password string) error {
+    err := uc.Register(ctx, u, password)
+    if err != nil {
+        return errors.Wrapf(err, "Can't register user via usecase")
+    }
+    return nil
+}
+
+func loginUser(ctx context.Context, uc users.Usecase, email, password string) (string, error) {
+    token, err := uc.Login(ctx, email, password)
+    if err != nil {
+        return "", errors.Wrapf(err, "Can't login user via usecase")
+    }
+    return token, nil
+}
+
+func getUser(ctx context.Context, uc users.Usecase, id uint64) (*users.User, error) {
+    ul, _, err := uc.Find(ctx, &map[string]interface{}{"id": id}, &[]interface{}{}, 1, 0)
+    if err != nil {
+        return nil, errors.Wrapf(err, "Can't get user by id %d via usecase", id)
+    }
+
+    if len(*ul) == 0 {
+        return nil, err
+    }
+
+    return &(*ul)[0], nil
+}
+
+func getNegotiate(data *gin.H) *gin.Negotiate {
+    return &gin.Negotiate{
+        Offered: []string{gin.MIMEJSON, gin.MIMEXML},
+        Data:    data,
+    }
+}
+
+func getUsecase() users.Usecase {
+    var uc users.Usecase
+    container.Make(&uc)
+    return uc
+}
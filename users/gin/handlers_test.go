@@ -0,0 +1,138 @@
+package gin
+
+import (
+    "context"
+    "testing"
+
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/users"
+)
+
+type usecaseMock struct {
+    mock.Mock
+}
+
+func (m *usecaseMock) Register(ctx context.Context, u *users.User, password string) error {
+    args := m.Called(ctx, u, password)
+    return args.Error(0)
+}
+
+func (m *usecaseMock) Login(ctx context.Context, email, password string) (string, error) {
+    args := m.Called(ctx, email, password)
+    return args.String(0), args.Error(1)
+}
+
+func (m *usecaseMock) Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]users.User, more bool, err error) {
+    args := m.Called(ctx, conds, order, limit, offset)
+    return args.Get(0).(*[]users.User), args.Bool(1), args.Error(2)
+}
+
+//----------------
+//--- Register ---
+//----------------
+
+func Test_handler_register_usecase_calls_is_correct(t *testing.T) {
+    uIn := &users.User{}
+
+    uc := &usecaseMock{}
+    uc.On("Register", mock.Anything, uIn, "password").Return(nil)
+
+    _ = registerUser(context.Background(), uc, uIn, "password")
+
+    registerCalls := 1
+    if !uc.AssertNumberOfCalls(t, "Register", registerCalls) {
+        t.Errorf("Метод Register у usecase должен вызваться %d раз", registerCalls)
+        t.Fail()
+    }
+}
+
+func Test_handler_register_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    uIn := &users.User{}
+
+    uc := &usecaseMock{}
+    uc.On("Register", mock.Anything, uIn, "password").Return(users.ErrEmailTaken)
+
+    errResult := registerUser(context.Background(), uc, uIn, "password")
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, users.ErrEmailTaken, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+//-------------
+//--- Login ---
+//-------------
+
+func Test_handler_login_when_usecase_work_success_result_is_token_from_usecase(t *testing.T) {
+    uc := &usecaseMock{}
+    uc.On("Login", mock.Anything, "user@example.com", "password").Return("token", nil)
+
+    token, errResult := loginUser(context.Background(), uc, "user@example.com", "password")
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, "token", token, "Токен должен быть идентичен тому, что вернул usecase")
+}
+
+func Test_handler_login_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    uc := &usecaseMock{}
+    uc.On("Login", mock.Anything, "user@example.com", "wrong").Return("", users.ErrInvalidCredentials)
+
+    _, errResult := loginUser(context.Background(), uc, "user@example.com", "wrong")
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, users.ErrInvalidCredentials, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+//---------------
+//--- Profile ---
+//---------------
+
+func Test_handler_profile_when_usecase_work_success_result_user_contains_data_from_usecase(t *testing.T) {
+    uExpected := &users.User{ID: 1, Email: "user@example.com"}
+
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, &map[string]interface{}{"id": uint64(1)}, &[]interface{}{}, 1, 0).Return(&[]users.User{*uExpected}, false, nil)
+
+    uResult, errResult := getUser(context.Background(), uc, 1)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, *uExpected, *uResult, "Результирующий объект user должен быть идентичен тому, что вернул usecase")
+}
+
+func Test_handler_profile_when_user_not_found_result_user_is_empty(t *testing.T) {
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, &map[string]interface{}{"id": uint64(1)}, &[]interface{}{}, 1, 0).Return(&[]users.User{}, false, nil)
+
+    uResult, errResult := getUser(context.Background(), uc, 1)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Nil(t, uResult, "Результирующий объект user должен быть пустым")
+}
+
+func Test_handler_profile_usecase_work_wrong_result_error_not_empty_and_have_info_from_usecase(t *testing.T) {
+    usecaseErr := errors.New("Usecase mock error")
+
+    uc := &usecaseMock{}
+    uc.On("Find", mock.Anything, &map[string]interface{}{"id": uint64(1)}, &[]interface{}{}, 1, 0).Return(&[]users.User{}, false, usecaseErr)
+
+    _, errResult := getUser(context.Background(), uc, 1)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, usecaseErr, "Возвращаемая ошибка должна содержать информацию из usecase")
+}
+
+//---------------------
+//--- Register data ---
+//---------------------
+
+func Test_register_data_bind_retern_correct_user_object(t *testing.T) {
+    d := &registerData{Email: "user@example.com", Password: "password", Name: "User"}
+    u := &users.User{}
+
+    d.Bind(u)
+
+    assert.Equal(t, users.User{Email: "user@example.com", Name: "User"}, *u, "Результирующий объект user неверный")
+}
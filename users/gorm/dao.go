@@ -0,0 +1,74 @@
+package gorm
+
+import (
+	"context"
+
+	gorm "github.com/chudoyoudo/gorm-interface"
+	"github.com/golobby/container"
+	"github.com/pkg/errors"
+	gorm_db "gorm.io/gorm"
+
+	"github.com/chudoyoudo/remember-cards/connection"
+	"github.com/chudoyoudo/remember-cards/users"
+)
+
+type dao struct {
+	c  gorm.Connection
+	db *gorm_db.DB
+}
+
+func (dao *dao) Create(ctx context.Context, u *users.User) error {
+	result := dao.getConnection(ctx).Create(u)
+	err := result.Error()
+	if connection.IsUniqueViolation(err) {
+		return errors.Wrapf(users.ErrEmailTaken, "Can't create user with email %s via connection: %v", u.Email, err)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Can't create user with email %s via connection", u.Email)
+	}
+	return nil
+}
+
+func (dao *dao) Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]users.User, more bool, err error) {
+	ul := []users.User{}
+	c := dao.getConnection(ctx)
+
+	if limit > 0 {
+		c = c.Limit(limit + 1)
+	}
+
+	if offset > 0 {
+		c = c.Offset(offset)
+	}
+
+	if len(*order) > 0 {
+		for _, o := range *order {
+			c = c.Order(o)
+		}
+	}
+
+	result := c.Find(&ul, *conds)
+
+	err = result.Error()
+	if err != nil {
+		return &ul, false, errors.Wrapf(err, "Can't find user via connection by conds %v", conds)
+	}
+
+	more = false
+	if limit > 0 && len(ul) >= limit+1 {
+		ul = ul[:limit]
+		more = true
+	}
+
+	return &ul, more, nil
+}
+
+func (dao *dao) getConnection(ctx context.Context) gorm.Connection {
+	if dao.c == nil {
+		if dao.db == nil {
+			container.Make(&dao.db)
+		}
+		return connection.New(dao.db.WithContext(ctx))
+	}
+	return dao.c
+}
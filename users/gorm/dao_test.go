@@ -0,0 +1,134 @@
+package gorm
+
+import (
+    "context"
+    "testing"
+
+    gorm "github.com/chudoyoudo/gorm-interface"
+    "github.com/mattn/go-sqlite3"
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/users"
+)
+
+// ----------------
+// ---- Create ----
+// ----------------
+
+func Test_dao_create_connection_calls_is_correct(t *testing.T) {
+    uIn := &users.User{}
+
+    c := &gorm.ConnectionMock{}
+    c.On("Create", uIn).Return(c)
+    dao := &dao{c: c}
+
+    _ = dao.Create(context.Background(), uIn)
+
+    createCalls := 1
+    if !c.AssertNumberOfCalls(t, "Create", createCalls) {
+        t.Errorf("Метод Create у connection должен вызваться %d раз", createCalls)
+        t.Fail()
+    }
+}
+
+func Test_dao_create_when_connection_work_success_we_have_correct_result_user(t *testing.T) {
+    uIn := &users.User{}
+
+    c := &gorm.ConnectionMock{}
+    c.On("Create", uIn).Return(&gorm.ConnectionMock{}).Run(func(args mock.Arguments) {
+        uIn := args.Get(0).(*users.User)
+        uIn.ID = 1
+    })
+    dao := &dao{c: c}
+
+    errResult := dao.Create(context.Background(), uIn)
+
+    assert.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, uint64(1), uIn.ID, "Результируещий объект user должен содержать данные, пришедшие из connection")
+}
+
+func Test_dao_create_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
+    uIn := &users.User{}
+    connectionErr := errors.New("Connection mock error")
+
+    c := &gorm.ConnectionMock{}
+    c.On("Create", uIn).Return(&gorm.ConnectionMock{Err: connectionErr})
+    dao := &dao{c: c}
+
+    errResult := dao.Create(context.Background(), uIn)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
+}
+
+func Test_dao_create_when_email_is_not_unique_result_error_is_email_taken(t *testing.T) {
+    uIn := &users.User{Email: "user@example.com"}
+    uniqueErr := sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}
+
+    c := &gorm.ConnectionMock{}
+    c.On("Create", uIn).Return(&gorm.ConnectionMock{Err: uniqueErr})
+    dao := &dao{c: c}
+
+    errResult := dao.Create(context.Background(), uIn)
+
+    assert.ErrorIs(t, errResult, users.ErrEmailTaken, "Возвращаемая ошибка должна быть ErrEmailTaken")
+}
+
+// --------------
+// ---- Find ----
+// --------------
+
+func Test_dao_find_connection_calls_is_correct(t *testing.T) {
+    conds := &map[string]interface{}{users.UserEmail: "user@example.com"}
+    order := &[]interface{}{"id desc"}
+    limit := 1
+    offset := 1
+
+    c := &gorm.ConnectionMock{}
+    c.On("Limit", limit+1).Return(c)
+    c.On("Offset", offset).Return(c)
+    c.On("Order", "id desc").Return(c)
+    c.On("Find", &[]users.User{}, []interface{}{*conds}).Return(c)
+    dao := &dao{c: c}
+
+    _, _, _ = dao.Find(context.Background(), conds, order, limit, offset)
+
+    c.AssertExpectations(t)
+}
+
+func Test_dao_find_when_we_have_more_then_limit_records_in_connection_result_more_is_true(t *testing.T) {
+    conds := &map[string]interface{}{}
+    order := &[]interface{}{}
+    limit := 1
+
+    c := &gorm.ConnectionMock{}
+    c.On("Limit", limit+1).Return(c)
+    c.On("Find", &[]users.User{}, []interface{}{*conds}).Return(&gorm.ConnectionMock{}).Run(func(args mock.Arguments) {
+        ulOut := args.Get(0).(*[]users.User)
+        *ulOut = append(*ulOut, users.User{ID: 1}, users.User{ID: 2})
+    })
+    dao := &dao{c: c}
+
+    ulResult, resultMore, _ := dao.Find(context.Background(), conds, order, limit, 0)
+
+    assert.Equal(t, true, resultMore, "Возвращаемый more флаг должно быть true")
+    assert.Equal(t, limit, len(*ulResult), "Лишние объекты user, использовавшиеся для вычисления флага more, должны быть убраны из возвращаемого списка объектов")
+}
+
+func Test_dao_find_when_connection_work_wrong_result_error_not_empty_and_have_info_from_connection(t *testing.T) {
+    conds := &map[string]interface{}{}
+    order := &[]interface{}{}
+    connectionErr := errors.New("Connection mock error")
+
+    c := &gorm.ConnectionMock{}
+    c.On("Find", &[]users.User{}, []interface{}{*conds}).Return(&gorm.ConnectionMock{Err: connectionErr})
+    dao := &dao{c: c}
+
+    _, _, errResult := dao.Find(context.Background(), conds, order, 0, 0)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    assert.ErrorIs(t, errResult, connectionErr, "Возвращаемая ошибка должна содержать информацию из connection")
+}
@@ -0,0 +1,13 @@
+package gorm
+
+import (
+    "github.com/golobby/container"
+
+    "github.com/chudoyoudo/remember-cards/users"
+)
+
+func init() {
+    container.Transient(func() users.Dao {
+        return &dao{}
+    })
+}
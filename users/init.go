@@ -0,0 +1,11 @@
+package users
+
+import (
+    "github.com/golobby/container"
+)
+
+func init() {
+    container.Transient(func() Usecase {
+        return &usecase{}
+    })
+}
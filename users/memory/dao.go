@@ -0,0 +1,76 @@
+package memory
+
+import (
+    "context"
+    "sort"
+    "sync"
+
+    "github.com/pkg/errors"
+
+    "github.com/chudoyoudo/remember-cards/memory"
+    "github.com/chudoyoudo/remember-cards/users"
+)
+
+type dao struct {
+    mu     sync.Mutex
+    users  map[uint64]users.User
+    lastId uint64
+}
+
+func newDao() *dao {
+    return &dao{users: map[uint64]users.User{}}
+}
+
+// Email пользователя уникален, как по индексу в хранилище gorm
+func (dao *dao) Create(ctx context.Context, u *users.User) error {
+    dao.mu.Lock()
+    defer dao.mu.Unlock()
+
+    for _, existing := range dao.users {
+        if existing.Email == u.Email {
+            return errors.Wrapf(users.ErrEmailTaken, "Can't create user with email %s", u.Email)
+        }
+    }
+
+    dao.lastId++
+    u.ID = dao.lastId
+    dao.users[u.ID] = *u
+    return nil
+}
+
+func (dao *dao) Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]users.User, more bool, err error) {
+    dao.mu.Lock()
+    defer dao.mu.Unlock()
+
+    ul := []users.User{}
+    for _, u := range dao.sorted() {
+        found, err := memory.Match(&u, *conds)
+        if err != nil {
+            return &[]users.User{}, false, errors.Wrapf(err, "Can't match user by conds %v", conds)
+        }
+        if found {
+            ul = append(ul, u)
+        }
+    }
+
+    err = memory.Sort(ul, *order)
+    if err != nil {
+        return &[]users.User{}, false, errors.Wrapf(err, "Can't sort users by order %v", order)
+    }
+
+    from, to, more := memory.Page(len(ul), limit, offset)
+    ul = ul[from:to]
+    return &ul, more, nil
+}
+
+// Метод возвращает пользователей по возрастанию id, как их без сортировки вернула бы база
+func (dao *dao) sorted() []users.User {
+    ul := make([]users.User, 0, len(dao.users))
+    for _, u := range dao.users {
+        ul = append(ul, u)
+    }
+    sort.Slice(ul, func(i, j int) bool {
+        return ul[i].ID < ul[j].ID
+    })
+    return ul
+}
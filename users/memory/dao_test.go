@@ -0,0 +1,50 @@
+package memory
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+
+    "github.com/chudoyoudo/remember-cards/users"
+)
+
+func Test_dao_create_set_id_of_user(t *testing.T) {
+    dao := newDao()
+    u1, u2 := &users.User{Email: "first@example.com"}, &users.User{Email: "second@example.com"}
+
+    errFirst := dao.Create(context.Background(), u1)
+    errSecond := dao.Create(context.Background(), u2)
+
+    require.Nil(t, errFirst, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errSecond, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, []uint64{1, 2}, []uint64{u1.ID, u2.ID}, "Пользователи должны получить id по порядку")
+}
+
+func Test_dao_create_when_email_taken_result_error_is_email_taken(t *testing.T) {
+    dao := newDao()
+    require.Nil(t, dao.Create(context.Background(), &users.User{Email: "user@example.com"}))
+
+    errResult := dao.Create(context.Background(), &users.User{Email: "user@example.com"})
+
+    assert.ErrorIs(t, errResult, users.ErrEmailTaken, "Возвращаемая ошибка должна быть ErrEmailTaken")
+}
+
+func Test_dao_find_filter_by_conds_and_paginate(t *testing.T) {
+    dao := newDao()
+    for _, email := range []string{"first@example.com", "second@example.com", "third@example.com"} {
+        require.Nil(t, dao.Create(context.Background(), &users.User{Email: email}))
+    }
+
+    byEmail, moreByEmail, errByEmail := dao.Find(context.Background(), &map[string]interface{}{users.UserEmail: "second@example.com"}, &[]interface{}{}, 1, 0)
+    page, morePage, errPage := dao.Find(context.Background(), &map[string]interface{}{}, &[]interface{}{}, 2, 0)
+
+    require.Nil(t, errByEmail, "Возвращаемая ошибка должна быть пустой")
+    require.Nil(t, errPage, "Возвращаемая ошибка должна быть пустой")
+    require.Len(t, *byEmail, 1, "Пользователь должен найтись по email")
+    assert.Equal(t, uint64(2), (*byEmail)[0].ID, "Найден не тот пользователь")
+    assert.False(t, moreByEmail, "Других пользователей с этим email нет")
+    assert.Len(t, *page, 2, "Количество пользователей на странице неверное")
+    assert.True(t, morePage, "После страницы есть еще пользователи")
+}
@@ -0,0 +1,15 @@
+package memory
+
+import (
+    "github.com/golobby/container"
+
+    "github.com/chudoyoudo/remember-cards/users"
+)
+
+// Метод регистрирует в контейнере dao пользователей, которое хранит данные в памяти процесса
+func Register() {
+    d := newDao()
+    container.Singleton(func() users.Dao {
+        return d
+    })
+}
@@ -0,0 +1,143 @@
+package users
+
+import (
+    "context"
+    "strings"
+    "time"
+
+    "github.com/golobby/container"
+    "github.com/pkg/errors"
+    "golang.org/x/crypto/bcrypt"
+
+    "github.com/chudoyoudo/remember-cards/auth"
+)
+
+var (
+    ErrEmailTaken         = errors.New("Email is already taken")
+    ErrInvalidCredentials = errors.New("Invalid email or password")
+)
+
+type Usecase interface {
+    Register(ctx context.Context, u *User, password string) error
+    Login(ctx context.Context, email, password string) (token string, err error)
+    Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]User, more bool, err error)
+}
+
+type usecase struct {
+    dao    Dao
+    tokens auth.Tokens
+    cost   int
+    now    time.Time
+}
+
+// Метод сохраняет пользователя с хэшем пароля.
+// Если email уже занят, возвращается ErrEmailTaken. Проверка email до хэширования пароля
+// не защищает от одновременной регистрации, поэтому ErrEmailTaken возвращается
+// и по ошибке уникальности от dao
+func (uc *usecase) Register(ctx context.Context, u *User, password string) error {
+    u.Email = normalizeEmail(u.Email)
+
+    existing, err := uc.findByEmail(ctx, u.Email)
+    if err != nil {
+        return errors.Wrapf(err, "Can't check email %s", u.Email)
+    }
+    if existing != nil {
+        return ErrEmailTaken
+    }
+
+    hash, err := bcrypt.GenerateFromPassword([]byte(password), uc.getCost())
+    if err != nil {
+        return errors.Wrap(err, "Can't hash password")
+    }
+
+    u.PasswordHash = string(hash)
+    u.CreatedAt = uc.getNow()
+
+    err = uc.getDao().Create(ctx, u)
+    if errors.Is(err, ErrEmailTaken) {
+        u.PasswordHash = ""
+        return ErrEmailTaken
+    }
+    if err != nil {
+        u.PasswordHash = ""
+        return errors.Wrap(err, "Can't create user via dao")
+    }
+
+    return nil
+}
+
+// Метод проверяет пароль и выпускает токен доступа.
+// Если пользователь не найден или пароль неверный, возвращается ErrInvalidCredentials
+func (uc *usecase) Login(ctx context.Context, email, password string) (string, error) {
+    u, err := uc.findByEmail(ctx, normalizeEmail(email))
+    if err != nil {
+        return "", errors.Wrapf(err, "Can't find user by email %s", email)
+    }
+    if u == nil {
+        return "", ErrInvalidCredentials
+    }
+
+    err = bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
+    if err != nil {
+        return "", ErrInvalidCredentials
+    }
+
+    token, err := uc.getTokens().Issue(u.ID)
+    if err != nil {
+        return "", errors.Wrapf(err, "Can't issue token for user %d", u.ID)
+    }
+    return token, nil
+}
+
+func (uc *usecase) Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]User, more bool, err error) {
+    dao := uc.getDao()
+    list, more, err = dao.Find(ctx, conds, order, limit, offset)
+    if err != nil {
+        return list, more, errors.Wrapf(err, "Can't find user via dao by conds %v", conds)
+    }
+    return list, more, err
+}
+
+func (uc *usecase) findByEmail(ctx context.Context, email string) (*User, error) {
+    ul, _, err := uc.getDao().Find(ctx, &map[string]interface{}{UserEmail: email}, &[]interface{}{}, 1, 0)
+    if err != nil {
+        return nil, err
+    }
+    if len(*ul) == 0 {
+        return nil, nil
+    }
+    return &(*ul)[0], nil
+}
+
+func (uc *usecase) getDao() Dao {
+    if uc.dao == nil {
+        container.Make(&uc.dao)
+    }
+    return uc.dao
+}
+
+func (uc *usecase) getTokens() auth.Tokens {
+    if uc.tokens == nil {
+        container.Make(&uc.tokens)
+    }
+    return uc.tokens
+}
+
+func (uc *usecase) getCost() int {
+    if uc.cost == 0 {
+        return bcrypt.DefaultCost
+    }
+    return uc.cost
+}
+
+func (uc *usecase) getNow() time.Time {
+    var emptyTime time.Time
+    if uc.now == emptyTime {
+        return time.Now()
+    }
+    return uc.now
+}
+
+func normalizeEmail(email string) string {
+    return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,201 @@
+package users
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/pkg/errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/mock"
+    "github.com/stretchr/testify/require"
+    "golang.org/x/crypto/bcrypt"
+
+    "github.com/chudoyoudo/remember-cards/auth"
+)
+
+type daoMock struct {
+    mock.Mock
+}
+
+func (m *daoMock) Create(ctx context.Context, u *User) error {
+    args := m.Called(ctx, u)
+    return args.Error(0)
+}
+
+func (m *daoMock) Find(ctx context.Context, conds *map[string]interface{}, order *[]interface{}, limit, offset int) (list *[]User, more bool, err error) {
+    args := m.Called(ctx, conds, order, limit, offset)
+    return args.Get(0).(*[]User), args.Bool(1), args.Error(2)
+}
+
+func emailConds(email string) *map[string]interface{} {
+    return &map[string]interface{}{UserEmail: email}
+}
+
+func hash(password string) string {
+    h, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+    return string(h)
+}
+
+// ------------------
+// ---- Register ----
+// ------------------
+
+func Test_usecase_register_dao_calls_is_correct(t *testing.T) {
+    uIn := &User{Email: "user@example.com"}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, emailConds(uIn.Email), &[]interface{}{}, 1, 0).Return(&[]User{}, false, nil)
+    dao.On("Create", mock.Anything, uIn).Return(nil)
+    uc := usecase{dao: dao, cost: bcrypt.MinCost}
+
+    _ = uc.Register(context.Background(), uIn, "password")
+
+    createCalls := 1
+    if !dao.AssertNumberOfCalls(t, "Create", createCalls) {
+        t.Errorf("Метод Create у dao должен вызваться %d раз", createCalls)
+        t.Fail()
+    }
+}
+
+func Test_usecase_register_normalize_email_and_hash_password(t *testing.T) {
+    now := time.Now()
+    uIn := &User{Email: " User@Example.com "}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, emailConds("user@example.com"), &[]interface{}{}, 1, 0).Return(&[]User{}, false, nil)
+    dao.On("Create", mock.Anything, uIn).Return(nil)
+    uc := usecase{dao: dao, cost: bcrypt.MinCost, now: now}
+
+    errResult := uc.Register(context.Background(), uIn, "password")
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, "user@example.com", uIn.Email, "Email должен быть приведен к нижнему регистру без пробелов")
+    assert.Nil(t, bcrypt.CompareHashAndPassword([]byte(uIn.PasswordHash), []byte("password")), "PasswordHash должен быть хэшем пароля")
+    assert.Equal(t, now, uIn.CreatedAt, "CreatedAt должно быть текущим временем")
+}
+
+func Test_usecase_register_when_email_taken_result_error_is_email_taken(t *testing.T) {
+    uIn := &User{Email: "user@example.com"}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, emailConds(uIn.Email), &[]interface{}{}, 1, 0).Return(&[]User{{ID: 1}}, false, nil)
+    uc := usecase{dao: dao, cost: bcrypt.MinCost}
+
+    errResult := uc.Register(context.Background(), uIn, "password")
+
+    assert.ErrorIs(t, errResult, ErrEmailTaken, "Возвращаемая ошибка должна быть ErrEmailTaken")
+    dao.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_usecase_register_when_dao_create_report_email_taken_result_error_is_email_taken(t *testing.T) {
+    uIn := &User{Email: "user@example.com"}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, emailConds(uIn.Email), &[]interface{}{}, 1, 0).Return(&[]User{}, false, nil)
+    dao.On("Create", mock.Anything, uIn).Return(errors.Wrap(ErrEmailTaken, "Dao mock error"))
+    uc := usecase{dao: dao, cost: bcrypt.MinCost}
+
+    errResult := uc.Register(context.Background(), uIn, "password")
+
+    assert.Equal(t, ErrEmailTaken, errResult, "Возвращаемая ошибка должна быть ErrEmailTaken")
+    assert.Empty(t, uIn.PasswordHash, "PasswordHash не должен оставаться в объекте после ошибки")
+}
+
+func Test_usecase_register_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    uIn := &User{Email: "user@example.com"}
+    daoErr := errors.New("Dao mock error")
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, emailConds(uIn.Email), &[]interface{}{}, 1, 0).Return(&[]User{}, false, nil)
+    dao.On("Create", mock.Anything, uIn).Return(daoErr)
+    uc := usecase{dao: dao, cost: bcrypt.MinCost}
+
+    errResult := uc.Register(context.Background(), uIn, "password")
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+    assert.Empty(t, uIn.PasswordHash, "PasswordHash не должен оставаться в объекте после ошибки")
+}
+
+// ---------------
+// ---- Login ----
+// ---------------
+
+func Test_usecase_login_when_password_is_correct_return_token_for_user(t *testing.T) {
+    tokens := auth.NewTokens([]byte("key"), time.Hour)
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, emailConds("user@example.com"), &[]interface{}{}, 1, 0).Return(&[]User{{ID: 5, PasswordHash: hash("password")}}, false, nil)
+    uc := usecase{dao: dao, tokens: tokens}
+
+    token, errResult := uc.Login(context.Background(), "User@example.com", "password")
+    userId, _ := tokens.Parse(token)
+
+    require.Nil(t, errResult, "Возвращаемая ошибка должна быть пустой")
+    assert.Equal(t, uint64(5), userId, "Токен должен быть выпущен для найденного пользователя")
+}
+
+func Test_usecase_login_when_password_is_wrong_result_error_is_invalid_credentials(t *testing.T) {
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, emailConds("user@example.com"), &[]interface{}{}, 1, 0).Return(&[]User{{ID: 5, PasswordHash: hash("password")}}, false, nil)
+    uc := usecase{dao: dao}
+
+    _, errResult := uc.Login(context.Background(), "user@example.com", "wrong")
+
+    assert.ErrorIs(t, errResult, ErrInvalidCredentials, "Возвращаемая ошибка должна быть ErrInvalidCredentials")
+}
+
+func Test_usecase_login_when_user_not_found_result_error_is_invalid_credentials(t *testing.T) {
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, emailConds("user@example.com"), &[]interface{}{}, 1, 0).Return(&[]User{}, false, nil)
+    uc := usecase{dao: dao}
+
+    _, errResult := uc.Login(context.Background(), "user@example.com", "password")
+
+    assert.ErrorIs(t, errResult, ErrInvalidCredentials, "Возвращаемая ошибка должна быть ErrInvalidCredentials")
+}
+
+func Test_usecase_login_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, emailConds("user@example.com"), &[]interface{}{}, 1, 0).Return(&[]User{}, false, daoErr)
+    uc := usecase{dao: dao}
+
+    _, errResult := uc.Login(context.Background(), "user@example.com", "password")
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
+
+// --------------
+// ---- Find ----
+// --------------
+
+func Test_usecase_find_when_dao_work_success_result_is_data_from_dao(t *testing.T) {
+    ulExpected := &[]User{{ID: 1}}
+    conds := &map[string]interface{}{"id": 1}
+    order := &[]interface{}{}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, conds, order, 1, 0).Return(ulExpected, false, nil)
+    uc := usecase{dao: dao}
+
+    ulResult, _, _ := uc.Find(context.Background(), conds, order, 1, 0)
+
+    assert.Equal(t, ulExpected, ulResult, "Возвращаемый список объектов user отличается от того, который вернул dao")
+}
+
+func Test_usecase_find_dao_work_wrong_result_error_not_empty_and_have_info_from_dao(t *testing.T) {
+    daoErr := errors.New("Dao mock error")
+    conds := &map[string]interface{}{"id": 1}
+    order := &[]interface{}{}
+
+    dao := &daoMock{}
+    dao.On("Find", mock.Anything, conds, order, 1, 0).Return(&[]User{}, false, daoErr)
+    uc := usecase{dao: dao}
+
+    _, _, errResult := uc.Find(context.Background(), conds, order, 1, 0)
+
+    require.NotNil(t, errResult, "Возвращаемая ошибка не должна быть пустой")
+    require.ErrorIs(t, errResult, daoErr, "Возвращаемая ошибка должна содержать информацию из dao")
+}
@@ -0,0 +1,15 @@
+package users
+
+import "time"
+
+const (
+    UserEmail = "email"
+)
+
+type User struct {
+    ID           uint64    `json:"id" gorm:"primaryKey"`
+    Email        string    `json:"email" gorm:"uniqueIndex"`
+    Name         string    `json:"name"`
+    PasswordHash string    `json:"-" gorm:"column:passwordHash"`
+    CreatedAt    time.Time `json:"createdAt" gorm:"column:createdAt"`
+}